@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/axlenote/axlenote-backend/internal/handlers"
 	"github.com/axlenote/axlenote-backend/internal/notification"
 	"github.com/axlenote/axlenote-backend/internal/repository"
 	"github.com/axlenote/axlenote-backend/internal/scheduler"
+	"github.com/axlenote/axlenote-backend/internal/storage"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
@@ -44,6 +50,27 @@ func main() {
 		"db/migrations/002_expanded_schema.sql",
 		"db/migrations/003_ensure_schema_columns.sql",
 		"db/migrations/004_documents_and_reminders.sql",
+		"db/migrations/005_users_and_subscriptions.sql",
+		"db/migrations/006_notification_templates.sql",
+		"db/migrations/007_job_runs.sql",
+		"db/migrations/008_document_versions.sql",
+		"db/migrations/009_document_metadata.sql",
+		"db/migrations/010_document_thumbnails.sql",
+		"db/migrations/011_calendar_feeds.sql",
+		"db/migrations/012_webhooks.sql",
+		"db/migrations/013_odometer_readings.sql",
+		"db/migrations/014_activity.sql",
+		"db/migrations/015_fixed_costs.sql",
+		"db/migrations/016_vehicle_fuel_type.sql",
+		"db/migrations/017_insights.sql",
+		"db/migrations/018_budgets.sql",
+		"db/migrations/019_expenses.sql",
+		"db/migrations/020_loans.sql",
+		"db/migrations/021_vehicle_value.sql",
+		"db/migrations/022_vehicle_status.sql",
+		"db/migrations/023_document_alerts.sql",
+		"db/migrations/024_service_completed_date.sql",
+		"db/migrations/025_list_indexes.sql",
 	}
 
 	for _, file := range migrationFiles {
@@ -61,14 +88,31 @@ func main() {
 	}
 
 	queries := repository.New(db)
-	h := handlers.New(queries)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	// Notification & Scheduler
 	notifier := notification.New()
-	sched := scheduler.New(db, queries, notifier)
-	sched.Start()
+	store, err := storage.New()
+	if err != nil {
+		log.Fatalf("Storage: %v", err)
+	}
+	if store.Encrypted() {
+		log.Println("Storage: Encrypting stored files at rest")
+	}
+	sched := scheduler.New(db, queries, notifier, store)
+	sched.Start(ctx)
 
-	app := fiber.New()
+	h := handlers.New(db, queries, sched, store)
+
+	uploadMB, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_MB"))
+	if err != nil || uploadMB <= 0 {
+		uploadMB = 20
+	}
+	app := fiber.New(fiber.Config{
+		BodyLimit: uploadMB * 1024 * 1024,
+	})
 
 	// Middleware
 	app.Use(logger.New())
@@ -84,6 +128,7 @@ func main() {
 	api.Get("/vehicles/:id", h.GetVehicle)
 	api.Put("/vehicles/:id", h.UpdateVehicle)
 	api.Delete("/vehicles/:id", h.DeleteVehicle)
+	api.Put("/vehicles/:id/status", h.UpdateVehicleStatus)
 
 	api.Get("/vehicles/:vehicleId/services", h.ListServiceRecords)
 	api.Post("/services", h.CreateServiceRecord)
@@ -97,15 +142,132 @@ func main() {
 
 	api.Get("/vehicles/:vehicleId/reminders", h.ListReminders)
 	api.Post("/reminders", h.CreateReminder)
+	api.Put("/reminders/:id", h.UpdateReminder)
 	api.Put("/reminders/:id/complete", h.CompleteReminder)
+	api.Delete("/reminders/:id", h.DeleteReminder)
+
+	api.Get("/vehicles/:vehicleId/odometer", h.ListOdometerReadings)
+	api.Post("/vehicles/:vehicleId/odometer", h.CreateOdometerReading)
 
 	api.Get("/vehicles/:id/stats", h.GetVehicleStats)
+	api.Get("/vehicles/:id/analytics/timeseries", h.GetVehicleTimeseries)
+	api.Post("/insights/:id/dismiss", h.DismissInsight)
+	api.Get("/vehicles/:id/tco", h.GetVehicleTCO)
+	api.Get("/reports/tco", h.GetTCOReport)
+	api.Get("/analytics/compare", h.CompareVehicles)
+
+	api.Get("/vehicles/:vehicleId/fixed-costs", h.ListFixedCosts)
+	api.Post("/vehicles/:vehicleId/fixed-costs", h.CreateFixedCost)
+	api.Put("/fixed-costs/:id", h.UpdateFixedCost)
+	api.Delete("/fixed-costs/:id", h.DeleteFixedCost)
+
+	api.Get("/vehicles/:id/value", h.GetVehicleValue)
+	api.Get("/vehicles/:vehicleId/valuations", h.ListValuations)
+	api.Post("/vehicles/:vehicleId/valuations", h.CreateValuation)
+	api.Put("/valuations/:id", h.UpdateValuation)
+	api.Delete("/valuations/:id", h.DeleteValuation)
+
+	api.Get("/vehicles/:vehicleId/expenses", h.ListExpenses)
+	api.Post("/vehicles/:vehicleId/expenses", h.CreateExpense)
+	api.Get("/expenses/files/:fileId", h.GetExpenseFile)
+	api.Delete("/expenses/files/:fileId", h.DeleteExpenseFile)
+	api.Get("/expenses/:id", h.GetExpense)
+	api.Put("/expenses/:id", h.UpdateExpense)
+	api.Delete("/expenses/:id", h.DeleteExpense)
+	api.Post("/expenses/:id/files", h.UploadExpenseFile)
+
+	api.Get("/vendors", h.ListVendors)
+	api.Post("/vendors", h.CreateVendor)
+	api.Get("/vendors/:id", h.GetVendor)
+	api.Put("/vendors/:id", h.UpdateVendor)
+	api.Delete("/vendors/:id", h.DeleteVendor)
+
+	api.Get("/vehicles/:vehicleId/loans", h.ListLoans)
+	api.Post("/vehicles/:vehicleId/loans", h.CreateLoan)
+	api.Delete("/loans/payments/:paymentId", h.DeleteLoanPayment)
+	api.Get("/loans/:id", h.GetLoan)
+	api.Put("/loans/:id", h.UpdateLoan)
+	api.Delete("/loans/:id", h.DeleteLoan)
+	api.Get("/loans/:id/schedule", h.GetLoanSchedule)
+	api.Get("/loans/:id/payments", h.ListLoanPayments)
+	api.Post("/loans/:id/payments", h.CreateLoanPayment)
+
+	api.Get("/budgets", h.ListBudgets)
+	api.Post("/budgets", h.CreateBudget)
+	api.Get("/budgets/:id", h.GetBudget)
+	api.Put("/budgets/:id", h.UpdateBudget)
+	api.Delete("/budgets/:id", h.DeleteBudget)
 
 	api.Get("/vehicles/:vehicleId/documents", h.ListDocuments)
+	api.Get("/documents", h.SearchDocuments)
+	api.Get("/documents/expiring", h.ListExpiringDocuments)
 	api.Post("/documents", h.CreateDocument)
+	api.Put("/documents/:id", h.UpdateDocument)
+	api.Post("/documents/:id/renew", h.RenewDocument)
+	api.Get("/documents/:id/versions", h.ListDocumentVersions)
 	api.Delete("/documents/:id", h.DeleteDocument)
 
+	api.Post("/documents/:id/files", h.UploadDocumentFile)
+	api.Get("/documents/files/:fileId", h.GetDocumentFile)
+	api.Get("/documents/files/:fileId/thumbnail", h.GetDocumentFileThumbnail)
+	api.Delete("/documents/files/:fileId", h.DeleteDocumentFile)
+
+	api.Get("/document-categories", h.ListDocumentCategories)
+	api.Post("/document-categories", h.CreateDocumentCategory)
+	api.Delete("/document-categories/:slug", h.DeleteDocumentCategory)
+
+	api.Get("/users", h.GetUsers)
+	api.Post("/users", h.CreateUser)
+	api.Get("/users/:id", h.GetUser)
+	api.Put("/users/:id", h.UpdateUser)
+	api.Delete("/users/:id", h.DeleteUser)
+
+	api.Get("/users/:id/digest", h.PreviewDigest)
+	api.Get("/users/:id/templates", h.ListTemplates)
+	api.Put("/users/:id/templates/:kind", h.SaveTemplate)
+	api.Delete("/users/:id/templates/:kind", h.DeleteTemplate)
+	api.Get("/locales", h.GetLocales)
+
+	api.Post("/users/:id/feed-token", h.CreateFeedToken)
+	api.Delete("/users/:id/feed-token", h.DeleteFeedToken)
+	api.Get("/calendar/:token.ics", h.GetCalendarFeed)
+	api.Get("/calendar/:token/vehicles/:vehicleId.ics", h.GetVehicleCalendarFeed)
+
+	api.Get("/users/:userId/subscriptions", h.ListSubscriptions)
+	api.Post("/subscriptions", h.CreateSubscription)
+	api.Put("/subscriptions/:id", h.UpdateSubscription)
+	api.Delete("/subscriptions/:id", h.DeleteSubscription)
+
+	api.Get("/webhooks", h.ListWebhooks)
+	api.Get("/webhooks/events", h.ListWebhookEvents)
+	api.Post("/webhooks", h.CreateWebhook)
+	api.Put("/webhooks/:id", h.UpdateWebhook)
+	api.Delete("/webhooks/:id", h.DeleteWebhook)
+	api.Post("/webhooks/:id/test", h.TestWebhook)
+	api.Get("/webhooks/:id/deliveries", h.ListWebhookDeliveries)
+	api.Post("/webhooks/deliveries/:deliveryId/retry", h.RetryWebhookDelivery)
+
+	api.Get("/dashboard", h.GetDashboard)
+	api.Get("/activity", h.ListActivity)
+
 	api.Get("/config", h.GetConfig)
 
-	log.Fatal(app.Listen(":3000"))
+	admin := api.Group("/admin", handlers.AdminAuth())
+	admin.Get("/jobs", h.ListJobs)
+	admin.Get("/jobs/runs", h.ListJobRuns)
+	admin.Post("/jobs/:name/run", h.RunJob)
+
+	go func() {
+		if err := app.Listen(":3000"); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down...")
+
+	if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+		log.Printf("Error: Server shutdown: %v", err)
+	}
+	sched.Stop()
 }
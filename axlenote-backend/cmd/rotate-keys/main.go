@@ -0,0 +1,34 @@
+// Command rotate-keys re-wraps the data keys of stored files with the current master key.
+//
+// Set STORAGE_ENCRYPTION_KEY to the new key and STORAGE_ENCRYPTION_OLD_KEYS to the keys
+// being retired, run this command, then drop the old keys from the configuration.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/axlenote/axlenote-backend/internal/storage"
+)
+
+func main() {
+	encryptPlain := flag.Bool("encrypt-plain", false, "also encrypt files stored before encryption was enabled")
+	flag.Parse()
+
+	store, err := storage.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Rotating keys in %s", store.Dir)
+	stats, err := store.Rotate(*encryptPlain)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Re-wrapped %d, encrypted %d, already current %d, left plain %d, failed %d",
+		stats.Rewrapped, stats.Encrypted, stats.Current, stats.Plain, stats.Failed)
+	if stats.Failed > 0 {
+		log.Fatal("Some files could not be rotated; keep the old keys configured until they are fixed")
+	}
+}
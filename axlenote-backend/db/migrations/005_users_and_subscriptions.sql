@@ -0,0 +1,27 @@
+-- Up Migration
+
+-- Users receiving notifications
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(255),
+    ntfy_topic VARCHAR(100), -- falls back to NOTIFY_TOPIC when empty
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Notification Subscriptions: which alerts a user receives, and where
+CREATE TABLE IF NOT EXISTS notification_subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    vehicle_id INTEGER REFERENCES vehicles(id) ON DELETE CASCADE, -- NULL = all vehicles
+    channel VARCHAR(20) NOT NULL DEFAULT 'ntfy', -- 'ntfy', 'email'
+    reminder_types TEXT[] NOT NULL DEFAULT '{}', -- e.g. {Insurance,Tax}; empty = all types
+    triggers TEXT[] NOT NULL DEFAULT '{}', -- 'overdue', 'upcoming'; empty = all levels
+    lead_days INTEGER NOT NULL DEFAULT 7, -- warn this many days before a due date
+    digest VARCHAR(10) NOT NULL DEFAULT 'none', -- 'none', 'daily'
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id ON notification_subscriptions(user_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_vehicle_id ON notification_subscriptions(vehicle_id);
@@ -0,0 +1,15 @@
+-- Up Migration
+
+-- Language for notifications, see internal/messages/locales
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
+
+-- User-edited notification templates, overriding the bundled translations
+CREATE TABLE IF NOT EXISTS notification_templates (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL, -- 'reminder_overdue', 'reminder_upcoming', 'economy_drop', 'budget_threshold', 'emi_due'
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, kind)
+);
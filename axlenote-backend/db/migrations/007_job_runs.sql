@@ -0,0 +1,14 @@
+-- Up Migration
+
+-- Scheduler job run history
+CREATE TABLE IF NOT EXISTS job_runs (
+    id SERIAL PRIMARY KEY,
+    job_name VARCHAR(50) NOT NULL, -- 'reminders', 'digest', 'backup', 'cleanup'
+    trigger VARCHAR(20) NOT NULL, -- 'schedule', 'manual'
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- 'running', 'success', 'failed'
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name ON job_runs(job_name, started_at DESC);
@@ -0,0 +1,7 @@
+-- Up Migration
+
+-- Renewing a document archives the old copy and links the replacement to it
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS previous_version_id INTEGER REFERENCES documents(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_documents_expiry_date ON documents(expiry_date) WHERE archived_at IS NULL;
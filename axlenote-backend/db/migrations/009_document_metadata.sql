@@ -0,0 +1,53 @@
+-- Up Migration
+
+-- Document categories. The seeded list covers the common paperwork; more can be added at runtime.
+CREATE TABLE IF NOT EXISTS document_categories (
+    slug VARCHAR(50) PRIMARY KEY, -- e.g. 'insurance'
+    name VARCHAR(100) NOT NULL,
+    tracks_expiry BOOLEAN NOT NULL DEFAULT FALSE, -- whether documents in this category usually expire
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO document_categories (slug, name, tracks_expiry) VALUES
+    ('insurance', 'Insurance', TRUE),
+    ('registration', 'Registration', TRUE),
+    ('license', 'Driving Licence', TRUE),
+    ('pollution', 'Pollution Certificate', TRUE),
+    ('tax', 'Road Tax', TRUE),
+    ('permit', 'Permit', TRUE),
+    ('warranty', 'Warranty', TRUE),
+    ('invoice', 'Invoice', FALSE),
+    ('other', 'Other', FALSE)
+ON CONFLICT (slug) DO NOTHING;
+
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS category VARCHAR(50) REFERENCES document_categories(slug) ON UPDATE CASCADE;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS issue_date DATE;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS issuer VARCHAR(100); -- insurer, RTO, ...
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS policy_number VARCHAR(100);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS registration_number VARCHAR(50);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Files can now be uploaded instead of linked
+ALTER TABLE documents ALTER COLUMN file_url SET DEFAULT '';
+
+-- Backfill categories from the free-text type
+UPDATE documents SET category = LOWER(type)
+WHERE category IS NULL AND LOWER(type) IN (SELECT slug FROM document_categories);
+
+CREATE INDEX IF NOT EXISTS idx_documents_category ON documents(category);
+CREATE INDEX IF NOT EXISTS idx_documents_tags ON documents USING GIN (tags);
+
+-- Uploaded files attached to a document, e.g. front and back scans
+CREATE TABLE IF NOT EXISTS document_files (
+    id SERIAL PRIMARY KEY,
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    label VARCHAR(50), -- 'front', 'back', ...
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_files_document_id ON document_files(document_id);
@@ -0,0 +1,7 @@
+-- Up Migration
+
+-- Thumbnails are generated in the background and stored next to the original file
+ALTER TABLE document_files ADD COLUMN IF NOT EXISTS thumbnail_status VARCHAR(20) NOT NULL DEFAULT 'pending'; -- 'pending', 'ready', 'failed', 'unsupported'
+ALTER TABLE document_files ADD COLUMN IF NOT EXISTS thumbnail_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_document_files_thumbnail_pending ON document_files(id) WHERE thumbnail_status = 'pending';
@@ -0,0 +1,6 @@
+-- Up Migration
+
+-- Secret token for a user's calendar feed; NULL until the feed is enabled
+ALTER TABLE users ADD COLUMN IF NOT EXISTS feed_token VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_feed_token ON users(feed_token);
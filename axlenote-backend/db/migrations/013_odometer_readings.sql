@@ -0,0 +1,14 @@
+-- Up Migration
+
+-- Standalone odometer readings, e.g. reported by Home Assistant over MQTT, in addition
+-- to the readings recorded with fuel logs and service records
+CREATE TABLE IF NOT EXISTS odometer_readings (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    odometer INTEGER NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    source VARCHAR(20) NOT NULL DEFAULT 'api', -- 'api', 'mqtt'
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_odometer_readings_vehicle ON odometer_readings(vehicle_id, recorded_at DESC);
@@ -0,0 +1,8 @@
+-- Up Migration
+
+-- When a reminder was completed, for the activity timeline. Reminders completed before
+-- this migration have no timestamp and do not appear there.
+ALTER TABLE reminders ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_fuel_logs_date ON fuel_logs(date);
+CREATE INDEX IF NOT EXISTS idx_service_records_date ON service_records(date);
@@ -0,0 +1,17 @@
+-- Up Migration
+
+-- Costs of owning a vehicle that do not come from fuel logs or service records, for the
+-- cost of ownership report. Recurring costs are spread evenly over the days they cover.
+CREATE TABLE IF NOT EXISTS fixed_costs (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL, -- 'insurance', 'tax', 'financing', 'depreciation', 'other'
+    amount DECIMAL(10, 2) NOT NULL,
+    frequency VARCHAR(10) NOT NULL DEFAULT 'yearly', -- 'once', 'monthly', 'yearly'
+    start_date DATE NOT NULL,
+    end_date DATE,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_fixed_costs_vehicle_id ON fixed_costs(vehicle_id);
@@ -0,0 +1,7 @@
+-- Up Migration
+
+-- What a vehicle runs on and the unit its odometer reads in, so vehicles can be compared.
+-- A NULL distance unit means METRICS_UNIT; fuel logs record litres, or kg for CNG and
+-- kWh for electric vehicles.
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS fuel_type VARCHAR(20); -- 'petrol', 'diesel', 'lpg', 'cng', 'e85', 'electric'
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS distance_unit VARCHAR(10); -- 'km', 'miles'
@@ -0,0 +1,18 @@
+-- Up Migration
+
+-- Findings the scheduler makes about a vehicle, like a drop in fuel economy. An insight
+-- stays open until the vehicle recovers or the user dismisses it.
+CREATE TABLE IF NOT EXISTS vehicle_insights (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    kind VARCHAR(40) NOT NULL, -- 'economy_drop'
+    fuel_log_id INTEGER REFERENCES fuel_logs(id) ON DELETE SET NULL, -- latest fill when it was found
+    baseline DOUBLE PRECISION NOT NULL, -- usual economy
+    recent DOUBLE PRECISION NOT NULL, -- economy over the last few fills
+    change_percent DOUBLE PRECISION NOT NULL,
+    z_score DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_insights_vehicle_id ON vehicle_insights(vehicle_id);
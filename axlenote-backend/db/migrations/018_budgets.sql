@@ -0,0 +1,25 @@
+-- Up Migration
+
+-- Spending limits per month or year, for one vehicle or (with no vehicle) the whole
+-- garage. Subscribers are alerted as spend crosses each threshold, a percentage of the
+-- amount.
+CREATE TABLE IF NOT EXISTS budgets (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER REFERENCES vehicles(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL, -- 'total', 'fuel', 'maintenance', 'insurance', 'tax', 'financing'
+    period VARCHAR(10) NOT NULL DEFAULT 'monthly', -- 'monthly', 'yearly'
+    amount DECIMAL(10, 2) NOT NULL,
+    thresholds INTEGER[] NOT NULL DEFAULT '{80,100}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_budgets_vehicle_id ON budgets(vehicle_id);
+
+-- Thresholds already alerted on, so each is only sent once per budget period.
+CREATE TABLE IF NOT EXISTS budget_alerts (
+    budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    threshold INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (budget_id, period_start, threshold)
+);
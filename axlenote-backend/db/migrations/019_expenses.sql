@@ -0,0 +1,49 @@
+-- Up Migration
+
+-- Shops, garages, toll operators and the like that expenses are paid to.
+CREATE TABLE IF NOT EXISTS vendors (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    website TEXT,
+    phone VARCHAR(30),
+    address TEXT,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Running costs that are neither fuel nor maintenance. A recurring expense is the first
+-- of a series: the scheduler copies it every month or year on next_due_date, until
+-- recurrence_end_date if set. Copies point back at it with recurring_expense_id.
+CREATE TABLE IF NOT EXISTS expenses (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    vendor_id INTEGER REFERENCES vendors(id) ON DELETE SET NULL,
+    category VARCHAR(20) NOT NULL, -- 'toll', 'parking', 'wash', 'insurance', 'tax', 'loan', 'fine', 'other'
+    date DATE NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    odometer INTEGER,
+    description TEXT,
+    recurrence VARCHAR(10) NOT NULL DEFAULT 'none', -- 'none', 'monthly', 'yearly'
+    recurrence_end_date DATE,
+    next_due_date DATE,
+    recurring_expense_id INTEGER REFERENCES expenses(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expenses_vehicle_id ON expenses(vehicle_id, date);
+CREATE INDEX IF NOT EXISTS idx_expenses_next_due_date ON expenses(next_due_date) WHERE recurrence <> 'none';
+-- One copy per date, so a generation run that is cut short can safely be repeated
+CREATE UNIQUE INDEX IF NOT EXISTS idx_expenses_recurring ON expenses(recurring_expense_id, date);
+
+-- Receipts and other files attached to an expense, kept in the same storage as document files.
+CREATE TABLE IF NOT EXISTS expense_files (
+    id SERIAL PRIMARY KEY,
+    expense_id INTEGER NOT NULL REFERENCES expenses(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_files_expense_id ON expense_files(expense_id);
@@ -0,0 +1,42 @@
+-- Up Migration
+
+-- Loans financing a vehicle, repaid in equal monthly instalments (EMIs) from
+-- first_emi_date. The EMI is worked out from the principal, rate and tenure unless the
+-- lender's figure is given.
+CREATE TABLE IF NOT EXISTS loans (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    lender VARCHAR(100),
+    principal DECIMAL(12, 2) NOT NULL,
+    annual_rate DECIMAL(6, 3) NOT NULL, -- percent per year
+    tenure_months INTEGER NOT NULL,
+    emi DECIMAL(12, 2) NOT NULL,
+    start_date DATE NOT NULL, -- when the loan was disbursed and interest starts
+    first_emi_date DATE NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loans_vehicle_id ON loans(vehicle_id);
+
+-- Repayments made. Each pays the interest accrued since the previous one first and the
+-- rest off the principal. Prepayments reduce the balance without counting as EMIs.
+CREATE TABLE IF NOT EXISTS loan_payments (
+    id SERIAL PRIMARY KEY,
+    loan_id INTEGER NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL,
+    prepayment BOOLEAN NOT NULL DEFAULT false,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loan_payments_loan_id ON loan_payments(loan_id, date);
+
+-- EMI due dates already reminded of, so each is only sent once.
+CREATE TABLE IF NOT EXISTS loan_reminders (
+    loan_id INTEGER NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    due_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (loan_id, due_date)
+);
@@ -0,0 +1,26 @@
+-- Up Migration
+
+-- What a vehicle was bought for, and how its current value is estimated from that:
+-- 'straight_line' loses depreciation_rate percent of the purchase price each year,
+-- 'declining_balance' loses depreciation_rate percent of the remaining value each year,
+-- and 'valuations' follows the values recorded in vehicle_valuations. The rate-based
+-- estimates never drop below residual_value.
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS purchase_price DECIMAL(12, 2);
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS purchase_date DATE;
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS depreciation_model VARCHAR(20); -- 'straight_line', 'declining_balance', 'valuations'
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS depreciation_rate DECIMAL(5, 2); -- percent per year, DEPRECIATION_RATE when NULL
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS residual_value DECIMAL(12, 2);
+
+-- What a vehicle was worth on a date: a dealer quote, an insurer's declared value or a
+-- price guide lookup.
+CREATE TABLE IF NOT EXISTS vehicle_valuations (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INTEGER NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    value DECIMAL(12, 2) NOT NULL,
+    source VARCHAR(100),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_valuations_vehicle_id ON vehicle_valuations(vehicle_id, date);
@@ -0,0 +1,8 @@
+-- Up Migration
+
+-- Where a vehicle is in its life. Stored vehicles are kept but laid up; sold and scrapped
+-- ones are archived, with what they went for. Only active vehicles get reminders.
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'; -- 'active', 'stored', 'sold', 'scrapped'
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS sale_date DATE;
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS sale_price DECIMAL(12, 2);
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS buyer_notes TEXT;
@@ -0,0 +1,13 @@
+-- Up Migration
+
+-- Document expiry alerts already sent, so each target hears once that a document is
+-- expiring and once that it has expired. Renewing a document changes its expiry_date,
+-- which starts over.
+CREATE TABLE IF NOT EXISTS document_alerts (
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    expiry_date DATE NOT NULL,
+    level VARCHAR(20) NOT NULL, -- 'upcoming', 'overdue'
+    target TEXT NOT NULL, -- channel and address the alert went to
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (document_id, expiry_date, level, target)
+);
@@ -0,0 +1,5 @@
+-- Up Migration
+
+-- The day a vehicle came back from a service, so the time it spent off the road can be
+-- counted. Null when it was not recorded, which is taken to mean the same day.
+ALTER TABLE service_records ADD COLUMN IF NOT EXISTS completed_date DATE;
@@ -0,0 +1,40 @@
+-- Up Migration
+
+-- Indexes for the paged list queries, which have one query per sort key and direction.
+-- Each index matches a query's ORDER BY, so a page is read in order and stops at the
+-- limit. Nullable keys are indexed through the same COALESCE the queries sort by.
+CREATE INDEX IF NOT EXISTS idx_fuel_logs_vehicle_date ON fuel_logs(vehicle_id, date, id);
+CREATE INDEX IF NOT EXISTS idx_fuel_logs_vehicle_odometer ON fuel_logs(vehicle_id, odometer, id);
+CREATE INDEX IF NOT EXISTS idx_fuel_logs_vehicle_total_cost ON fuel_logs(vehicle_id, total_cost, id);
+CREATE INDEX IF NOT EXISTS idx_fuel_logs_vehicle_liters ON fuel_logs(vehicle_id, liters, id);
+CREATE INDEX IF NOT EXISTS idx_fuel_logs_vehicle_price_per_liter ON fuel_logs(vehicle_id, price_per_liter, id);
+
+CREATE INDEX IF NOT EXISTS idx_service_records_vehicle_date ON service_records(vehicle_id, date, id);
+CREATE INDEX IF NOT EXISTS idx_service_records_vehicle_odometer ON service_records(vehicle_id, odometer, id);
+CREATE INDEX IF NOT EXISTS idx_service_records_vehicle_cost ON service_records(vehicle_id, cost, id);
+
+CREATE INDEX IF NOT EXISTS idx_expenses_vehicle_date ON expenses(vehicle_id, date, id);
+CREATE INDEX IF NOT EXISTS idx_expenses_vehicle_amount ON expenses(vehicle_id, amount, id);
+
+CREATE INDEX IF NOT EXISTS idx_odometer_readings_vehicle_recorded_at ON odometer_readings(vehicle_id, recorded_at, id);
+CREATE INDEX IF NOT EXISTS idx_odometer_readings_vehicle_odometer ON odometer_readings(vehicle_id, odometer, id);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_vehicle_due_date
+    ON reminders(vehicle_id, COALESCE(due_date, 'infinity'::date), id);
+CREATE INDEX IF NOT EXISTS idx_reminders_vehicle_due_odometer
+    ON reminders(vehicle_id, COALESCE(due_odometer, 2147483647), id);
+CREATE INDEX IF NOT EXISTS idx_reminders_vehicle_created_at
+    ON reminders(vehicle_id, COALESCE(created_at, TIMESTAMPTZ 'epoch'), id);
+
+-- Documents are also searched across vehicles, newest first by default
+CREATE INDEX IF NOT EXISTS idx_documents_created_at
+    ON documents(COALESCE(created_at, TIMESTAMPTZ 'epoch'), id);
+CREATE INDEX IF NOT EXISTS idx_documents_vehicle_created_at
+    ON documents(vehicle_id, COALESCE(created_at, TIMESTAMPTZ 'epoch'), id);
+CREATE INDEX IF NOT EXISTS idx_documents_vehicle_expiry_date
+    ON documents(vehicle_id, COALESCE(expiry_date, 'infinity'::date), id);
+CREATE INDEX IF NOT EXISTS idx_documents_vehicle_issue_date
+    ON documents(vehicle_id, COALESCE(issue_date, 'infinity'::date), id);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_started_at ON job_runs(started_at, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_created_at ON webhook_deliveries(webhook_id, created_at, id);
@@ -1,8 +1,9 @@
 -- name: CreateVehicle :one
 INSERT INTO vehicles (
-  name, make, model, year, type, vin, license_plate, image_url
+  name, make, model, year, type, vin, license_plate, image_url, fuel_type, distance_unit,
+  purchase_price, purchase_date, depreciation_model, depreciation_rate, residual_value
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 )
 RETURNING *;
 
@@ -16,12 +17,15 @@ ORDER BY created_at DESC;
 
 -- name: UpdateVehicle :one
 UPDATE vehicles
-SET name = $2, make = $3, model = $4, year = $5, type = $6, vin = $7, license_plate = $8, image_url = $9, updated_at = NOW()
+SET name = $2, make = $3, model = $4, year = $5, type = $6, vin = $7, license_plate = $8, image_url = $9,
+    fuel_type = $10, distance_unit = $11, purchase_price = $12, purchase_date = $13,
+    depreciation_model = $14, depreciation_rate = $15, residual_value = $16, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
--- name: UpdateVehicle :one
+
+-- name: UpdateVehicleStatus :one
 UPDATE vehicles
-SET name = $2, make = $3, model = $4, year = $5, type = $6, vin = $7, license_plate = $8, image_url = $9, updated_at = NOW()
+SET status = $2, sale_date = $3, sale_price = $4, buyer_notes = $5, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
@@ -29,6 +33,19 @@ RETURNING *;
 DELETE FROM vehicles
 WHERE id = $1;
 
+-- name: VehicleHasHistory :one
+-- Whether a vehicle has any records that deleting it would remove.
+SELECT (
+    EXISTS (SELECT 1 FROM service_records WHERE service_records.vehicle_id = sqlc.arg(vehicle_id)::int)
+    OR EXISTS (SELECT 1 FROM fuel_logs WHERE fuel_logs.vehicle_id = sqlc.arg(vehicle_id)::int)
+    OR EXISTS (SELECT 1 FROM odometer_readings WHERE odometer_readings.vehicle_id = sqlc.arg(vehicle_id)::int)
+    OR EXISTS (SELECT 1 FROM expenses WHERE expenses.vehicle_id = sqlc.arg(vehicle_id)::int)
+    OR EXISTS (SELECT 1 FROM documents WHERE documents.vehicle_id = sqlc.arg(vehicle_id)::int)
+    OR EXISTS (SELECT 1 FROM fixed_costs WHERE fixed_costs.vehicle_id = sqlc.arg(vehicle_id)::int)
+    OR EXISTS (SELECT 1 FROM loans WHERE loans.vehicle_id = sqlc.arg(vehicle_id)::int)
+    OR EXISTS (SELECT 1 FROM vehicle_valuations WHERE vehicle_valuations.vehicle_id = sqlc.arg(vehicle_id)::int)
+)::bool AS has_history;
+
 -- name: CreateFuelLog :one
 INSERT INTO fuel_logs (vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
@@ -40,10 +57,187 @@ SET date = $2, odometer = $3, liters = $4, price_per_liter = $5, total_cost = $6
 WHERE id = $1
 RETURNING *;
 
--- name: ListFuelLogsByVehicle :many
+-- name: ListFuelLogsByDateAsc :many
+-- One page of a vehicle's fuel logs, oldest first by date. Filters are optional, and
+-- after_key/after_id continue from the last row of the previous page. There is one
+-- query per sort key and direction so each reads a (vehicle_id, key, id) index in order.
 SELECT * FROM fuel_logs
-WHERE vehicle_id = $1
-ORDER BY date DESC;
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (date, id) > ((TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date, sqlc.narg(after_id)::int))
+ORDER BY date, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByDateDesc :many
+-- Like ListFuelLogsByDateAsc, in descending order.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (date, id) < ((TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date, sqlc.narg(after_id)::int))
+ORDER BY date DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByOdometerAsc :many
+-- Like ListFuelLogsByDateAsc, ordered by odometer.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (odometer, id) > (sqlc.narg(after_key)::float8::int, sqlc.narg(after_id)::int))
+ORDER BY odometer, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByOdometerDesc :many
+-- Like ListFuelLogsByDateAsc, ordered by odometer descending.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (odometer, id) < (sqlc.narg(after_key)::float8::int, sqlc.narg(after_id)::int))
+ORDER BY odometer DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByTotalCostAsc :many
+-- Like ListFuelLogsByDateAsc, ordered by total_cost.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (total_cost, id) > (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY total_cost, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByTotalCostDesc :many
+-- Like ListFuelLogsByDateAsc, ordered by total_cost descending.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (total_cost, id) < (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY total_cost DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByLitersAsc :many
+-- Like ListFuelLogsByDateAsc, ordered by liters.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (liters, id) > (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY liters, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByLitersDesc :many
+-- Like ListFuelLogsByDateAsc, ordered by liters descending.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (liters, id) < (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY liters DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByPricePerLiterAsc :many
+-- Like ListFuelLogsByDateAsc, ordered by price_per_liter.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (price_per_liter, id) > (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY price_per_liter, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListFuelLogsByPricePerLiterDesc :many
+-- Like ListFuelLogsByDateAsc, ordered by price_per_liter descending.
+SELECT * FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (price_per_liter, id) < (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY price_per_liter DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountFuelLogsByVehicle :one
+SELECT COUNT(*) FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR total_cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR total_cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(full_tank)::bool IS NULL OR full_tank = sqlc.narg(full_tank)::bool);
+
+-- name: ListPreviousFuelOdometers :many
+-- The closest earlier fill-up odometer for each of the given logs, to work out mileage.
+SELECT f.id, COALESCE((
+    SELECT MAX(p.odometer) FROM fuel_logs p
+    WHERE p.vehicle_id = f.vehicle_id AND p.odometer < f.odometer
+), 0)::int AS previous_odometer
+FROM fuel_logs f
+WHERE f.id = ANY(sqlc.arg(ids)::int[]);
 
 -- name: DeleteFuelLog :exec
 DELETE FROM fuel_logs WHERE id = $1;
@@ -64,27 +258,243 @@ SELECT * FROM reminders
 WHERE vehicle_id = $1 AND is_completed = FALSE
 ORDER BY due_date ASC;
 
+-- name: ListRemindersByDueDateAsc :many
+-- One page of a vehicle's reminders, like ListFuelLogsByDateAsc. Reminders without a due
+-- date or odometer sort after those with one; the expressions match the indexes.
+SELECT * FROM reminders
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(is_completed)::bool IS NULL OR is_completed = sqlc.narg(is_completed)::bool)
+  AND (sqlc.narg(type)::text IS NULL OR type = sqlc.narg(type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR due_date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR due_date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR due_odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR due_odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(due_date, 'infinity'::date), id) > (
+           CASE WHEN sqlc.narg(after_key)::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date END,
+           sqlc.narg(after_id)::int))
+ORDER BY COALESCE(due_date, 'infinity'::date), id
+LIMIT sqlc.arg(limit);
+
+-- name: ListRemindersByDueDateDesc :many
+-- Like ListRemindersByDueDateAsc, in descending order.
+SELECT * FROM reminders
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(is_completed)::bool IS NULL OR is_completed = sqlc.narg(is_completed)::bool)
+  AND (sqlc.narg(type)::text IS NULL OR type = sqlc.narg(type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR due_date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR due_date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR due_odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR due_odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(due_date, 'infinity'::date), id) < (
+           CASE WHEN sqlc.narg(after_key)::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date END,
+           sqlc.narg(after_id)::int))
+ORDER BY COALESCE(due_date, 'infinity'::date) DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListRemindersByDueOdometerAsc :many
+-- Like ListRemindersByDueDateAsc, ordered by due_odometer.
+SELECT * FROM reminders
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(is_completed)::bool IS NULL OR is_completed = sqlc.narg(is_completed)::bool)
+  AND (sqlc.narg(type)::text IS NULL OR type = sqlc.narg(type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR due_date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR due_date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR due_odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR due_odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(due_odometer, 2147483647), id) > (LEAST(sqlc.narg(after_key)::float8, 2147483647)::int, sqlc.narg(after_id)::int))
+ORDER BY COALESCE(due_odometer, 2147483647), id
+LIMIT sqlc.arg(limit);
+
+-- name: ListRemindersByDueOdometerDesc :many
+-- Like ListRemindersByDueDateAsc, ordered by due_odometer descending.
+SELECT * FROM reminders
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(is_completed)::bool IS NULL OR is_completed = sqlc.narg(is_completed)::bool)
+  AND (sqlc.narg(type)::text IS NULL OR type = sqlc.narg(type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR due_date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR due_date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR due_odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR due_odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(due_odometer, 2147483647), id) < (LEAST(sqlc.narg(after_key)::float8, 2147483647)::int, sqlc.narg(after_id)::int))
+ORDER BY COALESCE(due_odometer, 2147483647) DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListRemindersByCreatedAtAsc :many
+-- Like ListRemindersByDueDateAsc, ordered by created_at.
+SELECT * FROM reminders
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(is_completed)::bool IS NULL OR is_completed = sqlc.narg(is_completed)::bool)
+  AND (sqlc.narg(type)::text IS NULL OR type = sqlc.narg(type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR due_date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR due_date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR due_odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR due_odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(created_at, TIMESTAMPTZ 'epoch'), id) > (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY COALESCE(created_at, TIMESTAMPTZ 'epoch'), id
+LIMIT sqlc.arg(limit);
+
+-- name: ListRemindersByCreatedAtDesc :many
+-- Like ListRemindersByDueDateAsc, ordered by created_at descending.
+SELECT * FROM reminders
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(is_completed)::bool IS NULL OR is_completed = sqlc.narg(is_completed)::bool)
+  AND (sqlc.narg(type)::text IS NULL OR type = sqlc.narg(type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR due_date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR due_date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR due_odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR due_odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(created_at, TIMESTAMPTZ 'epoch'), id) < (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY COALESCE(created_at, TIMESTAMPTZ 'epoch') DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountRemindersPage :one
+SELECT COUNT(*) FROM reminders
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(is_completed)::bool IS NULL OR is_completed = sqlc.narg(is_completed)::bool)
+  AND (sqlc.narg(type)::text IS NULL OR type = sqlc.narg(type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR due_date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR due_date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR due_odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR due_odometer <= sqlc.narg(odometer_max)::int);
+
 -- name: CompleteReminder :exec
-UPDATE reminders SET is_completed = TRUE WHERE id = $1;
+UPDATE reminders SET is_completed = TRUE, completed_at = NOW() WHERE id = $1;
+
+-- name: GetReminder :one
+SELECT * FROM reminders
+WHERE id = $1 LIMIT 1;
+
+-- name: DeleteReminder :exec
+DELETE FROM reminders WHERE id = $1;
 
 -- name: CreateServiceRecord :one
 INSERT INTO service_records (
-  vehicle_id, date, odometer, cost, notes, service_type, document_url
+  vehicle_id, date, odometer, cost, notes, service_type, document_url, completed_date
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8
 )
 RETURNING *;
 
 -- name: UpdateServiceRecord :one
 UPDATE service_records
-SET date = $2, odometer = $3, cost = $4, notes = $5, service_type = $6, document_url = $7
+SET date = $2, odometer = $3, cost = $4, notes = $5, service_type = $6, document_url = $7, completed_date = $8
 WHERE id = $1
 RETURNING *;
 
--- name: ListServiceRecordsByVehicle :many
+-- name: ListServiceRecordsByDateAsc :many
+-- One page of a vehicle's service records, like ListFuelLogsByDateAsc.
 SELECT * FROM service_records
-WHERE vehicle_id = $1
-ORDER BY date DESC;
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(service_type)::text IS NULL OR service_type = sqlc.narg(service_type)::text)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (date, id) > ((TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date, sqlc.narg(after_id)::int))
+ORDER BY date, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListServiceRecordsByDateDesc :many
+-- Like ListServiceRecordsByDateAsc, in descending order.
+SELECT * FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(service_type)::text IS NULL OR service_type = sqlc.narg(service_type)::text)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (date, id) < ((TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date, sqlc.narg(after_id)::int))
+ORDER BY date DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListServiceRecordsByOdometerAsc :many
+-- Like ListServiceRecordsByDateAsc, ordered by odometer.
+SELECT * FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(service_type)::text IS NULL OR service_type = sqlc.narg(service_type)::text)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (odometer, id) > (sqlc.narg(after_key)::float8::int, sqlc.narg(after_id)::int))
+ORDER BY odometer, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListServiceRecordsByOdometerDesc :many
+-- Like ListServiceRecordsByDateAsc, ordered by odometer descending.
+SELECT * FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(service_type)::text IS NULL OR service_type = sqlc.narg(service_type)::text)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (odometer, id) < (sqlc.narg(after_key)::float8::int, sqlc.narg(after_id)::int))
+ORDER BY odometer DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListServiceRecordsByCostAsc :many
+-- Like ListServiceRecordsByDateAsc, ordered by cost.
+SELECT * FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(service_type)::text IS NULL OR service_type = sqlc.narg(service_type)::text)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (cost, id) > (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY cost, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListServiceRecordsByCostDesc :many
+-- Like ListServiceRecordsByDateAsc, ordered by cost descending.
+SELECT * FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(service_type)::text IS NULL OR service_type = sqlc.narg(service_type)::text)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (cost, id) < (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY cost DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountServiceRecordsByVehicle :one
+SELECT COUNT(*) FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR cost >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR cost <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(service_type)::text IS NULL OR service_type = sqlc.narg(service_type)::text);
 
 -- name: DeleteServiceRecord :exec
 DELETE FROM service_records WHERE id = $1;
@@ -107,18 +517,1233 @@ SELECT
     (SELECT COALESCE(SUM(cost), 0.0)::float8 FROM service_records WHERE service_records.vehicle_id = $1) AS total_service_cost,
     (SELECT COALESCE(SUM(liters), 0.0)::float8 FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_liters,
     (SELECT COUNT(*) FROM service_records WHERE service_records.vehicle_id = $1) AS total_services,
-    (SELECT COUNT(*) FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_fuel_logs
+    (SELECT COUNT(*) FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_fuel_logs,
+    (SELECT COALESCE(SUM(amount), 0.0)::float8 FROM expenses WHERE expenses.vehicle_id = $1) AS total_expense_cost,
+    (SELECT COUNT(*) FROM expenses WHERE expenses.vehicle_id = $1) AS total_expenses
 ;
 
 -- name: CreateDocument :one
-INSERT INTO documents (vehicle_id, name, type, file_url, expiry_date, notes)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO documents (
+  vehicle_id, name, type, file_url, expiry_date, notes, previous_version_id,
+  category, issue_date, issuer, policy_number, registration_number, tags
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 RETURNING *;
 
+-- name: UpdateDocument :one
+UPDATE documents
+SET name = $2, type = $3, file_url = $4, expiry_date = $5, notes = $6,
+    category = $7, issue_date = $8, issuer = $9, policy_number = $10, registration_number = $11, tags = $12
+WHERE id = $1
+RETURNING *;
+
+-- name: GetDocument :one
+SELECT * FROM documents
+WHERE id = $1 LIMIT 1;
+
 -- name: ListDocumentsByVehicle :many
 SELECT * FROM documents
-WHERE vehicle_id = $1
+WHERE vehicle_id = $1 AND archived_at IS NULL
 ORDER BY created_at DESC;
 
+-- name: SearchDocumentsByCreatedAtAsc :many
+-- Filters are optional; q matches name, issuer, policy/registration numbers and notes.
+-- Documents without an expiry or issue date sort after the rest.
+SELECT * FROM documents
+WHERE (sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(tag)::text IS NULL OR sqlc.narg(tag)::text = ANY(tags))
+  AND (sqlc.narg(expires_from)::date IS NULL OR expiry_date >= sqlc.narg(expires_from)::date)
+  AND (sqlc.narg(expires_to)::date IS NULL OR expiry_date <= sqlc.narg(expires_to)::date)
+  AND (sqlc.narg(query)::text IS NULL
+       OR name ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR issuer ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR policy_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR registration_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR notes ILIKE '%' || sqlc.narg(query)::text || '%')
+  AND (sqlc.arg(include_archived)::bool OR archived_at IS NULL)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(created_at, TIMESTAMPTZ 'epoch'), id) > (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY COALESCE(created_at, TIMESTAMPTZ 'epoch'), id
+LIMIT sqlc.arg(limit);
+
+-- name: SearchDocumentsByCreatedAtDesc :many
+-- Like SearchDocumentsByCreatedAtAsc, in descending order.
+SELECT * FROM documents
+WHERE (sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(tag)::text IS NULL OR sqlc.narg(tag)::text = ANY(tags))
+  AND (sqlc.narg(expires_from)::date IS NULL OR expiry_date >= sqlc.narg(expires_from)::date)
+  AND (sqlc.narg(expires_to)::date IS NULL OR expiry_date <= sqlc.narg(expires_to)::date)
+  AND (sqlc.narg(query)::text IS NULL
+       OR name ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR issuer ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR policy_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR registration_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR notes ILIKE '%' || sqlc.narg(query)::text || '%')
+  AND (sqlc.arg(include_archived)::bool OR archived_at IS NULL)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(created_at, TIMESTAMPTZ 'epoch'), id) < (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY COALESCE(created_at, TIMESTAMPTZ 'epoch') DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: SearchDocumentsByExpiryDateAsc :many
+-- Like SearchDocumentsByCreatedAtAsc, ordered by expiry_date.
+SELECT * FROM documents
+WHERE (sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(tag)::text IS NULL OR sqlc.narg(tag)::text = ANY(tags))
+  AND (sqlc.narg(expires_from)::date IS NULL OR expiry_date >= sqlc.narg(expires_from)::date)
+  AND (sqlc.narg(expires_to)::date IS NULL OR expiry_date <= sqlc.narg(expires_to)::date)
+  AND (sqlc.narg(query)::text IS NULL
+       OR name ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR issuer ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR policy_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR registration_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR notes ILIKE '%' || sqlc.narg(query)::text || '%')
+  AND (sqlc.arg(include_archived)::bool OR archived_at IS NULL)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(expiry_date, 'infinity'::date), id) > (
+           CASE WHEN sqlc.narg(after_key)::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date END,
+           sqlc.narg(after_id)::int))
+ORDER BY COALESCE(expiry_date, 'infinity'::date), id
+LIMIT sqlc.arg(limit);
+
+-- name: SearchDocumentsByExpiryDateDesc :many
+-- Like SearchDocumentsByCreatedAtAsc, ordered by expiry_date descending.
+SELECT * FROM documents
+WHERE (sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(tag)::text IS NULL OR sqlc.narg(tag)::text = ANY(tags))
+  AND (sqlc.narg(expires_from)::date IS NULL OR expiry_date >= sqlc.narg(expires_from)::date)
+  AND (sqlc.narg(expires_to)::date IS NULL OR expiry_date <= sqlc.narg(expires_to)::date)
+  AND (sqlc.narg(query)::text IS NULL
+       OR name ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR issuer ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR policy_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR registration_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR notes ILIKE '%' || sqlc.narg(query)::text || '%')
+  AND (sqlc.arg(include_archived)::bool OR archived_at IS NULL)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(expiry_date, 'infinity'::date), id) < (
+           CASE WHEN sqlc.narg(after_key)::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date END,
+           sqlc.narg(after_id)::int))
+ORDER BY COALESCE(expiry_date, 'infinity'::date) DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: SearchDocumentsByIssueDateAsc :many
+-- Like SearchDocumentsByCreatedAtAsc, ordered by issue_date.
+SELECT * FROM documents
+WHERE (sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(tag)::text IS NULL OR sqlc.narg(tag)::text = ANY(tags))
+  AND (sqlc.narg(expires_from)::date IS NULL OR expiry_date >= sqlc.narg(expires_from)::date)
+  AND (sqlc.narg(expires_to)::date IS NULL OR expiry_date <= sqlc.narg(expires_to)::date)
+  AND (sqlc.narg(query)::text IS NULL
+       OR name ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR issuer ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR policy_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR registration_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR notes ILIKE '%' || sqlc.narg(query)::text || '%')
+  AND (sqlc.arg(include_archived)::bool OR archived_at IS NULL)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(issue_date, 'infinity'::date), id) > (
+           CASE WHEN sqlc.narg(after_key)::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date END,
+           sqlc.narg(after_id)::int))
+ORDER BY COALESCE(issue_date, 'infinity'::date), id
+LIMIT sqlc.arg(limit);
+
+-- name: SearchDocumentsByIssueDateDesc :many
+-- Like SearchDocumentsByCreatedAtAsc, ordered by issue_date descending.
+SELECT * FROM documents
+WHERE (sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(tag)::text IS NULL OR sqlc.narg(tag)::text = ANY(tags))
+  AND (sqlc.narg(expires_from)::date IS NULL OR expiry_date >= sqlc.narg(expires_from)::date)
+  AND (sqlc.narg(expires_to)::date IS NULL OR expiry_date <= sqlc.narg(expires_to)::date)
+  AND (sqlc.narg(query)::text IS NULL
+       OR name ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR issuer ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR policy_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR registration_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR notes ILIKE '%' || sqlc.narg(query)::text || '%')
+  AND (sqlc.arg(include_archived)::bool OR archived_at IS NULL)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (COALESCE(issue_date, 'infinity'::date), id) < (
+           CASE WHEN sqlc.narg(after_key)::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date END,
+           sqlc.narg(after_id)::int))
+ORDER BY COALESCE(issue_date, 'infinity'::date) DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountDocuments :one
+SELECT COUNT(*) FROM documents
+WHERE (sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(tag)::text IS NULL OR sqlc.narg(tag)::text = ANY(tags))
+  AND (sqlc.narg(expires_from)::date IS NULL OR expiry_date >= sqlc.narg(expires_from)::date)
+  AND (sqlc.narg(expires_to)::date IS NULL OR expiry_date <= sqlc.narg(expires_to)::date)
+  AND (sqlc.narg(query)::text IS NULL
+       OR name ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR issuer ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR policy_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR registration_number ILIKE '%' || sqlc.narg(query)::text || '%'
+       OR notes ILIKE '%' || sqlc.narg(query)::text || '%')
+  AND (sqlc.arg(include_archived)::bool OR archived_at IS NULL);
+
+-- name: ListDocumentCategories :many
+SELECT * FROM document_categories
+ORDER BY name;
+
+-- name: CreateDocumentCategory :one
+INSERT INTO document_categories (slug, name, tracks_expiry)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: DeleteDocumentCategory :exec
+DELETE FROM document_categories WHERE slug = $1;
+
+-- name: CreateDocumentFile :one
+INSERT INTO document_files (document_id, label, file_name, content_type, size_bytes, storage_key, position)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetDocumentFile :one
+SELECT * FROM document_files
+WHERE id = $1 LIMIT 1;
+
+-- name: ListDocumentFiles :many
+SELECT * FROM document_files
+WHERE document_id = $1
+ORDER BY position, id;
+
+-- name: ListDocumentFilesByDocuments :many
+SELECT * FROM document_files
+WHERE document_id = ANY(sqlc.arg(document_ids)::int[])
+ORDER BY document_id, position, id;
+
+-- name: DeleteDocumentFile :exec
+DELETE FROM document_files WHERE id = $1;
+
+-- name: ArchiveDocument :one
+UPDATE documents
+SET archived_at = NOW()
+WHERE id = $1 AND archived_at IS NULL
+RETURNING *;
+
+-- name: ListExpiringDocuments :many
+-- Current documents that have expired or expire on or before the given date, across all vehicles.
+SELECT * FROM documents
+WHERE archived_at IS NULL AND expiry_date <= sqlc.arg(to_date)::date
+ORDER BY expiry_date ASC;
+
 -- name: DeleteDocument :exec
 DELETE FROM documents WHERE id = $1;
+
+-- name: CreateUser :one
+INSERT INTO users (name, email, ntfy_topic, locale)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetUser :one
+SELECT * FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: ListUsers :many
+SELECT * FROM users
+ORDER BY name ASC;
+
+-- name: UpdateUser :one
+UPDATE users
+SET name = $2, email = $3, ntfy_topic = $4, locale = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users WHERE id = $1;
+
+-- name: CreateSubscription :one
+INSERT INTO notification_subscriptions (user_id, vehicle_id, channel, reminder_types, triggers, lead_days, digest, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: UpdateSubscription :one
+UPDATE notification_subscriptions
+SET vehicle_id = $2, channel = $3, reminder_types = $4, triggers = $5, lead_days = $6, digest = $7, enabled = $8
+WHERE id = $1
+RETURNING *;
+
+-- name: ListSubscriptionsByUser :many
+SELECT * FROM notification_subscriptions
+WHERE user_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteSubscription :exec
+DELETE FROM notification_subscriptions WHERE id = $1;
+
+-- name: ListActiveSubscriptions :many
+SELECT s.id, s.user_id, s.vehicle_id, s.channel, s.reminder_types, s.triggers, s.lead_days, s.digest,
+       u.name AS user_name, u.email AS user_email, u.ntfy_topic AS user_ntfy_topic, u.locale AS user_locale
+FROM notification_subscriptions s
+JOIN users u ON u.id = s.user_id
+WHERE s.enabled = TRUE
+ORDER BY s.user_id, s.id;
+
+-- name: ListDocumentsExpiringBetween :many
+SELECT * FROM documents
+WHERE archived_at IS NULL AND expiry_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+ORDER BY expiry_date ASC;
+
+-- name: ListFuelSpendSince :many
+SELECT vehicle_id,
+    COALESCE(SUM(total_cost), 0.0)::float8 AS total_cost,
+    COALESCE(SUM(liters), 0.0)::float8 AS total_liters,
+    COUNT(*) AS fill_ups
+FROM fuel_logs
+WHERE date >= sqlc.arg(since)::date
+GROUP BY vehicle_id;
+
+-- name: ListNotificationTemplates :many
+SELECT * FROM notification_templates;
+
+-- name: ListNotificationTemplatesByUser :many
+SELECT * FROM notification_templates
+WHERE user_id = $1
+ORDER BY kind ASC;
+
+-- name: UpsertNotificationTemplate :one
+INSERT INTO notification_templates (user_id, kind, title, body)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, kind) DO UPDATE
+SET title = EXCLUDED.title, body = EXCLUDED.body, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteNotificationTemplate :exec
+DELETE FROM notification_templates
+WHERE user_id = $1 AND kind = $2;
+
+-- name: CreateJobRun :one
+INSERT INTO job_runs (job_name, trigger)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: FinishJobRun :one
+UPDATE job_runs
+SET status = $2, error = $3, finished_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListJobRunsByStartedAtAsc :many
+SELECT * FROM job_runs
+WHERE (sqlc.narg(job_name)::text IS NULL OR job_name = sqlc.narg(job_name)::text)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR started_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR started_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (started_at, id) > (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY started_at, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListJobRunsByStartedAtDesc :many
+-- Like ListJobRunsByStartedAtAsc, in descending order.
+SELECT * FROM job_runs
+WHERE (sqlc.narg(job_name)::text IS NULL OR job_name = sqlc.narg(job_name)::text)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR started_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR started_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (started_at, id) < (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY started_at DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountJobRuns :one
+SELECT COUNT(*) FROM job_runs
+WHERE (sqlc.narg(job_name)::text IS NULL OR job_name = sqlc.narg(job_name)::text)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR started_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR started_at < sqlc.narg(date_to)::date + 1);
+
+-- name: ListLatestJobRuns :many
+SELECT DISTINCT ON (job_name) * FROM job_runs
+ORDER BY job_name, started_at DESC;
+
+-- name: DeleteJobRunsBefore :execrows
+DELETE FROM job_runs
+WHERE started_at < $1;
+
+-- name: ListOdometerReadings :many
+-- Odometer readings from fuel logs, service records and standalone readings, oldest first,
+-- used to estimate driving rate.
+SELECT date::date AS date, odometer::int AS odometer
+FROM fuel_logs
+WHERE vehicle_id = sqlc.arg(vehicle_id) AND date >= sqlc.arg(since)
+UNION ALL
+SELECT date::date AS date, odometer::int AS odometer
+FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id) AND date >= sqlc.arg(since)
+UNION ALL
+SELECT recorded_at::date AS date, odometer::int AS odometer
+FROM odometer_readings
+WHERE vehicle_id = sqlc.arg(vehicle_id) AND recorded_at >= sqlc.arg(since)
+ORDER BY date, odometer;
+
+-- name: GetVehicleOdometer :one
+-- Highest odometer from any source, including readings older than the driving rate window.
+SELECT GREATEST(
+    (SELECT COALESCE(MAX(odometer), 0) FROM fuel_logs WHERE fuel_logs.vehicle_id = sqlc.arg(vehicle_id)::int),
+    (SELECT COALESCE(MAX(odometer), 0) FROM service_records WHERE service_records.vehicle_id = sqlc.arg(vehicle_id)::int),
+    (SELECT COALESCE(MAX(odometer), 0) FROM odometer_readings WHERE odometer_readings.vehicle_id = sqlc.arg(vehicle_id)::int)
+)::int AS odometer;
+
+-- name: ListPendingThumbnails :many
+SELECT * FROM document_files
+WHERE thumbnail_status = 'pending'
+ORDER BY id
+LIMIT $1;
+
+-- name: SetThumbnailStatus :exec
+UPDATE document_files
+SET thumbnail_status = $2, thumbnail_error = $3
+WHERE id = $1;
+
+-- name: SetUserFeedToken :one
+UPDATE users
+SET feed_token = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetUserByFeedToken :one
+SELECT * FROM users
+WHERE feed_token = $1 LIMIT 1;
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (url, secret, event_types, description, enabled)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+ORDER BY id ASC;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = $2, event_types = $3, description = $4, enabled = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhooks WHERE id = $1;
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Queues the event for every enabled webhook that listens to it.
+INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+SELECT id, sqlc.arg(event_type)::text, sqlc.arg(payload)::jsonb
+FROM webhooks
+WHERE enabled = TRUE
+  AND (cardinality(event_types) = 0 OR sqlc.arg(event_type)::text = ANY(event_types));
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ClaimWebhookDeliveries :many
+-- Leases due deliveries so concurrent workers don't send the same one twice.
+-- A delivery whose worker dies is picked up again once the lease expires.
+UPDATE webhook_deliveries
+SET next_attempt_at = NOW() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at ASC
+    LIMIT sqlc.arg(batch)::int
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RecordWebhookAttempt :exec
+UPDATE webhook_deliveries
+SET status = $2, attempts = attempts + 1, next_attempt_at = $3, response_status = $4, error = $5,
+    delivered_at = CASE WHEN $2 = 'success' THEN NOW() ELSE delivered_at END
+WHERE id = $1;
+
+-- name: RetryWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending', next_attempt_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListWebhookDeliveriesByCreatedAtAsc :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = sqlc.arg(webhook_id)::int
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(event_type)::text IS NULL OR event_type = sqlc.narg(event_type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR created_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR created_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (created_at, id) > (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListWebhookDeliveriesByCreatedAtDesc :many
+-- Like ListWebhookDeliveriesByCreatedAtAsc, in descending order.
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = sqlc.arg(webhook_id)::int
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(event_type)::text IS NULL OR event_type = sqlc.narg(event_type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR created_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR created_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (created_at, id) < (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) FROM webhook_deliveries
+WHERE webhook_id = sqlc.arg(webhook_id)::int
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(event_type)::text IS NULL OR event_type = sqlc.narg(event_type)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR created_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR created_at < sqlc.narg(date_to)::date + 1);
+
+-- name: DeleteWebhookDeliveriesBefore :execrows
+DELETE FROM webhook_deliveries
+WHERE status <> 'pending' AND created_at < $1;
+
+-- name: CreateOdometerReading :one
+INSERT INTO odometer_readings (vehicle_id, odometer, recorded_at, source)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListOdometerReadingsByRecordedAtAsc :many
+-- One page of a vehicle's standalone odometer readings, like ListFuelLogsByDateAsc.
+SELECT * FROM odometer_readings
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(source)::text IS NULL OR source = sqlc.narg(source)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR recorded_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR recorded_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (recorded_at, id) > (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY recorded_at, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListOdometerReadingsByRecordedAtDesc :many
+-- Like ListOdometerReadingsByRecordedAtAsc, in descending order.
+SELECT * FROM odometer_readings
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(source)::text IS NULL OR source = sqlc.narg(source)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR recorded_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR recorded_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (recorded_at, id) < (TIMESTAMPTZ 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 microsecond', sqlc.narg(after_id)::int))
+ORDER BY recorded_at DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListOdometerReadingsByOdometerAsc :many
+-- Like ListOdometerReadingsByRecordedAtAsc, ordered by odometer.
+SELECT * FROM odometer_readings
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(source)::text IS NULL OR source = sqlc.narg(source)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR recorded_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR recorded_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (odometer, id) > (sqlc.narg(after_key)::float8::int, sqlc.narg(after_id)::int))
+ORDER BY odometer, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListOdometerReadingsByOdometerDesc :many
+-- Like ListOdometerReadingsByRecordedAtAsc, ordered by odometer descending.
+SELECT * FROM odometer_readings
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(source)::text IS NULL OR source = sqlc.narg(source)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR recorded_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR recorded_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (odometer, id) < (sqlc.narg(after_key)::float8::int, sqlc.narg(after_id)::int))
+ORDER BY odometer DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountOdometerReadingsByVehicle :one
+SELECT COUNT(*) FROM odometer_readings
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(source)::text IS NULL OR source = sqlc.narg(source)::text)
+  AND (sqlc.narg(date_from)::date IS NULL OR recorded_at >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR recorded_at < sqlc.narg(date_to)::date + 1)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int);
+
+-- name: GetVehicleTelemetry :one
+-- Latest odometer from any source and spend since a date, for Home Assistant sensors.
+SELECT
+    GREATEST(
+        (SELECT COALESCE(MAX(odometer), 0) FROM fuel_logs WHERE fuel_logs.vehicle_id = sqlc.arg(vehicle_id)::int),
+        (SELECT COALESCE(MAX(odometer), 0) FROM service_records WHERE service_records.vehicle_id = sqlc.arg(vehicle_id)::int),
+        (SELECT COALESCE(MAX(odometer), 0) FROM odometer_readings WHERE odometer_readings.vehicle_id = sqlc.arg(vehicle_id)::int)
+    )::int AS odometer,
+    (
+        (SELECT COALESCE(SUM(total_cost), 0) FROM fuel_logs WHERE fuel_logs.vehicle_id = sqlc.arg(vehicle_id)::int AND date >= sqlc.arg(since)::date) +
+        (SELECT COALESCE(SUM(cost), 0) FROM service_records WHERE service_records.vehicle_id = sqlc.arg(vehicle_id)::int AND date >= sqlc.arg(since)::date) +
+        (SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE expenses.vehicle_id = sqlc.arg(vehicle_id)::int AND date >= sqlc.arg(since)::date)
+    )::float8 AS period_cost;
+
+-- name: ListLatestFuelLogs :many
+SELECT * FROM fuel_logs
+WHERE vehicle_id = $1
+ORDER BY odometer DESC
+LIMIT $2;
+
+-- name: ListActivityAsc :many
+-- Fuel logs, service records, expenses, reminder completions and document uploads across
+-- vehicles, as one timeline. Fuel logs, service records and expenses only have a date, so
+-- they sit at midnight UTC. No index can serve the union's order, so it is sorted in
+-- full, but each direction has its own query for a plain ORDER BY.
+WITH activity AS (
+    SELECT 'fuel_log' AS type, 1 AS type_rank, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC' AS occurred_at,
+           'Refuelled ' || liters::text || ' L' AS title, total_cost::float8 AS amount, odometer
+    FROM fuel_logs
+    UNION ALL
+    SELECT 'service_record', 2, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(COALESCE(NULLIF(service_type, ''), 'service')), cost::float8, odometer
+    FROM service_records
+    UNION ALL
+    SELECT 'reminder_completed', 3, id, vehicle_id, completed_at, title, 0, due_odometer
+    FROM reminders
+    WHERE completed_at IS NOT NULL
+    UNION ALL
+    SELECT 'document_upload', 4, f.id, d.vehicle_id, f.created_at,
+           d.name || ': ' || f.file_name, 0, NULL
+    FROM document_files f
+    JOIN documents d ON d.id = f.document_id
+    UNION ALL
+    SELECT 'expense', 5, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(category) || COALESCE(': ' || NULLIF(description, ''), ''), amount::float8, odometer
+    FROM expenses
+), keyed AS (
+    -- Milliseconds times 8 plus the type's rank orders same-time events of different
+    -- types and keeps keys exact as float8
+    SELECT *, (FLOOR(EXTRACT(EPOCH FROM occurred_at) * 1000) * 8 + type_rank)::float8 AS sort_key
+    FROM activity
+    WHERE (COALESCE(cardinality(sqlc.arg(vehicle_ids)::int[]), 0) = 0 OR vehicle_id = ANY(sqlc.arg(vehicle_ids)::int[]))
+      AND (COALESCE(cardinality(sqlc.arg(types)::text[]), 0) = 0 OR type = ANY(sqlc.arg(types)::text[]))
+      AND (sqlc.narg(date_from)::date IS NULL OR occurred_at >= sqlc.narg(date_from)::date)
+      AND (sqlc.narg(date_to)::date IS NULL OR occurred_at < sqlc.narg(date_to)::date + 1)
+)
+SELECT type::text AS type, id::int AS id, COALESCE(vehicle_id, 0)::int AS vehicle_id,
+       occurred_at::timestamptz AS occurred_at, title::text AS title, amount::float8 AS amount,
+       COALESCE(odometer, 0)::int AS odometer, sort_key::float8 AS sort_key
+FROM keyed
+WHERE sqlc.narg(after_key)::float8 IS NULL
+   OR (sort_key, id) > (sqlc.narg(after_key)::float8, sqlc.narg(after_id)::int)
+ORDER BY sort_key, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListActivityDesc :many
+-- Like ListActivityAsc, newest first.
+WITH activity AS (
+    SELECT 'fuel_log' AS type, 1 AS type_rank, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC' AS occurred_at,
+           'Refuelled ' || liters::text || ' L' AS title, total_cost::float8 AS amount, odometer
+    FROM fuel_logs
+    UNION ALL
+    SELECT 'service_record', 2, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(COALESCE(NULLIF(service_type, ''), 'service')), cost::float8, odometer
+    FROM service_records
+    UNION ALL
+    SELECT 'reminder_completed', 3, id, vehicle_id, completed_at, title, 0, due_odometer
+    FROM reminders
+    WHERE completed_at IS NOT NULL
+    UNION ALL
+    SELECT 'document_upload', 4, f.id, d.vehicle_id, f.created_at,
+           d.name || ': ' || f.file_name, 0, NULL
+    FROM document_files f
+    JOIN documents d ON d.id = f.document_id
+    UNION ALL
+    SELECT 'expense', 5, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(category) || COALESCE(': ' || NULLIF(description, ''), ''), amount::float8, odometer
+    FROM expenses
+), keyed AS (
+    -- Milliseconds times 8 plus the type's rank orders same-time events of different
+    -- types and keeps keys exact as float8
+    SELECT *, (FLOOR(EXTRACT(EPOCH FROM occurred_at) * 1000) * 8 + type_rank)::float8 AS sort_key
+    FROM activity
+    WHERE (COALESCE(cardinality(sqlc.arg(vehicle_ids)::int[]), 0) = 0 OR vehicle_id = ANY(sqlc.arg(vehicle_ids)::int[]))
+      AND (COALESCE(cardinality(sqlc.arg(types)::text[]), 0) = 0 OR type = ANY(sqlc.arg(types)::text[]))
+      AND (sqlc.narg(date_from)::date IS NULL OR occurred_at >= sqlc.narg(date_from)::date)
+      AND (sqlc.narg(date_to)::date IS NULL OR occurred_at < sqlc.narg(date_to)::date + 1)
+)
+SELECT type::text AS type, id::int AS id, COALESCE(vehicle_id, 0)::int AS vehicle_id,
+       occurred_at::timestamptz AS occurred_at, title::text AS title, amount::float8 AS amount,
+       COALESCE(odometer, 0)::int AS odometer, sort_key::float8 AS sort_key
+FROM keyed
+WHERE sqlc.narg(after_key)::float8 IS NULL
+   OR (sort_key, id) < (sqlc.narg(after_key)::float8, sqlc.narg(after_id)::int)
+ORDER BY sort_key DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountActivity :one
+WITH activity AS (
+    SELECT 'fuel_log' AS type, 1 AS type_rank, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC' AS occurred_at,
+           'Refuelled ' || liters::text || ' L' AS title, total_cost::float8 AS amount, odometer
+    FROM fuel_logs
+    UNION ALL
+    SELECT 'service_record', 2, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(COALESCE(NULLIF(service_type, ''), 'service')), cost::float8, odometer
+    FROM service_records
+    UNION ALL
+    SELECT 'reminder_completed', 3, id, vehicle_id, completed_at, title, 0, due_odometer
+    FROM reminders
+    WHERE completed_at IS NOT NULL
+    UNION ALL
+    SELECT 'document_upload', 4, f.id, d.vehicle_id, f.created_at,
+           d.name || ': ' || f.file_name, 0, NULL
+    FROM document_files f
+    JOIN documents d ON d.id = f.document_id
+    UNION ALL
+    SELECT 'expense', 5, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(category) || COALESCE(': ' || NULLIF(description, ''), ''), amount::float8, odometer
+    FROM expenses
+), keyed AS (
+    -- Milliseconds times 8 plus the type's rank orders same-time events of different
+    -- types and keeps keys exact as float8
+    SELECT *, (FLOOR(EXTRACT(EPOCH FROM occurred_at) * 1000) * 8 + type_rank)::float8 AS sort_key
+    FROM activity
+    WHERE (COALESCE(cardinality(sqlc.arg(vehicle_ids)::int[]), 0) = 0 OR vehicle_id = ANY(sqlc.arg(vehicle_ids)::int[]))
+      AND (COALESCE(cardinality(sqlc.arg(types)::text[]), 0) = 0 OR type = ANY(sqlc.arg(types)::text[]))
+      AND (sqlc.narg(date_from)::date IS NULL OR occurred_at >= sqlc.narg(date_from)::date)
+      AND (sqlc.narg(date_to)::date IS NULL OR occurred_at < sqlc.narg(date_to)::date + 1)
+)
+SELECT COUNT(*) FROM keyed;
+
+-- name: GetFuelTimeseries :many
+-- Fuel spend per period. Economy uses full-tank fills only: the distance since the
+-- previous fill over the litres filled, summed per period.
+WITH fills AS (
+    SELECT date, total_cost, liters, full_tank,
+           odometer - LAG(odometer) OVER (ORDER BY odometer, id) AS distance
+    FROM fuel_logs
+    WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+)
+SELECT date_trunc(sqlc.arg(period)::text, date::timestamp)::date AS period_start,
+       SUM(total_cost)::float8 AS fuel_cost,
+       SUM(liters)::float8 AS liters,
+       COALESCE(SUM(distance) FILTER (WHERE full_tank AND distance > 0), 0)::float8 AS economy_distance,
+       COALESCE(SUM(liters) FILTER (WHERE full_tank AND distance > 0), 0)::float8 AS economy_liters
+FROM fills
+WHERE date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetServiceTimeseries :many
+SELECT date_trunc(sqlc.arg(period)::text, date::timestamp)::date AS period_start,
+       SUM(cost)::float8 AS service_cost
+FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetOdometerTimeseries :many
+-- Highest odometer reading from any source per period, up to date_to. Earlier periods are
+-- included so the first period in range has a reading to measure distance from.
+WITH readings AS (
+    SELECT date, odometer FROM fuel_logs WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+    UNION ALL
+    SELECT date, odometer FROM service_records WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+    UNION ALL
+    SELECT recorded_at::date, odometer FROM odometer_readings WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+)
+SELECT date_trunc(sqlc.arg(period)::text, date::timestamp)::date AS period_start,
+       MAX(odometer)::int AS odometer
+FROM readings
+WHERE date <= sqlc.arg(date_to)::date
+GROUP BY 1
+ORDER BY 1;
+
+-- name: CreateFixedCost :one
+INSERT INTO fixed_costs (vehicle_id, category, amount, frequency, start_date, end_date, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: UpdateFixedCost :one
+UPDATE fixed_costs
+SET category = $2, amount = $3, frequency = $4, start_date = $5, end_date = $6, notes = $7
+WHERE id = $1
+RETURNING *;
+
+-- name: ListFixedCostsByVehicle :many
+SELECT * FROM fixed_costs
+WHERE vehicle_id = $1
+ORDER BY start_date DESC, id DESC;
+
+-- name: DeleteFixedCost :exec
+DELETE FROM fixed_costs WHERE id = $1;
+
+-- name: GetVehicleSpend :one
+-- Fuel and service spend of a vehicle between two dates, inclusive.
+SELECT
+    (SELECT COALESCE(SUM(total_cost), 0) FROM fuel_logs
+     WHERE fuel_logs.vehicle_id = sqlc.arg(vehicle_id)::int AND date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date)::float8 AS fuel_cost,
+    (SELECT COALESCE(SUM(cost), 0) FROM service_records
+     WHERE service_records.vehicle_id = sqlc.arg(vehicle_id)::int AND date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date)::float8 AS service_cost;
+
+-- name: GetOdometerRange :one
+-- Odometer at the start and end of a date range, from any source. The start is the last
+-- reading before the range, or the first one in it for vehicles added during the range.
+WITH readings AS (
+    SELECT date, odometer FROM fuel_logs WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+    UNION ALL
+    SELECT date, odometer FROM service_records WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+    UNION ALL
+    SELECT recorded_at::date, odometer FROM odometer_readings WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+)
+SELECT
+    COALESCE(
+        (SELECT MAX(odometer) FROM readings WHERE date < sqlc.arg(date_from)::date),
+        (SELECT MIN(odometer) FROM readings WHERE date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date),
+        0
+    )::int AS start_odometer,
+    COALESCE((SELECT MAX(odometer) FROM readings WHERE date <= sqlc.arg(date_to)::date), 0)::int AS end_odometer;
+
+-- name: GetFuelEconomy :one
+-- Fuel bought between two dates and economy over the full-tank fills among them, measured
+-- like GetFuelTimeseries.
+WITH fills AS (
+    SELECT date, liters, full_tank,
+           odometer - LAG(odometer) OVER (ORDER BY odometer, id) AS distance
+    FROM fuel_logs
+    WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+)
+SELECT COALESCE(SUM(liters), 0)::float8 AS liters,
+       COALESCE(SUM(distance) FILTER (WHERE full_tank AND distance > 0), 0)::float8 AS economy_distance,
+       COALESCE(SUM(liters) FILTER (WHERE full_tank AND distance > 0), 0)::float8 AS economy_liters
+FROM fills
+WHERE date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date;
+
+-- name: ListServiceRecordsBetween :many
+SELECT * FROM service_records
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date
+ORDER BY date, odometer, id;
+
+-- name: ListDashboardStats :many
+-- Current odometer from any source, economy of the latest full-tank fill and spend since a
+-- date, for every vehicle at once. Economy is 0/0 for vehicles without a measurable fill.
+WITH fills AS (
+    SELECT id, vehicle_id, odometer, liters, full_tank,
+           odometer - LAG(odometer) OVER (PARTITION BY vehicle_id ORDER BY odometer, id) AS distance
+    FROM fuel_logs
+),
+latest_fills AS (
+    SELECT DISTINCT ON (vehicle_id) vehicle_id, distance, liters
+    FROM fills
+    WHERE full_tank AND distance > 0 AND liters > 0
+    ORDER BY vehicle_id, odometer DESC, id DESC
+)
+SELECT v.id::int AS vehicle_id,
+       GREATEST(
+           (SELECT COALESCE(MAX(odometer), 0) FROM fuel_logs f WHERE f.vehicle_id = v.id),
+           (SELECT COALESCE(MAX(odometer), 0) FROM service_records s WHERE s.vehicle_id = v.id),
+           (SELECT COALESCE(MAX(odometer), 0) FROM odometer_readings o WHERE o.vehicle_id = v.id)
+       )::int AS odometer,
+       COALESCE(lf.distance, 0)::float8 AS economy_distance,
+       COALESCE(lf.liters, 0)::float8 AS economy_liters,
+       (SELECT COALESCE(SUM(total_cost), 0) FROM fuel_logs f
+        WHERE f.vehicle_id = v.id AND f.date >= sqlc.arg(since)::date)::float8 AS fuel_cost,
+       (SELECT COALESCE(SUM(cost), 0) FROM service_records s
+        WHERE s.vehicle_id = v.id AND s.date >= sqlc.arg(since)::date)::float8 AS service_cost,
+       (SELECT COALESCE(SUM(amount), 0) FROM expenses e
+        WHERE e.vehicle_id = v.id AND e.date >= sqlc.arg(since)::date)::float8 AS expense_cost
+FROM vehicles v
+LEFT JOIN latest_fills lf ON lf.vehicle_id = v.id
+ORDER BY v.id;
+
+-- name: ListRecentOdometerReadings :many
+-- ListOdometerReadings for every vehicle at once.
+WITH readings AS (
+    SELECT vehicle_id, date, odometer FROM fuel_logs WHERE date >= sqlc.arg(since)::date
+    UNION ALL
+    SELECT vehicle_id, date, odometer FROM service_records WHERE date >= sqlc.arg(since)::date
+    UNION ALL
+    SELECT vehicle_id, recorded_at::date, odometer FROM odometer_readings WHERE recorded_at >= sqlc.arg(since)::date
+)
+SELECT vehicle_id::int AS vehicle_id, date::date AS date, odometer::int AS odometer
+FROM readings
+WHERE vehicle_id IS NOT NULL
+ORDER BY vehicle_id, date, odometer;
+
+-- name: ListOpenReminders :many
+SELECT * FROM reminders
+WHERE is_completed = FALSE
+ORDER BY vehicle_id, due_date ASC;
+
+-- name: ListFullTankEconomy :many
+-- Economy of each full-tank fill of a vehicle, oldest first: the distance since the
+-- previous fill over the litres filled.
+WITH fills AS (
+    SELECT id, date, odometer, liters, full_tank,
+           odometer - LAG(odometer) OVER (ORDER BY odometer, id) AS distance
+    FROM fuel_logs
+    WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+)
+SELECT id::int AS id, date::date AS date, (distance / liters)::float8 AS economy
+FROM fills
+WHERE full_tank AND distance > 0 AND liters > 0
+ORDER BY odometer, id;
+
+-- name: CreateInsight :execrows
+-- Records an insight unless the vehicle already has an open one of the kind, or one was
+-- already raised (and perhaps dismissed) at the same fill.
+INSERT INTO vehicle_insights (vehicle_id, kind, fuel_log_id, baseline, recent, change_percent, z_score)
+SELECT sqlc.arg(vehicle_id)::int, sqlc.arg(kind)::text, sqlc.arg(fuel_log_id)::int,
+       sqlc.arg(baseline)::float8, sqlc.arg(recent)::float8, sqlc.arg(change_percent)::float8, sqlc.arg(z_score)::float8
+WHERE NOT EXISTS (
+    SELECT 1 FROM vehicle_insights
+    WHERE vehicle_id = sqlc.arg(vehicle_id)::int AND kind = sqlc.arg(kind)::text
+      AND (resolved_at IS NULL OR fuel_log_id = sqlc.arg(fuel_log_id)::int)
+);
+
+-- name: ResolveInsights :execrows
+UPDATE vehicle_insights
+SET resolved_at = NOW()
+WHERE vehicle_id = $1 AND kind = $2 AND resolved_at IS NULL;
+
+-- name: DismissInsight :one
+UPDATE vehicle_insights
+SET resolved_at = NOW()
+WHERE id = $1 AND resolved_at IS NULL
+RETURNING *;
+
+-- name: ListOpenInsights :many
+SELECT * FROM vehicle_insights
+WHERE vehicle_id = $1 AND resolved_at IS NULL
+ORDER BY created_at DESC, id DESC;
+
+-- name: CreateBudget :one
+INSERT INTO budgets (vehicle_id, category, period, amount, thresholds)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpdateBudget :one
+UPDATE budgets
+SET vehicle_id = $2, category = $3, period = $4, amount = $5, thresholds = $6
+WHERE id = $1
+RETURNING *;
+
+-- name: GetBudget :one
+SELECT * FROM budgets
+WHERE id = $1;
+
+-- name: ListBudgets :many
+SELECT * FROM budgets
+ORDER BY vehicle_id NULLS FIRST, category, id;
+
+-- name: DeleteBudget :exec
+DELETE FROM budgets WHERE id = $1;
+
+-- name: GetSpendBetween :one
+-- Fuel and service spend between two dates, inclusive, of one vehicle or all of them.
+SELECT
+    (SELECT COALESCE(SUM(total_cost), 0) FROM fuel_logs
+     WHERE (sqlc.narg(vehicle_id)::int IS NULL OR fuel_logs.vehicle_id = sqlc.narg(vehicle_id)::int)
+       AND date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date)::float8 AS fuel_cost,
+    (SELECT COALESCE(SUM(cost), 0) FROM service_records
+     WHERE (sqlc.narg(vehicle_id)::int IS NULL OR service_records.vehicle_id = sqlc.narg(vehicle_id)::int)
+       AND date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date)::float8 AS service_cost;
+
+-- name: ListFixedCosts :many
+-- Fixed costs of one vehicle, or of all of them when vehicle_id is NULL.
+SELECT * FROM fixed_costs
+WHERE sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int
+ORDER BY vehicle_id, start_date, id;
+
+-- name: RecordBudgetAlert :execrows
+INSERT INTO budget_alerts (budget_id, period_start, threshold)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: CreateVendor :one
+INSERT INTO vendors (name, website, phone, address, notes)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpdateVendor :one
+UPDATE vendors
+SET name = $2, website = $3, phone = $4, address = $5, notes = $6
+WHERE id = $1
+RETURNING *;
+
+-- name: GetVendor :one
+SELECT * FROM vendors
+WHERE id = $1;
+
+-- name: ListVendors :many
+SELECT * FROM vendors
+ORDER BY LOWER(name), id;
+
+-- name: DeleteVendor :exec
+DELETE FROM vendors WHERE id = $1;
+
+-- name: CreateExpense :one
+INSERT INTO expenses (vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: UpdateExpense :one
+UPDATE expenses
+SET vendor_id = $2, category = $3, date = $4, amount = $5, odometer = $6, description = $7,
+    recurrence = $8, recurrence_end_date = $9, next_due_date = $10
+WHERE id = $1
+RETURNING *;
+
+-- name: GetExpense :one
+SELECT * FROM expenses
+WHERE id = $1;
+
+-- name: ListExpensesByDateAsc :many
+-- One page of a vehicle's expenses, like ListFuelLogsByDateAsc.
+SELECT * FROM expenses
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR amount >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR amount <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(vendor_id)::int IS NULL OR vendor_id = sqlc.narg(vendor_id)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (date, id) > ((TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date, sqlc.narg(after_id)::int))
+ORDER BY date, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListExpensesByDateDesc :many
+-- Like ListExpensesByDateAsc, in descending order.
+SELECT * FROM expenses
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR amount >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR amount <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(vendor_id)::int IS NULL OR vendor_id = sqlc.narg(vendor_id)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (date, id) < ((TIMESTAMP 'epoch' + sqlc.narg(after_key)::float8 * INTERVAL '1 second')::date, sqlc.narg(after_id)::int))
+ORDER BY date DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: ListExpensesByAmountAsc :many
+-- Like ListExpensesByDateAsc, ordered by amount.
+SELECT * FROM expenses
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR amount >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR amount <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(vendor_id)::int IS NULL OR vendor_id = sqlc.narg(vendor_id)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (amount, id) > (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY amount, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListExpensesByAmountDesc :many
+-- Like ListExpensesByDateAsc, ordered by amount descending.
+SELECT * FROM expenses
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR amount >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR amount <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(vendor_id)::int IS NULL OR vendor_id = sqlc.narg(vendor_id)::int)
+  AND (sqlc.narg(after_key)::float8 IS NULL
+       OR (amount, id) < (sqlc.narg(after_key)::float8::numeric, sqlc.narg(after_id)::int))
+ORDER BY amount DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: CountExpensesByVehicle :one
+SELECT COUNT(*) FROM expenses
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND (sqlc.narg(date_from)::date IS NULL OR date >= sqlc.narg(date_from)::date)
+  AND (sqlc.narg(date_to)::date IS NULL OR date <= sqlc.narg(date_to)::date)
+  AND (sqlc.narg(odometer_min)::int IS NULL OR odometer >= sqlc.narg(odometer_min)::int)
+  AND (sqlc.narg(odometer_max)::int IS NULL OR odometer <= sqlc.narg(odometer_max)::int)
+  AND (sqlc.narg(cost_min)::float8 IS NULL OR amount >= sqlc.narg(cost_min)::float8)
+  AND (sqlc.narg(cost_max)::float8 IS NULL OR amount <= sqlc.narg(cost_max)::float8)
+  AND (sqlc.narg(category)::text IS NULL OR category = sqlc.narg(category)::text)
+  AND (sqlc.narg(vendor_id)::int IS NULL OR vendor_id = sqlc.narg(vendor_id)::int);
+
+-- name: DeleteExpense :exec
+DELETE FROM expenses WHERE id = $1;
+
+-- name: ListDueRecurringExpenses :many
+-- Recurring expenses with a copy due on or before today.
+SELECT * FROM expenses
+WHERE recurrence <> 'none' AND next_due_date <= sqlc.arg(today)::date
+ORDER BY next_due_date, id;
+
+-- name: CreateRecurringExpense :execrows
+-- Copies a recurring expense to the given date, unless a copy for that date exists.
+INSERT INTO expenses (vehicle_id, vendor_id, category, date, amount, description, recurring_expense_id)
+SELECT vehicle_id, vendor_id, category, sqlc.arg(date)::date, amount, description, id
+FROM expenses
+WHERE id = sqlc.arg(id)::int
+ON CONFLICT DO NOTHING;
+
+-- name: SetExpenseNextDue :exec
+UPDATE expenses
+SET next_due_date = $2
+WHERE id = $1;
+
+-- name: ListExpenseSpend :many
+-- Expense spend per category between two dates, inclusive, of one vehicle or all of them.
+SELECT category::text AS category, SUM(amount)::float8 AS amount
+FROM expenses
+WHERE (sqlc.narg(vehicle_id)::int IS NULL OR vehicle_id = sqlc.narg(vehicle_id)::int)
+  AND date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date
+GROUP BY category
+ORDER BY category;
+
+-- name: GetExpenseTimeseries :many
+SELECT date_trunc(sqlc.arg(period)::text, date::timestamp)::date AS period_start,
+       SUM(amount)::float8 AS expense_cost
+FROM expenses
+WHERE vehicle_id = sqlc.arg(vehicle_id)::int
+  AND date >= sqlc.arg(date_from)::date AND date <= sqlc.arg(date_to)::date
+GROUP BY 1
+ORDER BY 1;
+
+-- name: CreateExpenseFile :one
+INSERT INTO expense_files (expense_id, file_name, content_type, size_bytes, storage_key)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetExpenseFile :one
+SELECT * FROM expense_files
+WHERE id = $1;
+
+-- name: ListExpenseFiles :many
+SELECT * FROM expense_files
+WHERE expense_id = $1
+ORDER BY id;
+
+-- name: ListExpenseFilesByExpenses :many
+SELECT * FROM expense_files
+WHERE expense_id = ANY(sqlc.arg(expense_ids)::int[])
+ORDER BY expense_id, id;
+
+-- name: DeleteExpenseFile :exec
+DELETE FROM expense_files WHERE id = $1;
+
+-- name: CreateLoan :one
+INSERT INTO loans (vehicle_id, lender, principal, annual_rate, tenure_months, emi, start_date, first_emi_date, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: UpdateLoan :one
+UPDATE loans
+SET lender = $2, principal = $3, annual_rate = $4, tenure_months = $5, emi = $6,
+    start_date = $7, first_emi_date = $8, notes = $9
+WHERE id = $1
+RETURNING *;
+
+-- name: GetLoan :one
+SELECT * FROM loans
+WHERE id = $1;
+
+-- name: ListLoansByVehicle :many
+SELECT * FROM loans
+WHERE vehicle_id = $1
+ORDER BY start_date DESC, id DESC;
+
+-- name: ListLoans :many
+SELECT * FROM loans
+ORDER BY vehicle_id, start_date, id;
+
+-- name: DeleteLoan :exec
+DELETE FROM loans WHERE id = $1;
+
+-- name: CreateLoanPayment :one
+INSERT INTO loan_payments (loan_id, date, amount, prepayment, notes)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetLoanPayment :one
+SELECT * FROM loan_payments
+WHERE id = $1;
+
+-- name: ListLoanPayments :many
+SELECT * FROM loan_payments
+WHERE loan_id = $1
+ORDER BY date, id;
+
+-- name: ListLoanPaymentsByVehicle :many
+SELECT p.* FROM loan_payments p
+JOIN loans l ON l.id = p.loan_id
+WHERE l.vehicle_id = $1
+ORDER BY p.loan_id, p.date, p.id;
+
+-- name: ListAllLoanPayments :many
+SELECT * FROM loan_payments
+ORDER BY loan_id, date, id;
+
+-- name: DeleteLoanPayment :exec
+DELETE FROM loan_payments WHERE id = $1;
+
+-- name: RecordLoanReminder :execrows
+INSERT INTO loan_reminders (loan_id, due_date)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RecordDocumentAlert :execrows
+INSERT INTO document_alerts (document_id, expiry_date, level, target)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING;
+
+-- name: CreateValuation :one
+INSERT INTO vehicle_valuations (vehicle_id, date, value, source, notes)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpdateValuation :one
+UPDATE vehicle_valuations
+SET date = $2, value = $3, source = $4, notes = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: ListValuationsByVehicle :many
+SELECT * FROM vehicle_valuations
+WHERE vehicle_id = $1
+ORDER BY date, id;
+
+-- name: DeleteValuation :exec
+DELETE FROM vehicle_valuations WHERE id = $1;
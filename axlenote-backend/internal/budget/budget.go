@@ -0,0 +1,183 @@
+// Package budget tracks spend against monthly or yearly budgets, per vehicle or for the
+// whole garage.
+package budget
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/tco"
+)
+
+// Budget categories. Fuel and maintenance come from fuel logs and service records,
+// expenses from tolls, parking and the like, and the rest from fixed costs and expenses
+// of the same kind; total is all of them.
+const (
+	CategoryTotal       = "total"
+	CategoryFuel        = "fuel"
+	CategoryMaintenance = "maintenance"
+	CategoryInsurance   = tco.CategoryInsurance
+	CategoryTax         = tco.CategoryTax
+	CategoryFinancing   = tco.CategoryFinancing
+	CategoryExpenses    = tco.CategoryExpenses
+)
+
+// Budget periods.
+const (
+	PeriodMonthly = "monthly"
+	PeriodYearly  = "yearly"
+)
+
+// Budget statuses.
+const (
+	StatusOnTrack = "on_track" // spend is on course to stay within the budget
+	StatusAtRisk  = "at_risk"  // spend at the current pace will exceed it
+	StatusOver    = "over"
+)
+
+var (
+	Categories = []string{CategoryTotal, CategoryFuel, CategoryMaintenance, CategoryInsurance, CategoryTax, CategoryFinancing, CategoryExpenses}
+	Periods    = []string{PeriodMonthly, PeriodYearly}
+)
+
+// DefaultThresholds are the percentages of a budget alerted on when a budget does not set
+// its own, from BUDGET_THRESHOLDS (comma separated, default 80,100).
+func DefaultThresholds() []int32 {
+	var out []int32
+	for _, v := range strings.Split(os.Getenv("BUDGET_THRESHOLDS"), ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err == nil && n > 0 {
+			out = append(out, int32(n))
+		}
+	}
+	if len(out) == 0 {
+		return []int32{80, 100}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// Period returns the first and last day of the budget period that contains day.
+func Period(period string, day time.Time) (start, end time.Time) {
+	if period == PeriodYearly {
+		start = time.Date(day.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, -1)
+	}
+	start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, -1)
+}
+
+// Progress is spend against a budget in its current period.
+type Progress struct {
+	PeriodStart string  `json:"period_start"` // YYYY-MM-DD
+	PeriodEnd   string  `json:"period_end"`
+	Spent       float64 `json:"spent"`
+	Remaining   float64 `json:"remaining"` // negative once over budget
+	Percent     float64 `json:"percent"`
+	// Projected is the spend by the end of the period if it carries on at the same pace
+	Projected float64 `json:"projected"`
+	Status    string  `json:"status"`
+	// Crossed lists the thresholds spend has reached
+	Crossed []int32 `json:"crossed"`
+}
+
+// ForBudget works out the progress of a budget in the period containing today.
+func ForBudget(ctx context.Context, queries *repository.Queries, b repository.Budget, today time.Time) (Progress, error) {
+	start, end := Period(b.Period, today)
+	spent, err := Spent(ctx, queries, b, start, today)
+	if err != nil {
+		return Progress{}, err
+	}
+	amount, _ := strconv.ParseFloat(b.Amount, 64)
+
+	p := Progress{
+		PeriodStart: start.Format("2006-01-02"),
+		PeriodEnd:   end.Format("2006-01-02"),
+		Spent:       round2(spent),
+		Remaining:   round2(amount - spent),
+		Crossed:     []int32{},
+	}
+	if amount > 0 {
+		p.Percent = round2(spent / amount * 100)
+	}
+	elapsed := today.Sub(start).Hours()/24 + 1
+	total := end.Sub(start).Hours()/24 + 1
+	p.Projected = round2(spent / elapsed * total)
+
+	switch {
+	case spent > amount:
+		p.Status = StatusOver
+	case p.Projected > amount:
+		p.Status = StatusAtRisk
+	default:
+		p.Status = StatusOnTrack
+	}
+	for _, t := range b.Thresholds {
+		if p.Percent >= float64(t) {
+			p.Crossed = append(p.Crossed, t)
+		}
+	}
+	return p, nil
+}
+
+// Spent adds up spend in a budget's category between from and to, inclusive.
+func Spent(ctx context.Context, queries *repository.Queries, b repository.Budget, from, to time.Time) (float64, error) {
+	var spent float64
+	if b.Category == CategoryTotal || b.Category == CategoryFuel || b.Category == CategoryMaintenance {
+		spend, err := queries.GetSpendBetween(ctx, repository.GetSpendBetweenParams{
+			VehicleID: b.VehicleID,
+			DateFrom:  from,
+			DateTo:    to,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("spend: %w", err)
+		}
+		switch b.Category {
+		case CategoryFuel:
+			return spend.FuelCost, nil
+		case CategoryMaintenance:
+			return spend.ServiceCost, nil
+		}
+		spent = spend.FuelCost + spend.ServiceCost
+	}
+
+	expenses, err := queries.ListExpenseSpend(ctx, repository.ListExpenseSpendParams{
+		VehicleID: b.VehicleID,
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("expenses: %w", err)
+	}
+	for _, e := range expenses {
+		if b.Category == CategoryTotal || tco.ExpenseCost(e.Category) == b.Category {
+			spent += e.Amount
+		}
+	}
+
+	fixed, err := queries.ListFixedCosts(ctx, b.VehicleID)
+	if err != nil {
+		return 0, fmt.Errorf("fixed costs: %w", err)
+	}
+	for _, fc := range fixed {
+		// Depreciation is a loss of value rather than money spent
+		if fc.Category == tco.CategoryDepreciation {
+			continue
+		}
+		if b.Category == CategoryTotal || fc.Category == b.Category {
+			spent += tco.Accrued(fc, from, to)
+		}
+	}
+	return spent, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
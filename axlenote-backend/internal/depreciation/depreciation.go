@@ -0,0 +1,217 @@
+// Package depreciation estimates what a vehicle is worth on a date, from its purchase
+// price and a depreciation model or from the valuations recorded for it.
+package depreciation
+
+import (
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// Depreciation models.
+const (
+	ModelStraightLine     = "straight_line"     // the same share of the purchase price each year
+	ModelDecliningBalance = "declining_balance" // the same share of the remaining value each year
+	ModelValuations       = "valuations"        // between the values recorded for the vehicle
+)
+
+var Models = []string{ModelStraightLine, ModelDecliningBalance, ModelValuations}
+
+// Sources of the points in a value history.
+const (
+	SourcePurchase  = "purchase"
+	SourceEstimate  = "estimate"
+	SourceValuation = "valuation"
+	SourceSale      = "sale"
+)
+
+// maxHistoryMonths bounds a value history to 50 years of monthly points.
+const maxHistoryMonths = 600
+
+const daysPerYear = 365.25
+
+// DefaultRate is the yearly depreciation rate in percent, from DEPRECIATION_RATE
+// (default 15).
+func DefaultRate() float64 {
+	rate, err := strconv.ParseFloat(os.Getenv("DEPRECIATION_RATE"), 64)
+	if err != nil || rate < 0 || rate > 100 {
+		return 15
+	}
+	return rate
+}
+
+// ModelOf is the model a vehicle's value is estimated with, declining balance unless set.
+func ModelOf(v repository.Vehicle) string {
+	if slices.Contains(Models, v.DepreciationModel.String) {
+		return v.DepreciationModel.String
+	}
+	return ModelDecliningBalance
+}
+
+// RateOf is the yearly depreciation rate of a vehicle in percent, DefaultRate unless set.
+func RateOf(v repository.Vehicle) float64 {
+	if v.DepreciationRate.Valid {
+		if rate, err := strconv.ParseFloat(v.DepreciationRate.String, 64); err == nil {
+			return rate
+		}
+	}
+	return DefaultRate()
+}
+
+// Point is a vehicle's value on a date.
+type Point struct {
+	Date   string  `json:"date"` // YYYY-MM-DD
+	Value  float64 `json:"value"`
+	Source string  `json:"source"` // purchase, estimate, valuation or sale
+	date   time.Time
+}
+
+// Estimate works out a vehicle's value on any date.
+type Estimate struct {
+	Model         string
+	PurchasePrice float64
+	PurchaseDate  time.Time
+	Rate          float64   // percent per year
+	Residual      float64   // the value rate-based models stop at
+	SaleDate      time.Time // zero unless sold or scrapped
+	SalePrice     *float64  // nil when not recorded
+	anchors       []Point   // purchase and valuations, oldest first
+}
+
+// ForVehicle sets up the estimate of a vehicle's value from its purchase details and
+// valuations, oldest first. It returns false when there is nothing to estimate from: no
+// purchase price and date for the rate-based models, and neither those nor a valuation
+// for the valuations model.
+func ForVehicle(v repository.Vehicle, valuations []repository.VehicleValuation) (Estimate, bool) {
+	e := Estimate{Model: ModelOf(v), Rate: RateOf(v)}
+	e.Residual, _ = strconv.ParseFloat(v.ResidualValue.String, 64)
+	if v.SaleDate.Valid {
+		e.SaleDate = v.SaleDate.Time
+		if v.SalePrice.Valid {
+			price, _ := strconv.ParseFloat(v.SalePrice.String, 64)
+			e.SalePrice = &price
+		}
+	}
+	purchased := v.PurchasePrice.Valid && v.PurchaseDate.Valid
+	if purchased {
+		e.PurchasePrice, _ = strconv.ParseFloat(v.PurchasePrice.String, 64)
+		e.PurchaseDate = v.PurchaseDate.Time
+		e.anchors = append(e.anchors, point(e.PurchaseDate, e.PurchasePrice, SourcePurchase))
+	}
+	for _, val := range valuations {
+		if purchased && val.Date.Before(e.PurchaseDate) {
+			continue
+		}
+		value, _ := strconv.ParseFloat(val.Value, 64)
+		e.anchors = append(e.anchors, point(val.Date, value, SourceValuation))
+	}
+	if e.Model == ModelValuations {
+		return e, len(e.anchors) > 0
+	}
+	return e, purchased
+}
+
+// ValueAt is the value at the start of a day. Before the purchase it is the purchase
+// price, and from the sale on the sale price, or the value on the sale date when the
+// price was not recorded. The valuations model runs in a straight line from one recorded
+// value to the next and stays at the last one.
+func (e Estimate) ValueAt(t time.Time) float64 {
+	if !e.SaleDate.IsZero() && !t.Before(e.SaleDate) {
+		if e.SalePrice != nil {
+			return *e.SalePrice
+		}
+		t = e.SaleDate
+	}
+	if e.Model == ModelValuations {
+		return e.interpolate(t)
+	}
+	if !t.After(e.PurchaseDate) {
+		return e.PurchasePrice
+	}
+	years := t.Sub(e.PurchaseDate).Hours() / 24 / daysPerYear
+	var value float64
+	if e.Model == ModelStraightLine {
+		value = e.PurchasePrice * (1 - e.Rate/100*years)
+	} else {
+		value = e.PurchasePrice * math.Pow(1-e.Rate/100, years)
+	}
+	return math.Max(value, math.Min(e.Residual, e.PurchasePrice))
+}
+
+func (e Estimate) interpolate(t time.Time) float64 {
+	i, _ := slices.BinarySearchFunc(e.anchors, t, func(p Point, t time.Time) int { return p.date.Compare(t) })
+	switch {
+	case i == 0:
+		return e.anchors[0].Value
+	case i == len(e.anchors):
+		return e.anchors[i-1].Value
+	}
+	prev, next := e.anchors[i-1], e.anchors[i]
+	span := next.date.Sub(prev.date).Hours()
+	if span == 0 {
+		return next.Value
+	}
+	return prev.Value + (next.Value-prev.Value)*t.Sub(prev.date).Hours()/span
+}
+
+// Between is the value lost between from and to, inclusive. It is negative when the
+// vehicle gained value.
+func (e Estimate) Between(from, to time.Time) float64 {
+	return e.ValueAt(from) - e.ValueAt(to.AddDate(0, 0, 1))
+}
+
+// History lists the value month by month from the purchase, or the first valuation, up
+// to the given date or the sale, along with every valuation recorded in that time.
+func (e Estimate) History(to time.Time) []Point {
+	sold := !e.SaleDate.IsZero() && !to.Before(e.SaleDate)
+	if sold {
+		to = e.SaleDate
+	}
+	start := e.PurchaseDate
+	if len(e.anchors) > 0 {
+		start = e.anchors[0].date
+	}
+	if oldest := to.AddDate(0, -maxHistoryMonths, 0); start.Before(oldest) {
+		start = oldest
+	}
+
+	var history []Point
+	for n := 0; ; n++ {
+		d := start.AddDate(0, n, 0)
+		if !d.Before(to) {
+			break
+		}
+		history = append(history, point(d, e.ValueAt(d), SourceEstimate))
+	}
+	last := SourceEstimate
+	if sold && e.SalePrice != nil {
+		last = SourceSale
+	}
+	history = append(history, point(to, e.ValueAt(to), last))
+
+	// Recorded values replace the estimate of their day, up to the sale
+	for _, a := range e.anchors {
+		if a.date.Before(start) || a.date.After(to) || (sold && !a.date.Before(to)) {
+			continue
+		}
+		i, found := slices.BinarySearchFunc(history, a.date, func(p Point, t time.Time) int { return p.date.Compare(t) })
+		if found {
+			history[i] = a
+		} else {
+			history = slices.Insert(history, i, a)
+		}
+	}
+	return history
+}
+
+func point(t time.Time, value float64, source string) Point {
+	return Point{Date: t.Format("2006-01-02"), Value: round2(value), Source: source, date: t}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,118 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Digest periods, matching the subscription digest modes.
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+// SpendDays is how many days of fuel spend a digest of the period summarises.
+func SpendDays(period string) int {
+	if period == PeriodWeekly {
+		return 7
+	}
+	return 1
+}
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var (
+	textTemplate = texttemplate.Must(texttemplate.New("digest.txt.tmpl").Funcs(texttemplate.FuncMap(funcs)).ParseFS(templateFS, "templates/digest.txt.tmpl"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("digest.html.tmpl").Funcs(htmltemplate.FuncMap(funcs)).ParseFS(templateFS, "templates/digest.html.tmpl"))
+)
+
+var funcs = map[string]any{
+	"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"date":  func(t time.Time) string { return t.Format("2006-01-02") },
+}
+
+// Digest is the data handed to the digest templates.
+type Digest struct {
+	Period      string
+	GeneratedAt time.Time
+	Currency    string
+	SpendDays   int // window the fuel spend covers
+	Vehicles    []VehicleSummary
+}
+
+type VehicleSummary struct {
+	ID                int32
+	Name              string
+	Overdue           []ReminderItem
+	Upcoming          []ReminderItem
+	ExpiringDocuments []DocumentItem
+	FuelSpend         float64
+	FuelLiters        float64
+	FillUps           int64
+}
+
+type ReminderItem struct {
+	Title   string
+	Type    string
+	Trigger string
+}
+
+type DocumentItem struct {
+	Name       string
+	Type       string
+	ExpiryDate time.Time
+	DaysLeft   int
+}
+
+func (v VehicleSummary) Empty() bool {
+	return len(v.Overdue) == 0 && len(v.Upcoming) == 0 && len(v.ExpiringDocuments) == 0 && v.FillUps == 0
+}
+
+// Empty reports whether there is nothing worth sending.
+func (d Digest) Empty() bool {
+	for _, v := range d.Vehicles {
+		if !v.Empty() {
+			return false
+		}
+	}
+	return true
+}
+
+func (d Digest) Title() string {
+	overdue := 0
+	for _, v := range d.Vehicles {
+		overdue += len(v.Overdue)
+	}
+	label := "Daily"
+	if d.Period == PeriodWeekly {
+		label = "Weekly"
+	}
+	if overdue > 0 {
+		return fmt.Sprintf("AxleNote %s Digest: %d overdue", label, overdue)
+	}
+	return fmt.Sprintf("AxleNote %s Digest", label)
+}
+
+// RenderText renders the plain-text digest used for push notifications.
+func RenderText(d Digest) (string, error) {
+	var buf bytes.Buffer
+	if err := textTemplate.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// RenderHTML renders the HTML digest used for email.
+func RenderHTML(d Digest) (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
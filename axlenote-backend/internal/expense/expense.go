@@ -0,0 +1,68 @@
+// Package expense holds the categories of running costs that are neither fuel nor
+// maintenance, and works out when a recurring expense is next due.
+package expense
+
+import "time"
+
+// Expense categories.
+const (
+	CategoryToll      = "toll"
+	CategoryParking   = "parking"
+	CategoryWash      = "wash"
+	CategoryInsurance = "insurance"
+	CategoryTax       = "tax"
+	CategoryLoan      = "loan" // EMIs and other loan repayments
+	CategoryFine      = "fine"
+	CategoryOther     = "other"
+)
+
+// Recurrences. A recurring expense is copied by the scheduler every month or year.
+const (
+	RecurrenceNone    = "none"
+	RecurrenceMonthly = "monthly"
+	RecurrenceYearly  = "yearly"
+)
+
+var (
+	Categories  = []string{CategoryToll, CategoryParking, CategoryWash, CategoryInsurance, CategoryTax, CategoryLoan, CategoryFine, CategoryOther}
+	Recurrences = []string{RecurrenceNone, RecurrenceMonthly, RecurrenceYearly}
+)
+
+// Occurrence returns the nth date of a series starting on start, 0 being start itself.
+// Days past the end of a shorter month fall on its last day, so a series starting on
+// the 31st is due on 28 or 29 February and back on the 31st in March.
+func Occurrence(start time.Time, recurrence string, n int) time.Time {
+	months := n
+	if recurrence == RecurrenceYearly {
+		months = n * 12
+	}
+	first := time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(start.Day(), last)-1)
+}
+
+// NextDue returns the first date of a series starting on start that falls after the
+// given day, or false for expenses that do not recur or whose series has ended.
+func NextDue(start time.Time, recurrence string, end *time.Time, after time.Time) (time.Time, bool) {
+	if recurrence != RecurrenceMonthly && recurrence != RecurrenceYearly {
+		return time.Time{}, false
+	}
+	n := 1
+	if after.After(start) {
+		// Jump close to the answer rather than walking from the start
+		months := (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+		if recurrence == RecurrenceYearly {
+			months /= 12
+		}
+		n = max(months, 1)
+	}
+	next := Occurrence(start, recurrence, n)
+	for !next.After(after) {
+		n++
+		next = Occurrence(start, recurrence, n)
+	}
+	if end != nil && next.After(*end) {
+		return time.Time{}, false
+	}
+	return next, true
+}
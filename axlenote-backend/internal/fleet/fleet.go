@@ -0,0 +1,251 @@
+// Package fleet compares vehicles side by side. Distances are converted to one unit, and
+// fuel to the litres of petrol holding the same energy, so a diesel car, a motorcycle and
+// an electric car can be set against each other.
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/tco"
+)
+
+// Fuel types. Fuel logs record litres, except kg for CNG and kWh for electric vehicles.
+const (
+	FuelPetrol   = "petrol"
+	FuelDiesel   = "diesel"
+	FuelLPG      = "lpg"
+	FuelCNG      = "cng"
+	FuelE85      = "e85"
+	FuelElectric = "electric"
+)
+
+// Distance units, as stored on vehicles and in METRICS_UNIT.
+const (
+	UnitKm    = "km"
+	UnitMiles = "miles"
+)
+
+// Vehicle statuses. Sold and scrapped vehicles are archived: they drop out of the vehicle
+// list by default but stay in reports. Only active vehicles get reminders.
+const (
+	StatusActive   = "active"
+	StatusStored   = "stored"
+	StatusSold     = "sold"
+	StatusScrapped = "scrapped"
+)
+
+var (
+	FuelTypes = []string{FuelPetrol, FuelDiesel, FuelLPG, FuelCNG, FuelE85, FuelElectric}
+	Units     = []string{UnitKm, UnitMiles}
+	Statuses  = []string{StatusActive, StatusStored, StatusSold, StatusScrapped}
+)
+
+const kmPerMile = 1.609344
+
+// petrolEquivalent is how many litres of petrol hold the energy of one logged unit of
+// each fuel, from typical energy densities (petrol 34.2 MJ/L).
+var petrolEquivalent = map[string]float64{
+	FuelPetrol:   1,
+	FuelDiesel:   38.6 / 34.2,
+	FuelLPG:      25.3 / 34.2,
+	FuelCNG:      48.0 / 34.2, // per kg
+	FuelE85:      25.2 / 34.2,
+	FuelElectric: 3.6 / 34.2, // per kWh
+}
+
+// fuelUnits is the unit fuel logs are kept in for each fuel type.
+var fuelUnits = map[string]string{
+	FuelCNG:      "kg",
+	FuelElectric: "kWh",
+}
+
+// DefaultUnit is the distance unit from METRICS_UNIT.
+func DefaultUnit() string {
+	if os.Getenv("METRICS_UNIT") == UnitMiles {
+		return UnitMiles
+	}
+	return UnitKm
+}
+
+// UnitOf is the unit a vehicle's odometer reads in.
+func UnitOf(v repository.Vehicle) string {
+	if v.DistanceUnit.String == UnitMiles || v.DistanceUnit.String == UnitKm {
+		return v.DistanceUnit.String
+	}
+	return DefaultUnit()
+}
+
+// IsActive reports whether a vehicle is in use, rather than stored or gone.
+func IsActive(v repository.Vehicle) bool {
+	return v.Status == StatusActive
+}
+
+// IsArchived reports whether a vehicle has been sold or scrapped.
+func IsArchived(v repository.Vehicle) bool {
+	return v.Status == StatusSold || v.Status == StatusScrapped
+}
+
+// FuelOf is the fuel a vehicle runs on, petrol unless set.
+func FuelOf(v repository.Vehicle) string {
+	if _, ok := petrolEquivalent[v.FuelType.String]; ok {
+		return v.FuelType.String
+	}
+	return FuelPetrol
+}
+
+// convert converts a distance between units.
+func convert(distance float64, from, to string) float64 {
+	switch {
+	case from == UnitMiles && to == UnitKm:
+		return distance * kmPerMile
+	case from == UnitKm && to == UnitMiles:
+		return distance / kmPerMile
+	}
+	return distance
+}
+
+// Comparison is how one vehicle did over a date range, with distances in a common unit.
+type Comparison struct {
+	VehicleID    int32   `json:"vehicle_id"`
+	VehicleName  string  `json:"vehicle_name"`
+	VehicleType  string  `json:"vehicle_type"`
+	FuelType     string  `json:"fuel_type"`
+	FuelUnit     string  `json:"fuel_unit"` // L, kg or kWh
+	OdometerUnit string  `json:"odometer_unit"`
+	Distance     float64 `json:"distance"`
+	FuelUsed     float64 `json:"fuel_used"`
+
+	// Economy is distance per fuel unit over full-tank fills; PetrolEquivalentEconomy is
+	// the same per litre of petrol with the same energy, comparable across fuel types.
+	Economy                 *float64 `json:"economy"`
+	PetrolEquivalentEconomy *float64 `json:"petrol_equivalent_economy"`
+
+	CostPerDistance     *float64 `json:"cost_per_distance"` // all-in, as in the cost of ownership report
+	FuelCostPerDistance *float64 `json:"fuel_cost_per_distance"`
+
+	Services int `json:"services"` // visits, counting records on the same day once
+	// ServicesPer10k is visits per 10,000 distance units driven
+	ServicesPer10k          *float64 `json:"services_per_10k"`
+	DaysBetweenServices     *float64 `json:"days_between_services"`
+	DistanceBetweenServices *float64 `json:"distance_between_services"`
+	// DowntimeDays is how long the vehicle was off the road for services, from each visit's
+	// date to its completed date. Visits without a completed date count as same-day.
+	DowntimeDays float64 `json:"downtime_days"`
+
+	Spend              tco.Costs          `json:"spend"`
+	MaintenanceByType  map[string]float64 `json:"maintenance_by_type"`
+	MaintenancePerYear float64            `json:"maintenance_per_year"`
+}
+
+// Compare works out the comparison of a vehicle between from and to, inclusive, with
+// distances in unit.
+func Compare(ctx context.Context, queries *repository.Queries, vehicle repository.Vehicle, from, to time.Time, unit string) (Comparison, error) {
+	fuel := FuelOf(vehicle)
+	native := UnitOf(vehicle)
+	cmp := Comparison{
+		VehicleID:         vehicle.ID,
+		VehicleName:       vehicle.Name,
+		VehicleType:       vehicle.Type.String,
+		FuelType:          fuel,
+		FuelUnit:          "L",
+		OdometerUnit:      native,
+		MaintenanceByType: map[string]float64{},
+	}
+	if u, ok := fuelUnits[fuel]; ok {
+		cmp.FuelUnit = u
+	}
+
+	report, err := tco.ForVehicle(ctx, queries, vehicle, from, to)
+	if err != nil {
+		return cmp, err
+	}
+	cmp.Spend = report.Costs
+	distance := convert(float64(report.Distance), native, unit)
+	cmp.Distance = round2(distance)
+
+	economy, err := queries.GetFuelEconomy(ctx, repository.GetFuelEconomyParams{
+		VehicleID: vehicle.ID,
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return cmp, fmt.Errorf("fuel economy: %w", err)
+	}
+	cmp.FuelUsed = round2(economy.Liters)
+	if economy.EconomyLiters > 0 {
+		perUnit := convert(economy.EconomyDistance, native, unit) / economy.EconomyLiters
+		cmp.Economy = ptr(round2(perUnit))
+		cmp.PetrolEquivalentEconomy = ptr(round2(perUnit / petrolEquivalent[fuel]))
+	}
+	if distance > 0 {
+		cmp.CostPerDistance = ptr(round2(report.Costs.Total / distance))
+		cmp.FuelCostPerDistance = ptr(round2(report.Costs.Fuel / distance))
+	}
+
+	records, err := queries.ListServiceRecordsBetween(ctx, repository.ListServiceRecordsBetweenParams{
+		VehicleID: vehicle.ID,
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return cmp, fmt.Errorf("service records: %w", err)
+	}
+	var visits []repository.ServiceRecord
+	for _, r := range records {
+		cost, _ := strconv.ParseFloat(r.Cost, 64)
+		kind := r.ServiceType.String
+		if kind == "" {
+			kind = "other"
+		}
+		cmp.MaintenanceByType[kind] = round2(cmp.MaintenanceByType[kind] + cost)
+
+		if n := len(visits); n > 0 && visits[n-1].Date.Equal(r.Date) {
+			visits[n-1].Odometer = max(visits[n-1].Odometer, r.Odometer)
+			if r.CompletedDate.Valid && (!visits[n-1].CompletedDate.Valid || r.CompletedDate.Time.After(visits[n-1].CompletedDate.Time)) {
+				visits[n-1].CompletedDate = r.CompletedDate
+			}
+			continue
+		}
+		visits = append(visits, r)
+	}
+	cmp.Services = len(visits)
+	for _, v := range visits {
+		if !v.CompletedDate.Valid {
+			continue
+		}
+		// Only the part of the time off the road that falls within the range counts
+		back := v.CompletedDate.Time
+		if back.After(to) {
+			back = to
+		}
+		cmp.DowntimeDays += back.Sub(v.Date).Hours() / 24
+	}
+	cmp.DowntimeDays = round2(cmp.DowntimeDays)
+	cmp.MaintenancePerYear = round2(report.Costs.Maintenance / (float64(report.Days) / 365.25))
+	if distance > 0 {
+		cmp.ServicesPer10k = ptr(round2(float64(len(visits)) / distance * 10000))
+	}
+	if n := len(visits); n > 1 {
+		days := visits[n-1].Date.Sub(visits[0].Date).Hours() / 24
+		cmp.DaysBetweenServices = ptr(round2(days / float64(n-1)))
+		driven := convert(float64(visits[n-1].Odometer-visits[0].Odometer), native, unit)
+		if driven > 0 {
+			cmp.DistanceBetweenServices = ptr(round2(driven / float64(n-1)))
+		}
+	}
+	return cmp, nil
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,141 @@
+// Package forecast estimates how fast a vehicle is driven and when odometer-based
+// reminders will fall due.
+package forecast
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+const (
+	// WindowDays is how much recent history the driving rate is based on.
+	WindowDays = 90
+	// minSpanDays avoids wild estimates from two fill-ups a day apart.
+	minSpanDays = 7
+)
+
+// Rate is a vehicle's recent driving rate.
+type Rate struct {
+	KmPerDay float64
+	Odometer int32     // latest reading
+	AsOf     time.Time // date of the latest reading
+}
+
+// FromReadings estimates the driving rate from readings sorted oldest first.
+// It reports false when there is not enough history to say anything useful.
+func FromReadings(readings []repository.ListOdometerReadingsRow) (Rate, bool) {
+	if len(readings) < 2 {
+		return Rate{}, false
+	}
+
+	first := readings[0]
+	last := first
+	for _, r := range readings[1:] {
+		// Readings are sorted by date, keep the highest odometer on the latest day
+		if r.Odometer >= last.Odometer {
+			last = r
+		}
+	}
+
+	days := last.Date.Sub(first.Date).Hours() / 24
+	distance := float64(last.Odometer - first.Odometer)
+	if days < minSpanDays || distance <= 0 {
+		return Rate{}, false
+	}
+
+	return Rate{KmPerDay: distance / days, Odometer: last.Odometer, AsOf: last.Date}, true
+}
+
+// ForVehicle loads recent readings and estimates the driving rate of a vehicle, projecting
+// from its highest odometer on record.
+func ForVehicle(ctx context.Context, queries *repository.Queries, vehicleID int32, now time.Time) (Rate, bool, error) {
+	readings, err := queries.ListOdometerReadings(ctx, repository.ListOdometerReadingsParams{
+		VehicleID: sql.NullInt32{Int32: vehicleID, Valid: true},
+		Since:     now.AddDate(0, 0, -WindowDays),
+	})
+	if err != nil {
+		return Rate{}, false, err
+	}
+	rate, ok := FromReadings(readings)
+	if !ok {
+		return rate, false, nil
+	}
+	odometer, err := queries.GetVehicleOdometer(ctx, vehicleID)
+	if err != nil {
+		return Rate{}, false, err
+	}
+	return rate.From(odometer), true, nil
+}
+
+// ForVehicles estimates the driving rate of every vehicle with enough history in one query.
+func ForVehicles(ctx context.Context, queries *repository.Queries, now time.Time) (map[int32]Rate, error) {
+	readings, err := queries.ListRecentOdometerReadings(ctx, now.AddDate(0, 0, -WindowDays))
+	if err != nil {
+		return nil, err
+	}
+	byVehicle := make(map[int32][]repository.ListOdometerReadingsRow)
+	for _, r := range readings {
+		byVehicle[r.VehicleID] = append(byVehicle[r.VehicleID], repository.ListOdometerReadingsRow{Date: r.Date, Odometer: r.Odometer})
+	}
+	rates := make(map[int32]Rate, len(byVehicle))
+	for id, rows := range byVehicle {
+		if rate, ok := FromReadings(rows); ok {
+			rates[id] = rate
+		}
+	}
+	return rates, nil
+}
+
+// Today is the current local date at midnight UTC, matching how DATE columns are scanned.
+func Today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// From returns the rate projecting from odometer when that is higher than the latest
+// reading in the window, so projections start from the true current reading.
+func (r Rate) From(odometer int32) Rate {
+	if odometer > r.Odometer {
+		r.Odometer = odometer
+	}
+	return r
+}
+
+// DueDate projects when the odometer will reach dueOdometer. Projections that
+// land in the past (the vehicle has not been logged for a while) are clamped to today.
+func (r Rate) DueDate(dueOdometer int32, today time.Time) time.Time {
+	remaining := float64(dueOdometer - r.Odometer)
+	if remaining <= 0 {
+		return today
+	}
+	days := int(math.Ceil(remaining / r.KmPerDay))
+	due := r.AsOf.AddDate(0, 0, days)
+	if due.Before(today) {
+		return today
+	}
+	return due
+}
+
+// DaysUntil is the number of whole days from today until the projected due date.
+func (r Rate) DaysUntil(dueOdometer int32, today time.Time) int {
+	return int(r.DueDate(dueOdometer, today).Sub(today).Hours() / 24)
+}
+
+// ReminderDue returns when a reminder falls due: its due date, or for odometer reminders
+// the projected date if that comes first. projected reports which one was used, and ok
+// is false when neither is known. Pass hasRate false when the vehicle has no driving rate.
+func ReminderDue(rem repository.Reminder, rate Rate, hasRate bool, today time.Time) (due time.Time, projected bool, ok bool) {
+	if rem.DueDate.Valid {
+		due, ok = rem.DueDate.Time, true
+	}
+	if hasRate && rem.DueOdometer.Valid && rem.DueOdometer.Int32 > 0 {
+		if p := rate.DueDate(rem.DueOdometer.Int32, today); !ok || p.Before(due) {
+			return p, true, true
+		}
+	}
+	return due, false, ok
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Activity types, as returned in ActivityResponse.Type and accepted by ?type=.
+const (
+	ActivityFuelLog           = "fuel_log"
+	ActivityServiceRecord     = "service_record"
+	ActivityExpense           = "expense"
+	ActivityReminderCompleted = "reminder_completed"
+	ActivityDocumentUpload    = "document_upload"
+)
+
+var activityTypes = []string{ActivityFuelLog, ActivityServiceRecord, ActivityExpense, ActivityReminderCompleted, ActivityDocumentUpload}
+
+type ActivityResponse struct {
+	Type        string  `json:"type"`
+	ID          int32   `json:"id"` // of the fuel log, service record, expense, reminder or document file
+	VehicleID   int32   `json:"vehicle_id"`
+	VehicleName string  `json:"vehicle_name"`
+	OccurredAt  string  `json:"occurred_at"` // RFC 3339
+	Title       string  `json:"title"`
+	Amount      float64 `json:"amount,omitempty"`   // cost of fuel logs, service records and expenses
+	Odometer    int32   `json:"odometer,omitempty"` // reading, or the due odometer of a reminder
+}
+
+var activitySorts = sortKeys[repository.ListActivityDescRow]{
+	"occurred_at": func(a repository.ListActivityDescRow) float64 { return a.SortKey },
+}
+
+// listActivity runs the timeline query for the direction. The two queries return the same
+// columns, so oldest-first rows are converted to the newest-first row type.
+func (h *Handler) listActivity(ctx context.Context, desc bool, p repository.ListActivityDescParams) ([]repository.ListActivityDescRow, error) {
+	if desc {
+		return h.queries.ListActivityDesc(ctx, p)
+	}
+	asc, err := h.queries.ListActivityAsc(ctx, repository.ListActivityAscParams(p))
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]repository.ListActivityDescRow, len(asc))
+	for i, a := range asc {
+		rows[i] = repository.ListActivityDescRow(a)
+	}
+	return rows, nil
+}
+
+// ListActivity returns a timeline of fuel logs, service records, expenses, reminder
+// completions and document uploads across the garage, newest first. Narrow it with
+// ?vehicle_id= and ?type= (both comma separated), ?from= and ?to=, and ?user_id= for the
+// vehicles a user follows through their subscriptions.
+func (h *Handler) ListActivity(c *fiber.Ctx) error {
+	opts, msg := parseListOptions(c, activitySorts, "-occurred_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	params := repository.ListActivityDescParams{
+		DateFrom: opts.dateFrom,
+		DateTo:   opts.dateTo,
+		AfterKey: opts.afterKey(),
+		AfterID:  opts.afterID(),
+		Limit:    opts.fetchLimit(),
+	}
+	for _, t := range splitList(c.Query("type")) {
+		if !slices.Contains(activityTypes, t) {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid type, use one of " + strings.Join(activityTypes, ", ")})
+		}
+		params.Types = append(params.Types, t)
+	}
+	for _, v := range splitList(c.Query("vehicle_id")) {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+		}
+		params.VehicleIds = append(params.VehicleIds, int32(id))
+	}
+
+	vehicles, err := h.queries.ListVehicles(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+	}
+	if v := c.Query("user_id"); v != "" {
+		userID, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+		}
+		if _, err := h.queries.GetUser(c.Context(), int32(userID)); err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+		}
+		visible, err := h.feedVehicles(c.Context(), int32(userID))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+		}
+
+		var allowed []int32
+		for _, v := range visible {
+			if len(params.VehicleIds) == 0 || slices.Contains(params.VehicleIds, v.ID) {
+				allowed = append(allowed, v.ID)
+			}
+		}
+		if len(allowed) == 0 {
+			// An empty list would mean "all vehicles" to the query
+			meta := ListMeta{Limit: opts.limit, Sort: opts.sortParam()}
+			return c.JSON(fiber.Map{"data": []ActivityResponse{}, "meta": meta})
+		}
+		params.VehicleIds = allowed
+	}
+
+	rows, err := h.listActivity(c.Context(), opts.desc, params)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch activity", "details": err.Error()})
+	}
+	total, err := h.queries.CountActivity(c.Context(), repository.CountActivityParams{
+		VehicleIds: params.VehicleIds,
+		Types:      params.Types,
+		DateFrom:   params.DateFrom,
+		DateTo:     params.DateTo,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch activity", "details": err.Error()})
+	}
+	rows, meta := pageOf(rows, opts, total, activitySorts, func(a repository.ListActivityDescRow) int32 { return a.ID })
+
+	names := make(map[int32]string, len(vehicles))
+	for _, v := range vehicles {
+		names[v.ID] = v.Name
+	}
+	response := make([]ActivityResponse, len(rows))
+	for i, a := range rows {
+		response[i] = ActivityResponse{
+			Type:        a.Type,
+			ID:          a.ID,
+			VehicleID:   a.VehicleID,
+			VehicleName: names[a.VehicleID],
+			OccurredAt:  a.OccurredAt.UTC().Format(time.RFC3339),
+			Title:       a.Title,
+			Amount:      a.Amount,
+			Odometer:    a.Odometer,
+		}
+	}
+
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+// splitList splits a comma separated query value, ignoring empty items.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/scheduler"
+	"github.com/gofiber/fiber/v2"
+)
+
+type JobRunResponse struct {
+	ID         int32   `json:"id"`
+	JobName    string  `json:"job_name"`
+	Trigger    string  `json:"trigger"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt *string `json:"finished_at,omitempty"`
+}
+
+type JobResponse struct {
+	scheduler.JobInfo
+	LastRun *JobRunResponse `json:"last_run,omitempty"`
+}
+
+func mapJobRunToResponse(r repository.JobRun) JobRunResponse {
+	resp := JobRunResponse{
+		ID:        r.ID,
+		JobName:   r.JobName,
+		Trigger:   r.Trigger,
+		Status:    r.Status,
+		Error:     r.Error.String,
+		StartedAt: r.StartedAt.Format(time.RFC3339),
+	}
+	if r.FinishedAt.Valid {
+		finished := r.FinishedAt.Time.Format(time.RFC3339)
+		resp.FinishedAt = &finished
+	}
+	return resp
+}
+
+// AdminAuth guards the admin routes with ADMIN_TOKEN when it is set.
+func AdminAuth() fiber.Handler {
+	token := os.Getenv("ADMIN_TOKEN")
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return c.Next()
+		}
+		given := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+		}
+		return c.Next()
+	}
+}
+
+// ListJobs returns every scheduler job with its schedule and most recent run.
+func (h *Handler) ListJobs(c *fiber.Ctx) error {
+	latest, err := h.queries.ListLatestJobRuns(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch job runs", "details": err.Error()})
+	}
+	lastRun := make(map[string]repository.JobRun)
+	for _, r := range latest {
+		lastRun[r.JobName] = r
+	}
+
+	var response []JobResponse
+	for _, info := range h.scheduler.Jobs() {
+		job := JobResponse{JobInfo: info}
+		if r, ok := lastRun[info.Name]; ok {
+			run := mapJobRunToResponse(r)
+			job.LastRun = &run
+		}
+		response = append(response, job)
+	}
+	return c.JSON(fiber.Map{"data": response, "leader": h.scheduler.IsLeader()})
+}
+
+// RunJob starts a job immediately. The job runs in the background; poll the run history for the result.
+func (h *Handler) RunJob(c *fiber.Ctx) error {
+	run, err := h.scheduler.Trigger(c.Params("name"))
+	if errors.Is(err, scheduler.ErrUnknownJob) {
+		return c.Status(404).JSON(fiber.Map{"error": "Job not found"})
+	}
+	if errors.Is(err, scheduler.ErrJobRunning) {
+		return c.Status(409).JSON(fiber.Map{"error": "Job is already running"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to start job", "details": err.Error()})
+	}
+	return c.Status(202).JSON(fiber.Map{"data": mapJobRunToResponse(run)})
+}
+
+var jobRunSorts = sortKeys[repository.JobRun]{
+	"started_at": func(r repository.JobRun) float64 { return timestampKey(r.StartedAt) },
+}
+
+// listJobRuns runs the job run query for sort, like listFuelLogs.
+func (h *Handler) listJobRuns(ctx context.Context, sort string, p repository.ListJobRunsByStartedAtAscParams) ([]repository.JobRun, error) {
+	switch sort {
+	case "started_at":
+		return h.queries.ListJobRunsByStartedAtAsc(ctx, p)
+	case "-started_at":
+		return h.queries.ListJobRunsByStartedAtDesc(ctx, repository.ListJobRunsByStartedAtDescParams(p))
+	}
+	return nil, fmt.Errorf("no job run query for sort %q", sort)
+}
+
+// ListJobRuns pages through job runs, newest first. Use ?job= and ?status= to filter,
+// and ?from= and ?to= for the day a run started.
+func (h *Handler) ListJobRuns(c *fiber.Ctx) error {
+	opts, msg := parseListOptions(c, jobRunSorts, "-started_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	filters := repository.CountJobRunsParams{
+		JobName:  queryNullString(c, "job"),
+		Status:   queryNullString(c, "status"),
+		DateFrom: opts.dateFrom,
+		DateTo:   opts.dateTo,
+	}
+	runs, err := h.listJobRuns(c.Context(), opts.sortParam(), repository.ListJobRunsByStartedAtAscParams{
+		JobName:  filters.JobName,
+		Status:   filters.Status,
+		DateFrom: filters.DateFrom,
+		DateTo:   filters.DateTo,
+		AfterKey: opts.afterKey(),
+		AfterID:  opts.afterID(),
+		Limit:    opts.fetchLimit(),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch job runs", "details": err.Error()})
+	}
+	total, err := h.queries.CountJobRuns(c.Context(), filters)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch job runs", "details": err.Error()})
+	}
+	runs, meta := pageOf(runs, opts, total, jobRunSorts, func(r repository.JobRun) int32 { return r.ID })
+
+	response := make([]JobRunResponse, len(runs))
+	for i, r := range runs {
+		response[i] = mapJobRunToResponse(r)
+	}
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
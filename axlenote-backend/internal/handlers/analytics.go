@@ -2,8 +2,17 @@ package handlers
 
 import (
 	"database/sql"
+	"fmt"
+	"math"
+	"slices"
 	"strconv"
+	"time"
 
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/insights"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/tco"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -13,7 +22,46 @@ type AnalyticsResponse struct {
 	TotalLiters      float64 `json:"total_liters"`
 	TotalServices    int64   `json:"total_services"`
 	TotalFuelLogs    int64   `json:"total_fuel_logs"`
+	TotalExpenseCost float64 `json:"total_expense_cost"`
+	TotalExpenses    int64   `json:"total_expenses"`
 	TotalCost        float64 `json:"total_cost"`
+	// Insights are open findings of the scheduler, like a drop in fuel economy
+	Insights []InsightResponse `json:"insights"`
+}
+
+type InsightResponse struct {
+	ID            int32   `json:"id"`
+	VehicleID     int32   `json:"vehicle_id"`
+	Kind          string  `json:"kind"`
+	Message       string  `json:"message"`
+	Baseline      float64 `json:"baseline"`
+	Recent        float64 `json:"recent"`
+	ChangePercent float64 `json:"change_percent"`
+	ZScore        float64 `json:"z_score"`
+	FuelLogID     int32   `json:"fuel_log_id,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+	ResolvedAt    string  `json:"resolved_at,omitempty"`
+}
+
+func mapInsightToResponse(i repository.VehicleInsight, unit string) InsightResponse {
+	resp := InsightResponse{
+		ID:            i.ID,
+		VehicleID:     i.VehicleID,
+		Kind:          i.Kind,
+		Baseline:      i.Baseline,
+		Recent:        i.Recent,
+		ChangePercent: i.ChangePercent,
+		ZScore:        i.ZScore,
+		FuelLogID:     i.FuelLogID.Int32,
+		CreatedAt:     i.CreatedAt.Format(time.RFC3339),
+	}
+	if i.Kind == insights.KindEconomyDrop {
+		resp.Message = insights.EconomyDrop{Baseline: i.Baseline, Recent: i.Recent, ChangePercent: i.ChangePercent}.Message(unit)
+	}
+	if i.ResolvedAt.Valid {
+		resp.ResolvedAt = i.ResolvedAt.Time.Format(time.RFC3339)
+	}
+	return resp
 }
 
 func (h *Handler) GetVehicleStats(c *fiber.Ctx) error {
@@ -33,7 +81,334 @@ func (h *Handler) GetVehicleStats(c *fiber.Ctx) error {
 		TotalLiters:      stats.TotalLiters,
 		TotalServices:    stats.TotalServices,
 		TotalFuelLogs:    stats.TotalFuelLogs,
-		TotalCost:        stats.TotalFuelCost + stats.TotalServiceCost,
+		TotalExpenseCost: stats.TotalExpenseCost,
+		TotalExpenses:    stats.TotalExpenses,
+		TotalCost:        stats.TotalFuelCost + stats.TotalServiceCost + stats.TotalExpenseCost,
+		Insights:         []InsightResponse{},
+	}
+
+	openInsights, err := h.queries.ListOpenInsights(c.Context(), int32(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch insights", "details": err.Error()})
+	}
+	if len(openInsights) > 0 {
+		unit := fleet.DefaultUnit()
+		if vehicle, err := h.queries.GetVehicle(c.Context(), int32(id)); err == nil {
+			unit = fleet.UnitOf(vehicle)
+		}
+		for _, i := range openInsights {
+			response.Insights = append(response.Insights, mapInsightToResponse(i, unit))
+		}
+	}
+
+	return c.JSON(fiber.Map{"data": response})
+}
+
+// DismissInsight closes an insight the owner has looked into. The scheduler raises it
+// again if the problem still shows after the next fill.
+func (h *Handler) DismissInsight(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid insight ID"})
+	}
+
+	insight, err := h.queries.DismissInsight(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Insight not found or already resolved"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to dismiss insight", "details": err.Error()})
+	}
+
+	unit := fleet.DefaultUnit()
+	if vehicle, err := h.queries.GetVehicle(c.Context(), insight.VehicleID); err == nil {
+		unit = fleet.UnitOf(vehicle)
+	}
+	return c.JSON(fiber.Map{"data": mapInsightToResponse(insight, unit)})
+}
+
+// maxTimeseriesPeriods bounds the size of a timeseries, e.g. 19 years by week.
+const maxTimeseriesPeriods = 1000
+
+type TimeseriesPoint struct {
+	PeriodStart string   `json:"period_start"` // YYYY-MM-DD
+	PeriodEnd   string   `json:"period_end"`   // last day of the period
+	FuelCost    float64  `json:"fuel_cost"`
+	ServiceCost float64  `json:"service_cost"`
+	ExpenseCost float64  `json:"expense_cost"`
+	TotalCost   float64  `json:"total_cost"`
+	Liters      float64  `json:"liters"`
+	Distance    int32    `json:"distance"`
+	Economy     *float64 `json:"economy"` // distance per litre over full-tank fills, null without any
+}
+
+type TimeseriesResponse struct {
+	Interval string            `json:"interval"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Points   []TimeseriesPoint `json:"points"`
+	Totals   TimeseriesPoint   `json:"totals"`
+}
+
+// periodStart truncates a date to the start of its week (Monday), month or year, like
+// date_trunc in Postgres.
+func periodStart(t time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case "year":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextPeriod(t time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return t.AddDate(0, 0, 7)
+	case "year":
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 1, 0)
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// GetVehicleTimeseries buckets a vehicle's fuel, service and expense spend, litres,
+// distance driven and fuel economy by ?interval=week|month|year (default month). ?from= is rounded down
+// to the start of its period; the range defaults to the last year, or five years by year.
+// Periods without activity are included with zeros.
+func (h *Handler) GetVehicleTimeseries(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	interval := c.Query("interval", "month")
+	if interval != "week" && interval != "month" && interval != "year" {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid interval, use week, month or year"})
+	}
+
+	to := forecast.Today()
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid to, use YYYY-MM-DD"})
+		}
+	}
+	from := to.AddDate(-1, 0, 1)
+	if interval == "year" {
+		from = to.AddDate(-5, 0, 1)
+	}
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid from, use YYYY-MM-DD"})
+		}
+	}
+	from = periodStart(from, interval)
+	if from.After(to) {
+		return c.Status(400).JSON(fiber.Map{"error": "From must not be after to"})
+	}
+
+	var periods []time.Time
+	for p := from; !p.After(to); p = nextPeriod(p, interval) {
+		if len(periods) == maxTimeseriesPeriods {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("Range too long, at most %d periods", maxTimeseriesPeriods)})
+		}
+		periods = append(periods, p)
+	}
+
+	if _, err := h.queries.GetVehicle(c.Context(), int32(id)); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	fuel, err := h.queries.GetFuelTimeseries(c.Context(), repository.GetFuelTimeseriesParams{
+		VehicleID: int32(id),
+		Period:    interval,
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch fuel logs", "details": err.Error()})
+	}
+	service, err := h.queries.GetServiceTimeseries(c.Context(), repository.GetServiceTimeseriesParams{
+		VehicleID: int32(id),
+		Period:    interval,
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch service records", "details": err.Error()})
+	}
+	expenses, err := h.queries.GetExpenseTimeseries(c.Context(), repository.GetExpenseTimeseriesParams{
+		VehicleID: int32(id),
+		Period:    interval,
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch expenses", "details": err.Error()})
+	}
+	odometer, err := h.queries.GetOdometerTimeseries(c.Context(), repository.GetOdometerTimeseriesParams{
+		VehicleID: int32(id),
+		Period:    interval,
+		DateTo:    to,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch odometer readings", "details": err.Error()})
+	}
+
+	type bucket struct {
+		fuelCost, serviceCost, expenseCost float64
+		liters                             float64
+		economyDistance, economyLiters     float64
+		distance                           int32
+	}
+	buckets := make(map[string]*bucket, len(periods)) // by period start
+	for _, p := range periods {
+		buckets[p.Format("2006-01-02")] = &bucket{}
+	}
+	for _, r := range fuel {
+		if b := buckets[r.PeriodStart.Format("2006-01-02")]; b != nil {
+			b.fuelCost, b.liters = r.FuelCost, r.Liters
+			b.economyDistance, b.economyLiters = r.EconomyDistance, r.EconomyLiters
+		}
+	}
+	for _, r := range service {
+		if b := buckets[r.PeriodStart.Format("2006-01-02")]; b != nil {
+			b.serviceCost = r.ServiceCost
+		}
+	}
+	for _, r := range expenses {
+		if b := buckets[r.PeriodStart.Format("2006-01-02")]; b != nil {
+			b.expenseCost = r.ExpenseCost
+		}
+	}
+	// Distance in a period is how far the highest reading moved past the highest one before
+	var last int32
+	for _, r := range odometer {
+		if b := buckets[r.PeriodStart.Format("2006-01-02")]; b != nil && last > 0 && r.Odometer > last {
+			b.distance = r.Odometer - last
+		}
+		last = max(last, r.Odometer)
+	}
+
+	economy := func(distance, liters float64) *float64 {
+		if liters <= 0 {
+			return nil
+		}
+		e := round2(distance / liters)
+		return &e
+	}
+	response := TimeseriesResponse{
+		Interval: interval,
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Points:   make([]TimeseriesPoint, len(periods)),
+	}
+	var total bucket
+	for i, p := range periods {
+		b := buckets[p.Format("2006-01-02")]
+		response.Points[i] = TimeseriesPoint{
+			PeriodStart: p.Format("2006-01-02"),
+			PeriodEnd:   nextPeriod(p, interval).AddDate(0, 0, -1).Format("2006-01-02"),
+			FuelCost:    round2(b.fuelCost),
+			ServiceCost: round2(b.serviceCost),
+			ExpenseCost: round2(b.expenseCost),
+			TotalCost:   round2(b.fuelCost + b.serviceCost + b.expenseCost),
+			Liters:      round2(b.liters),
+			Distance:    b.distance,
+			Economy:     economy(b.economyDistance, b.economyLiters),
+		}
+		total.fuelCost += b.fuelCost
+		total.serviceCost += b.serviceCost
+		total.expenseCost += b.expenseCost
+		total.liters += b.liters
+		total.economyDistance += b.economyDistance
+		total.economyLiters += b.economyLiters
+		total.distance += b.distance
+	}
+	response.Totals = TimeseriesPoint{
+		PeriodStart: response.From,
+		PeriodEnd:   response.To,
+		FuelCost:    round2(total.fuelCost),
+		ServiceCost: round2(total.serviceCost),
+		ExpenseCost: round2(total.expenseCost),
+		TotalCost:   round2(total.fuelCost + total.serviceCost + total.expenseCost),
+		Liters:      round2(total.liters),
+		Distance:    total.distance,
+		Economy:     economy(total.economyDistance, total.economyLiters),
+	}
+
+	return c.JSON(fiber.Map{"data": response})
+}
+
+type ComparisonResponse struct {
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Unit     string             `json:"unit"` // of every distance in the comparison
+	Currency string             `json:"currency"`
+	Vehicles []fleet.Comparison `json:"vehicles"`
+}
+
+// CompareVehicles sets vehicles side by side over ?from= to ?to= (default the last year):
+// economy, cost per distance, service frequency and intervals, and spend by category.
+// Pick vehicles with ?vehicle_id= (comma separated, default all) and the distance unit
+// with ?unit=km|miles.
+func (h *Handler) CompareVehicles(c *fiber.Ctx) error {
+	from, to, msg := tcoRange(c)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	unit := c.Query("unit", fleet.DefaultUnit())
+	if !slices.Contains(fleet.Units, unit) {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid unit, use km or miles"})
+	}
+	var ids []int32
+	for _, v := range splitList(c.Query("vehicle_id")) {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+		}
+		if !slices.Contains(ids, int32(id)) {
+			ids = append(ids, int32(id))
+		}
+	}
+
+	vehicles, err := h.queries.ListVehicles(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+	}
+	if len(ids) > 0 {
+		vehicles = slices.DeleteFunc(vehicles, func(v repository.Vehicle) bool { return !slices.Contains(ids, v.ID) })
+		if len(vehicles) < len(ids) {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		// In the order asked for
+		slices.SortFunc(vehicles, func(a, b repository.Vehicle) int {
+			return slices.Index(ids, a.ID) - slices.Index(ids, b.ID)
+		})
+	}
+
+	response := ComparisonResponse{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Unit:     unit,
+		Currency: tco.Currency(),
+		Vehicles: make([]fleet.Comparison, 0, len(vehicles)),
+	}
+	for _, v := range vehicles {
+		cmp, err := fleet.Compare(c.Context(), h.queries, v, from, to, unit)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to compare vehicles", "details": err.Error()})
+		}
+		response.Vehicles = append(response.Vehicles, cmp)
 	}
 
 	return c.JSON(fiber.Map{"data": response})
@@ -1,15 +1,58 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
+	"log"
+
 	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/scheduler"
+	"github.com/axlenote/axlenote-backend/internal/storage"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
 )
 
 type Handler struct {
-	queries *repository.Queries
+	db        *sql.DB
+	queries   *repository.Queries
+	scheduler *scheduler.Scheduler
+	storage   *storage.Store
 }
 
-func New(queries *repository.Queries) *Handler {
+func New(db *sql.DB, queries *repository.Queries, scheduler *scheduler.Scheduler, storage *storage.Store) *Handler {
 	return &Handler{
-		queries: queries,
+		db:        db,
+		queries:   queries,
+		scheduler: scheduler,
+		storage:   storage,
+	}
+}
+
+// inTx runs fn with queries bound to a transaction, which is committed if fn returns nil
+// and rolled back otherwise.
+func (h *Handler) inTx(ctx context.Context, fn func(q *repository.Queries) error) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(h.queries.WithTx(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// emit announces a data change: it queues webhooks and refreshes the Home Assistant
+// sensors. Failures are logged rather than failing the request, since the change itself
+// has already been saved.
+func (h *Handler) emit(ctx context.Context, event string, data any) {
+	h.scheduler.PublishMQTT()
+
+	queued, err := webhook.Enqueue(ctx, h.queries, event, data)
+	if err != nil {
+		log.Printf("Failed to queue %s webhooks: %v", event, err)
+		return
+	}
+	if queued > 0 {
+		h.scheduler.DeliverWebhooks()
 	}
 }
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/budget"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateBudgetRequest struct {
+	VehicleID  int32   `json:"vehicle_id"` // 0 for a budget covering every vehicle
+	Category   string  `json:"category"`   // total, fuel, maintenance, insurance, tax, financing, expenses
+	Period     string  `json:"period"`     // monthly (default) or yearly
+	Amount     float64 `json:"amount"`
+	Thresholds []int32 `json:"thresholds"` // percentages to alert at, defaults to BUDGET_THRESHOLDS
+}
+
+type BudgetResponse struct {
+	ID         int32           `json:"id"`
+	VehicleID  int32           `json:"vehicle_id,omitempty"`
+	Category   string          `json:"category"`
+	Period     string          `json:"period"`
+	Amount     float64         `json:"amount"`
+	Thresholds []int32         `json:"thresholds"`
+	CreatedAt  string          `json:"created_at"`
+	Progress   budget.Progress `json:"progress"` // in the current period
+}
+
+// budgetResponse maps a budget along with its progress in the current period.
+func (h *Handler) budgetResponse(ctx context.Context, b repository.Budget, today time.Time) (BudgetResponse, error) {
+	amount, _ := strconv.ParseFloat(b.Amount, 64)
+	progress, err := budget.ForBudget(ctx, h.queries, b, today)
+	if err != nil {
+		return BudgetResponse{}, err
+	}
+	return BudgetResponse{
+		ID:         b.ID,
+		VehicleID:  b.VehicleID.Int32,
+		Category:   b.Category,
+		Period:     b.Period,
+		Amount:     amount,
+		Thresholds: b.Thresholds,
+		CreatedAt:  b.CreatedAt.Time.Format(time.RFC3339),
+		Progress:   progress,
+	}, nil
+}
+
+func (req *CreateBudgetRequest) validate() string {
+	if req.Period == "" {
+		req.Period = budget.PeriodMonthly
+	}
+	if len(req.Thresholds) == 0 {
+		req.Thresholds = budget.DefaultThresholds()
+	}
+	if !slices.Contains(budget.Categories, req.Category) {
+		return "Invalid category, use one of " + strings.Join(budget.Categories, ", ")
+	}
+	if !slices.Contains(budget.Periods, req.Period) {
+		return "Invalid period, use monthly or yearly"
+	}
+	if req.Amount <= 0 {
+		return "Amount must be positive"
+	}
+	for _, t := range req.Thresholds {
+		if t <= 0 || t > 1000 {
+			return fmt.Sprintf("Invalid threshold %d, use a percentage between 1 and 1000", t)
+		}
+	}
+	slices.Sort(req.Thresholds)
+	req.Thresholds = slices.Compact(req.Thresholds)
+	return ""
+}
+
+// ListBudgets returns budgets with their progress this period. ?vehicle_id= limits the
+// list to a vehicle's budgets and the garage-wide ones.
+func (h *Handler) ListBudgets(c *fiber.Ctx) error {
+	var vehicleID int32
+	if v := c.Query("vehicle_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+		}
+		vehicleID = int32(id)
+	}
+
+	budgets, err := h.queries.ListBudgets(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch budgets", "details": err.Error()})
+	}
+
+	today := forecast.Today()
+	response := make([]BudgetResponse, 0, len(budgets))
+	for _, b := range budgets {
+		if vehicleID != 0 && b.VehicleID.Valid && b.VehicleID.Int32 != vehicleID {
+			continue
+		}
+		resp, err := h.budgetResponse(c.Context(), b, today)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch budget progress", "details": err.Error()})
+		}
+		response = append(response, resp)
+	}
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *Handler) GetBudget(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid budget ID"})
+	}
+
+	b, err := h.queries.GetBudget(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Budget not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	resp, err := h.budgetResponse(c.Context(), b, forecast.Today())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch budget progress", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) CreateBudget(c *fiber.Ctx) error {
+	var req CreateBudgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if msg := req.validate(); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	b, err := h.queries.CreateBudget(c.Context(), repository.CreateBudgetParams{
+		VehicleID:  sql.NullInt32{Int32: req.VehicleID, Valid: req.VehicleID > 0},
+		Category:   req.Category,
+		Period:     req.Period,
+		Amount:     strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		Thresholds: req.Thresholds,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create budget", "details": err.Error()})
+	}
+
+	resp, err := h.budgetResponse(c.Context(), b, forecast.Today())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch budget progress", "details": err.Error()})
+	}
+	return c.Status(201).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) UpdateBudget(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid budget ID"})
+	}
+
+	var req CreateBudgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if msg := req.validate(); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	b, err := h.queries.UpdateBudget(c.Context(), repository.UpdateBudgetParams{
+		ID:         int32(id),
+		VehicleID:  sql.NullInt32{Int32: req.VehicleID, Valid: req.VehicleID > 0},
+		Category:   req.Category,
+		Period:     req.Period,
+		Amount:     strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		Thresholds: req.Thresholds,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Budget not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update budget", "details": err.Error()})
+	}
+
+	resp, err := h.budgetResponse(c.Context(), b, forecast.Today())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch budget progress", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) DeleteBudget(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid budget ID"})
+	}
+
+	if err := h.queries.DeleteBudget(c.Context(), int32(id)); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete budget"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
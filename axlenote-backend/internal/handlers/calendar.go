@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/ical"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FeedTokenResponse struct {
+	Url         string           `json:"url"`
+	VehicleUrls map[int32]string `json:"vehicle_urls"`
+}
+
+// CreateFeedToken enables a user's calendar feed, replacing any previous token so old
+// subscription URLs stop working.
+func (h *Handler) CreateFeedToken(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to generate token", "details": err.Error()})
+	}
+	token := hex.EncodeToString(buf)
+
+	user, err := h.queries.SetUserFeedToken(c.Context(), repository.SetUserFeedTokenParams{
+		ID:        int32(id),
+		FeedToken: sql.NullString{String: token, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save token", "details": err.Error()})
+	}
+
+	vehicles, err := h.feedVehicles(c.Context(), user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+	}
+
+	base := c.BaseURL() + "/api/v1/calendar/" + token
+	resp := FeedTokenResponse{Url: base + ".ics", VehicleUrls: make(map[int32]string)}
+	for _, v := range vehicles {
+		resp.VehicleUrls[v.ID] = fmt.Sprintf("%s/vehicles/%d.ics", base, v.ID)
+	}
+	return c.Status(201).JSON(fiber.Map{"data": resp})
+}
+
+// DeleteFeedToken disables a user's calendar feed.
+func (h *Handler) DeleteFeedToken(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	_, err = h.queries.SetUserFeedToken(c.Context(), repository.SetUserFeedTokenParams{ID: int32(id)})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to revoke token", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Calendar feed disabled"})
+}
+
+// GetCalendarFeed serves the iCalendar feed for all of a user's vehicles.
+func (h *Handler) GetCalendarFeed(c *fiber.Ctx) error {
+	user, err := h.queries.GetUserByFeedToken(c.Context(), sql.NullString{String: c.Params("token"), Valid: true})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Calendar not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch calendar", "details": err.Error()})
+	}
+
+	vehicles, err := h.feedVehicles(c.Context(), user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+	}
+	return h.sendCalendar(c, "AxleNote", vehicles)
+}
+
+// GetVehicleCalendarFeed serves the iCalendar feed for a single vehicle.
+func (h *Handler) GetVehicleCalendarFeed(c *fiber.Ctx) error {
+	vehicleID, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	user, err := h.queries.GetUserByFeedToken(c.Context(), sql.NullString{String: c.Params("token"), Valid: true})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Calendar not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch calendar", "details": err.Error()})
+	}
+
+	vehicles, err := h.feedVehicles(c.Context(), user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+	}
+	for _, v := range vehicles {
+		if v.ID == int32(vehicleID) {
+			return h.sendCalendar(c, "AxleNote: "+v.Name, []repository.Vehicle{v})
+		}
+	}
+	// Vehicles the user doesn't follow look the same as ones that don't exist
+	return c.Status(404).JSON(fiber.Map{"error": "Calendar not found"})
+}
+
+// feedVehicles returns the vehicles covered by a user's feed: those they have enabled
+// subscriptions for, or every vehicle if a subscription covers all of them or they have none.
+// Sold and scrapped vehicles are never included.
+func (h *Handler) feedVehicles(ctx context.Context, userID int32) ([]repository.Vehicle, error) {
+	all, err := h.queries.ListVehicles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	all = slices.DeleteFunc(all, fleet.IsArchived)
+	subs, err := h.queries.ListSubscriptionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[int32]bool)
+	for _, s := range subs {
+		if !s.Enabled {
+			continue
+		}
+		if !s.VehicleID.Valid {
+			return all, nil
+		}
+		selected[s.VehicleID.Int32] = true
+	}
+	if len(selected) == 0 {
+		return all, nil
+	}
+
+	var out []repository.Vehicle
+	for _, v := range all {
+		if selected[v.ID] {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func (h *Handler) sendCalendar(c *fiber.Ctx, name string, vehicles []repository.Vehicle) error {
+	cal := ical.Calendar{Name: name}
+	for _, v := range vehicles {
+		events, err := h.vehicleEvents(c.Context(), v)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to build calendar", "details": err.Error()})
+		}
+		cal.Events = append(cal.Events, events...)
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Write(&buf); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to render calendar", "details": err.Error()})
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=900")
+	return c.Send(buf.Bytes())
+}
+
+// vehicleEvents builds calendar events for a vehicle's open reminders and current documents.
+// UIDs are derived from the row IDs so a changed due date moves the existing event.
+func (h *Handler) vehicleEvents(ctx context.Context, v repository.Vehicle) ([]ical.Event, error) {
+	vehicleID := sql.NullInt32{Int32: v.ID, Valid: true}
+	reminders, err := h.queries.ListRemindersByVehicle(ctx, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("list reminders: %w", err)
+	}
+	documents, err := h.queries.ListDocumentsByVehicle(ctx, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("list documents: %w", err)
+	}
+
+	rate, hasRate, err := forecast.ForVehicle(ctx, h.queries, v.ID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("forecast: %w", err)
+	}
+	today := forecast.Today()
+
+	var events []ical.Event
+	for _, r := range reminders {
+		if r.IsCompleted.Bool {
+			continue
+		}
+		date, projected, ok := forecast.ReminderDue(r, rate, hasRate, today)
+		if !ok {
+			continue
+		}
+		var description string
+		if projected {
+			// Odometers, and so the driving rate, are in the vehicle's own unit
+			unit := fleet.UnitOf(v)
+			description = fmt.Sprintf("Projected to reach %d %s at about %.0f %s/day.", r.DueOdometer.Int32, unit, rate.KmPerDay, unit)
+		}
+		if r.Notes.Valid && r.Notes.String != "" {
+			if description != "" {
+				description += "\n\n"
+			}
+			description += r.Notes.String
+		}
+
+		event := ical.Event{
+			UID:         fmt.Sprintf("reminder-%d@axlenote", r.ID),
+			Date:        date,
+			Summary:     fmt.Sprintf("%s: %s", v.Name, r.Title),
+			Description: description,
+		}
+		if r.Type.Valid && r.Type.String != "" {
+			event.Categories = []string{r.Type.String}
+		}
+		events = append(events, event)
+	}
+
+	for _, d := range documents {
+		if !d.ExpiryDate.Valid {
+			continue
+		}
+		event := ical.Event{
+			UID:         fmt.Sprintf("document-%d@axlenote", d.ID),
+			Date:        d.ExpiryDate.Time,
+			Summary:     fmt.Sprintf("%s: %s expires", v.Name, d.Name),
+			Description: d.Notes.String,
+		}
+		if d.Type.Valid && d.Type.String != "" {
+			event.Categories = []string{d.Type.String}
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
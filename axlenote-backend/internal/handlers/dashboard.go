@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"slices"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DashboardReminder struct {
+	ID        int32  `json:"id"`
+	Title     string `json:"title"`
+	DueDate   string `json:"due_date"`  // YYYY-MM-DD
+	Projected bool   `json:"projected"` // due date estimated from the due odometer
+	Overdue   bool   `json:"overdue"`
+}
+
+type DashboardDocument struct {
+	ID         int32  `json:"id"`
+	Name       string `json:"name"`
+	Category   string `json:"category"`
+	ExpiryDate string `json:"expiry_date"`
+	DaysLeft   int    `json:"days_left"` // negative once expired
+	Expired    bool   `json:"expired"`
+}
+
+type DashboardVehicle struct {
+	VehicleID         int32               `json:"vehicle_id"`
+	Name              string              `json:"name"`
+	Make              string              `json:"make"`
+	Model             string              `json:"model"`
+	ImageUrl          string              `json:"image_url"`
+	Odometer          int32               `json:"odometer"`
+	FuelEconomy       *float64            `json:"fuel_economy"` // distance per litre over the latest full-tank fill
+	FuelCost          float64             `json:"fuel_cost"`    // this calendar month
+	ServiceCost       float64             `json:"service_cost"`
+	ExpenseCost       float64             `json:"expense_cost"`
+	MonthToDateSpend  float64             `json:"month_to_date_spend"`
+	NextReminder      *DashboardReminder  `json:"next_reminder"`
+	OverdueReminders  int                 `json:"overdue_reminders"`
+	ExpiringDocuments []DashboardDocument `json:"expiring_documents"`
+}
+
+type DashboardResponse struct {
+	AsOf              string             `json:"as_of"` // YYYY-MM-DD
+	MonthToDateSpend  float64            `json:"month_to_date_spend"`
+	OverdueReminders  int                `json:"overdue_reminders"`
+	ExpiringDocuments int                `json:"expiring_documents"`
+	Vehicles          []DashboardVehicle `json:"vehicles"`
+}
+
+// GetDashboard summarises every vehicle for the dashboard in one response: current
+// odometer, latest fuel economy, spend this month, the next reminder due, how many are
+// overdue, and documents that have expired or expire within ?days= (default 30). Sold and
+// scrapped vehicles are left out.
+func (h *Handler) GetDashboard(c *fiber.Ctx) error {
+	days := c.QueryInt("days", 30)
+	if days < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Days must not be negative"})
+	}
+
+	today := forecast.Today()
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	vehicles, err := h.queries.ListVehicles(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+	}
+	vehicles = slices.DeleteFunc(vehicles, fleet.IsArchived)
+	stats, err := h.queries.ListDashboardStats(c.Context(), monthStart)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch stats", "details": err.Error()})
+	}
+	reminders, err := h.queries.ListOpenReminders(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch reminders", "details": err.Error()})
+	}
+	docs, err := h.queries.ListExpiringDocuments(c.Context(), today.AddDate(0, 0, days))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch documents", "details": err.Error()})
+	}
+	rates, err := forecast.ForVehicles(c.Context(), h.queries, time.Now())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch odometer readings", "details": err.Error()})
+	}
+
+	response := DashboardResponse{AsOf: today.Format("2006-01-02"), Vehicles: make([]DashboardVehicle, len(vehicles))}
+	index := make(map[int32]int, len(vehicles))
+	for i, v := range vehicles {
+		index[v.ID] = i
+		response.Vehicles[i] = DashboardVehicle{
+			VehicleID:         v.ID,
+			Name:              v.Name,
+			Make:              v.Make.String,
+			Model:             v.Model.String,
+			ImageUrl:          v.ImageUrl.String,
+			ExpiringDocuments: []DashboardDocument{},
+		}
+	}
+
+	for _, s := range stats {
+		i, ok := index[s.VehicleID]
+		if !ok {
+			continue
+		}
+		dv := &response.Vehicles[i]
+		dv.Odometer = s.Odometer
+		if s.EconomyLiters > 0 {
+			economy := round2(s.EconomyDistance / s.EconomyLiters)
+			dv.FuelEconomy = &economy
+		}
+		dv.FuelCost = round2(s.FuelCost)
+		dv.ServiceCost = round2(s.ServiceCost)
+		dv.ExpenseCost = round2(s.ExpenseCost)
+		dv.MonthToDateSpend = round2(s.FuelCost + s.ServiceCost + s.ExpenseCost)
+		response.MonthToDateSpend += s.FuelCost + s.ServiceCost + s.ExpenseCost
+	}
+	response.MonthToDateSpend = round2(response.MonthToDateSpend)
+
+	nextDue := make(map[int32]time.Time)
+	for _, r := range reminders {
+		i, ok := index[r.VehicleID.Int32]
+		if !ok {
+			continue
+		}
+		dv := &response.Vehicles[i]
+
+		overdue := (r.DueDate.Valid && r.DueDate.Time.Before(today)) ||
+			(r.DueOdometer.Valid && r.DueOdometer.Int32 > 0 && dv.Odometer >= r.DueOdometer.Int32)
+		if overdue {
+			dv.OverdueReminders++
+			response.OverdueReminders++
+		}
+
+		rate, hasRate := rates[dv.VehicleID]
+		rate = rate.From(dv.Odometer)
+		due, projected, ok := forecast.ReminderDue(r, rate, hasRate, today)
+		if !ok && !overdue {
+			continue
+		}
+		if overdue && (!ok || due.After(today)) {
+			// Due by odometer already, whatever the date says
+			due, projected = today, false
+		}
+		if next, seen := nextDue[dv.VehicleID]; seen && !due.Before(next) {
+			continue
+		}
+		nextDue[dv.VehicleID] = due
+		dv.NextReminder = &DashboardReminder{
+			ID:        r.ID,
+			Title:     r.Title,
+			DueDate:   due.Format("2006-01-02"),
+			Projected: projected,
+			Overdue:   overdue,
+		}
+	}
+
+	for _, d := range docs {
+		i, ok := index[d.VehicleID.Int32]
+		if !ok {
+			continue
+		}
+		left := int(d.ExpiryDate.Time.Sub(today).Hours() / 24)
+		response.Vehicles[i].ExpiringDocuments = append(response.Vehicles[i].ExpiringDocuments, DashboardDocument{
+			ID:         d.ID,
+			Name:       d.Name,
+			Category:   d.Category.String,
+			ExpiryDate: d.ExpiryDate.Time.Format("2006-01-02"),
+			DaysLeft:   left,
+			Expired:    left < 0,
+		})
+		response.ExpiringDocuments++
+	}
+
+	return c.JSON(fiber.Map{"data": response})
+}
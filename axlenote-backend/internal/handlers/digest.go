@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/axlenote/axlenote-backend/internal/digest"
+	"github.com/axlenote/axlenote-backend/internal/scheduler"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PreviewDigest renders the digest a user would receive, without sending it.
+// Use ?period=daily|weekly and ?format=json|text|html.
+func (h *Handler) PreviewDigest(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	period := c.Query("period", digest.PeriodDaily)
+	if period != digest.PeriodDaily && period != digest.PeriodWeekly {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid period, use daily or weekly"})
+	}
+
+	user, err := h.queries.GetUser(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	d, err := h.scheduler.PreviewDigest(c.Context(), user.ID, period)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to build digest", "details": err.Error()})
+	}
+
+	msg, err := scheduler.RenderDigest(d)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to render digest", "details": err.Error()})
+	}
+
+	switch c.Query("format", "json") {
+	case "html":
+		c.Type("html")
+		return c.SendString(msg.HTML)
+	case "text":
+		c.Type("txt")
+		return c.SendString(msg.Body)
+	default:
+		return c.JSON(fiber.Map{"data": fiber.Map{
+			"title": msg.Title,
+			"text":  msg.Body,
+			"html":  msg.HTML,
+		}})
+	}
+}
@@ -1,47 +1,187 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"log"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/axlenote/axlenote-backend/internal/forecast"
 	"github.com/axlenote/axlenote-backend/internal/repository"
 	"github.com/gofiber/fiber/v2"
 )
 
 type CreateDocumentRequest struct {
-	VehicleID  int32  `json:"vehicle_id"`
-	Name       string `json:"name"`
-	Type       string `json:"type"`
-	FileUrl    string `json:"file_url"`
-	ExpiryDate string `json:"expiry_date"`
-	Notes      string `json:"notes"`
+	VehicleID          int32    `json:"vehicle_id"`
+	Name               string   `json:"name"`
+	Type               string   `json:"type"`
+	Category           string   `json:"category"` // slug from /document-categories
+	FileUrl            string   `json:"file_url"` // optional external link; files can also be uploaded
+	IssueDate          string   `json:"issue_date"`
+	ExpiryDate         string   `json:"expiry_date"`
+	Issuer             string   `json:"issuer"`
+	PolicyNumber       string   `json:"policy_number"`
+	RegistrationNumber string   `json:"registration_number"`
+	Tags               []string `json:"tags"`
+	Notes              string   `json:"notes"`
 }
 
 type DocumentResponse struct {
-	ID         int32  `json:"id"`
-	VehicleID  int32  `json:"vehicle_id"`
-	Name       string `json:"name"`
-	Type       string `json:"type"`
-	FileUrl    string `json:"file_url"`
-	ExpiryDate string `json:"expiry_date"`
-	Notes      string `json:"notes"`
+	ID                 int32                  `json:"id"`
+	VehicleID          int32                  `json:"vehicle_id"`
+	Name               string                 `json:"name"`
+	Type               string                 `json:"type"`
+	Category           string                 `json:"category"`
+	FileUrl            string                 `json:"file_url"`
+	IssueDate          string                 `json:"issue_date"`
+	ExpiryDate         string                 `json:"expiry_date"`
+	Issuer             string                 `json:"issuer"`
+	PolicyNumber       string                 `json:"policy_number"`
+	RegistrationNumber string                 `json:"registration_number"`
+	Tags               []string               `json:"tags"`
+	Notes              string                 `json:"notes"`
+	Files              []DocumentFileResponse `json:"files"`
+	ArchivedAt         string                 `json:"archived_at,omitempty"`
+	PreviousVersionID  int32                  `json:"previous_version_id,omitempty"`
+}
+
+type ExpiringDocumentResponse struct {
+	DocumentResponse
+	VehicleName string `json:"vehicle_name"`
+	DaysLeft    int    `json:"days_left"` // negative once expired
+	Expired     bool   `json:"expired"`
+}
+
+type DocumentCategoryRequest struct {
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	TracksExpiry bool   `json:"tracks_expiry"`
+}
+
+type DocumentCategoryResponse struct {
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	TracksExpiry bool   `json:"tracks_expiry"`
+}
+
+func formatDate(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format("2006-01-02")
 }
 
 func mapDocumentToResponse(d repository.Document) DocumentResponse {
-	var expiry string
-	if d.ExpiryDate.Valid {
-		expiry = d.ExpiryDate.Time.Format("2006-01-02")
+	var archived string
+	if d.ArchivedAt.Valid {
+		archived = d.ArchivedAt.Time.Format(time.RFC3339)
 	}
 	return DocumentResponse{
-		ID:         d.ID,
-		VehicleID:  d.VehicleID.Int32,
-		Name:       d.Name,
-		Type:       d.Type.String,
-		FileUrl:    d.FileUrl,
-		ExpiryDate: expiry,
-		Notes:      d.Notes.String,
+		ID:                 d.ID,
+		VehicleID:          d.VehicleID.Int32,
+		Name:               d.Name,
+		Type:               d.Type.String,
+		Category:           d.Category.String,
+		FileUrl:            d.FileUrl,
+		IssueDate:          formatDate(d.IssueDate),
+		ExpiryDate:         formatDate(d.ExpiryDate),
+		Issuer:             d.Issuer.String,
+		PolicyNumber:       d.PolicyNumber.String,
+		RegistrationNumber: d.RegistrationNumber.String,
+		Tags:               nonNilStrings(d.Tags),
+		Notes:              d.Notes.String,
+		Files:              []DocumentFileResponse{},
+		ArchivedAt:         archived,
+		PreviousVersionID:  d.PreviousVersionID.Int32,
+	}
+}
+
+// documentFields holds a parsed and validated document request.
+type documentFields struct {
+	issueDate  sql.NullTime
+	expiryDate sql.NullTime
+	category   sql.NullString
+	tags       []string
+}
+
+// parse validates dates and the category and normalizes tags.
+func (req *CreateDocumentRequest) parse(ctx context.Context, queries *repository.Queries) (documentFields, string) {
+	var f documentFields
+	for _, d := range []struct {
+		value string
+		out   *sql.NullTime
+		field string
+	}{
+		{req.IssueDate, &f.issueDate, "issue_date"},
+		{req.ExpiryDate, &f.expiryDate, "expiry_date"},
+	} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return f, fmt.Sprintf("Invalid %s, use YYYY-MM-DD", d.field)
+		}
+		*d.out = sql.NullTime{Time: parsed, Valid: true}
+	}
+	if f.issueDate.Valid && f.expiryDate.Valid && f.expiryDate.Time.Before(f.issueDate.Time) {
+		return f, "expiry_date cannot be before issue_date"
+	}
+
+	if req.Category != "" {
+		categories, err := queries.ListDocumentCategories(ctx)
+		if err != nil {
+			return f, "Failed to fetch document categories"
+		}
+		found := false
+		for _, c := range categories {
+			if c.Slug == req.Category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return f, "Unknown category"
+		}
+		f.category = sql.NullString{String: req.Category, Valid: true}
+	}
+
+	f.tags = []string{}
+	seen := make(map[string]bool)
+	for _, t := range req.Tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			f.tags = append(f.tags, t)
+		}
+	}
+	return f, ""
+}
+
+// withFiles attaches uploaded files to the documents in place.
+func (h *Handler) withFiles(ctx context.Context, docs []DocumentResponse) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	ids := make([]int32, len(docs))
+	index := make(map[int32]int, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+		index[d.ID] = i
 	}
+
+	files, err := h.queries.ListDocumentFilesByDocuments(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		i := index[f.DocumentID]
+		docs[i].Files = append(docs[i].Files, mapDocumentFileToResponse(f))
+	}
+	return nil
 }
 
 func (h *Handler) CreateDocument(c *fiber.Ctx) error {
@@ -49,22 +189,28 @@ func (h *Handler) CreateDocument(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
+	}
 
-	var expiryDate sql.NullTime
-	if req.ExpiryDate != "" {
-		parsed, err := time.Parse("2006-01-02", req.ExpiryDate)
-		if err == nil {
-			expiryDate = sql.NullTime{Time: parsed, Valid: true}
-		}
+	fields, msg := req.parse(c.Context(), h.queries)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
 	}
 
 	doc, err := h.queries.CreateDocument(c.Context(), repository.CreateDocumentParams{
-		VehicleID:  sql.NullInt32{Int32: req.VehicleID, Valid: true},
-		Name:       req.Name,
-		Type:       sql.NullString{String: req.Type, Valid: req.Type != ""},
-		FileUrl:    req.FileUrl,
-		ExpiryDate: expiryDate,
-		Notes:      sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		VehicleID:          sql.NullInt32{Int32: req.VehicleID, Valid: true},
+		Name:               req.Name,
+		Type:               sql.NullString{String: req.Type, Valid: req.Type != ""},
+		FileUrl:            req.FileUrl,
+		ExpiryDate:         fields.expiryDate,
+		Notes:              sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		Category:           fields.category,
+		IssueDate:          fields.issueDate,
+		Issuer:             sql.NullString{String: req.Issuer, Valid: req.Issuer != ""},
+		PolicyNumber:       sql.NullString{String: req.PolicyNumber, Valid: req.PolicyNumber != ""},
+		RegistrationNumber: sql.NullString{String: req.RegistrationNumber, Valid: req.RegistrationNumber != ""},
+		Tags:               fields.tags,
 	})
 
 	if err != nil {
@@ -74,23 +220,181 @@ func (h *Handler) CreateDocument(c *fiber.Ctx) error {
 	return c.Status(201).JSON(fiber.Map{"data": mapDocumentToResponse(doc)})
 }
 
-func (h *Handler) ListDocuments(c *fiber.Ctx) error {
-	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+func (h *Handler) UpdateDocument(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid document ID"})
+	}
+
+	var req CreateDocumentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
 	}
 
-	docs, err := h.queries.ListDocumentsByVehicle(c.Context(), sql.NullInt32{Int32: int32(vehicleId), Valid: true})
+	fields, msg := req.parse(c.Context(), h.queries)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	doc, err := h.queries.UpdateDocument(c.Context(), repository.UpdateDocumentParams{
+		ID:                 int32(id),
+		Name:               req.Name,
+		Type:               sql.NullString{String: req.Type, Valid: req.Type != ""},
+		FileUrl:            req.FileUrl,
+		ExpiryDate:         fields.expiryDate,
+		Notes:              sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		Category:           fields.category,
+		IssueDate:          fields.issueDate,
+		Issuer:             sql.NullString{String: req.Issuer, Valid: req.Issuer != ""},
+		PolicyNumber:       sql.NullString{String: req.PolicyNumber, Valid: req.PolicyNumber != ""},
+		RegistrationNumber: sql.NullString{String: req.RegistrationNumber, Valid: req.RegistrationNumber != ""},
+		Tags:               fields.tags,
+	})
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch documents"})
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Document not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update document", "details": err.Error()})
+	}
+
+	response := []DocumentResponse{mapDocumentToResponse(doc)}
+	if err := h.withFiles(c.Context(), response); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch document files", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": response[0]})
+}
+
+// searchParams reads the document filters shared by ListDocuments and SearchDocuments:
+// ?category=, ?tag=, ?q=, ?expires_within=<days> or ?expires_from= and ?expires_to=, and ?archived=true.
+func searchParams(c *fiber.Ctx) (repository.SearchDocumentsByCreatedAtAscParams, string) {
+	params := repository.SearchDocumentsByCreatedAtAscParams{
+		Category:        sql.NullString{String: c.Query("category"), Valid: c.Query("category") != ""},
+		Tag:             sql.NullString{String: strings.ToLower(c.Query("tag")), Valid: c.Query("tag") != ""},
+		Query:           sql.NullString{String: c.Query("q"), Valid: c.Query("q") != ""},
+		IncludeArchived: c.QueryBool("archived"),
+	}
+
+	if within := c.Query("expires_within"); within != "" {
+		days, err := strconv.Atoi(within)
+		if err != nil || days < 0 {
+			return params, "Invalid expires_within, use a number of days"
+		}
+		params.ExpiresTo = sql.NullTime{Time: forecast.Today().AddDate(0, 0, days), Valid: true}
+	}
+	for _, d := range []struct {
+		key string
+		out *sql.NullTime
+	}{
+		{"expires_from", &params.ExpiresFrom},
+		{"expires_to", &params.ExpiresTo},
+	} {
+		if v := c.Query(d.key); v != "" {
+			parsed, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return params, fmt.Sprintf("Invalid %s, use YYYY-MM-DD", d.key)
+			}
+			*d.out = sql.NullTime{Time: parsed, Valid: true}
+		}
+	}
+	return params, ""
+}
+
+var documentSorts = sortKeys[repository.Document]{
+	"created_at":  func(d repository.Document) float64 { return nullTimestampKey(d.CreatedAt) },
+	"expiry_date": func(d repository.Document) float64 { return nullDateKey(d.ExpiryDate) },
+	"issue_date":  func(d repository.Document) float64 { return nullDateKey(d.IssueDate) },
+}
+
+// listDocuments runs the search query for sort, like listFuelLogs.
+func (h *Handler) listDocuments(ctx context.Context, sort string, p repository.SearchDocumentsByCreatedAtAscParams) ([]repository.Document, error) {
+	switch sort {
+	case "created_at":
+		return h.queries.SearchDocumentsByCreatedAtAsc(ctx, p)
+	case "-created_at":
+		return h.queries.SearchDocumentsByCreatedAtDesc(ctx, repository.SearchDocumentsByCreatedAtDescParams(p))
+	case "expiry_date":
+		return h.queries.SearchDocumentsByExpiryDateAsc(ctx, repository.SearchDocumentsByExpiryDateAscParams(p))
+	case "-expiry_date":
+		return h.queries.SearchDocumentsByExpiryDateDesc(ctx, repository.SearchDocumentsByExpiryDateDescParams(p))
+	case "issue_date":
+		return h.queries.SearchDocumentsByIssueDateAsc(ctx, repository.SearchDocumentsByIssueDateAscParams(p))
+	case "-issue_date":
+		return h.queries.SearchDocumentsByIssueDateDesc(ctx, repository.SearchDocumentsByIssueDateDescParams(p))
 	}
+	return nil, fmt.Errorf("no document query for sort %q", sort)
+}
+
+// searchDocuments returns one page of the documents matching params, newest first by
+// default. Documents without an expiry or issue date sort after the rest.
+func (h *Handler) searchDocuments(c *fiber.Ctx, params repository.SearchDocumentsByCreatedAtAscParams) error {
+	opts, msg := parseListOptions(c, documentSorts, "-created_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	params.AfterKey = opts.afterKey()
+	params.AfterID = opts.afterID()
+	params.Limit = opts.fetchLimit()
+
+	docs, err := h.listDocuments(c.Context(), opts.sortParam(), params)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch documents", "details": err.Error()})
+	}
+	total, err := h.queries.CountDocuments(c.Context(), repository.CountDocumentsParams{
+		VehicleID:       params.VehicleID,
+		Category:        params.Category,
+		Tag:             params.Tag,
+		ExpiresFrom:     params.ExpiresFrom,
+		ExpiresTo:       params.ExpiresTo,
+		Query:           params.Query,
+		IncludeArchived: params.IncludeArchived,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch documents", "details": err.Error()})
+	}
+	docs, meta := pageOf(docs, opts, total, documentSorts, func(d repository.Document) int32 { return d.ID })
 
 	response := make([]DocumentResponse, len(docs))
 	for i, d := range docs {
 		response[i] = mapDocumentToResponse(d)
 	}
+	if err := h.withFiles(c.Context(), response); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch document files", "details": err.Error()})
+	}
 
-	return c.JSON(fiber.Map{"data": response})
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+// ListDocuments lists a vehicle's current documents. Archived (renewed) documents are only
+// included with ?archived=true. See searchParams for the other filters.
+func (h *Handler) ListDocuments(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	params, msg := searchParams(c)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	params.VehicleID = sql.NullInt32{Int32: int32(vehicleId), Valid: true}
+
+	return h.searchDocuments(c, params)
+}
+
+// SearchDocuments lists documents across all vehicles, optionally narrowed with ?vehicle_id=.
+func (h *Handler) SearchDocuments(c *fiber.Ctx) error {
+	params, msg := searchParams(c)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	if v := c.QueryInt("vehicle_id"); v > 0 {
+		params.VehicleID = sql.NullInt32{Int32: int32(v), Valid: true}
+	}
+
+	return h.searchDocuments(c, params)
 }
 
 func (h *Handler) DeleteDocument(c *fiber.Ctx) error {
@@ -99,9 +403,234 @@ func (h *Handler) DeleteDocument(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid document ID"})
 	}
 
+	files, err := h.queries.ListDocumentFiles(c.Context(), int32(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete document"})
+	}
+
 	err = h.queries.DeleteDocument(c.Context(), int32(id))
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete document"})
 	}
+
+	for _, f := range files {
+		if err := h.storage.Delete(f.StorageKey); err != nil {
+			log.Printf("Failed to remove stored file %s: %v", f.StorageKey, err)
+		}
+	}
+	return c.JSON(fiber.Map{"message": "Deleted"})
+}
+
+var expiringDocumentSorts = sortKeys[repository.Document]{
+	"expiry_date": func(d repository.Document) float64 { return nullDateKey(d.ExpiryDate) },
+}
+
+// ListExpiringDocuments returns current documents across all vehicles that have expired
+// or expire within ?days= (default 30), soonest first.
+func (h *Handler) ListExpiringDocuments(c *fiber.Ctx) error {
+	days := c.QueryInt("days", 30)
+	if days < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Days must not be negative"})
+	}
+	opts, msg := parseListOptions(c, expiringDocumentSorts, "expiry_date")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	today := forecast.Today()
+	docs, err := h.queries.ListExpiringDocuments(c.Context(), today.AddDate(0, 0, days))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch documents", "details": err.Error()})
+	}
+	docs, meta := paginate(docs, opts, expiringDocumentSorts, func(d repository.Document) int32 { return d.ID })
+
+	vehicles, err := h.queries.ListVehicles(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+	}
+	names := make(map[int32]string, len(vehicles))
+	for _, v := range vehicles {
+		names[v.ID] = v.Name
+	}
+
+	response := make([]ExpiringDocumentResponse, len(docs))
+	for i, d := range docs {
+		left := int(d.ExpiryDate.Time.Sub(today).Hours() / 24)
+		response[i] = ExpiringDocumentResponse{
+			DocumentResponse: mapDocumentToResponse(d),
+			VehicleName:      names[d.VehicleID.Int32],
+			DaysLeft:         left,
+			Expired:          left < 0,
+		}
+	}
+
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+// RenewDocument stores a replacement for a document. The old document is archived and
+// the new one links back to it, both in one transaction. Empty fields are carried over, except the file and dates.
+func (h *Handler) RenewDocument(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid document ID"})
+	}
+
+	var req CreateDocumentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	fields, msg := req.parse(c.Context(), h.queries)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	old, err := h.queries.GetDocument(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Document not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch document", "details": err.Error()})
+	}
+	if old.ArchivedAt.Valid {
+		return c.Status(409).JSON(fiber.Map{"error": "Document has already been renewed"})
+	}
+
+	name := req.Name
+	if name == "" {
+		name = old.Name
+	}
+	params := repository.CreateDocumentParams{
+		VehicleID:          old.VehicleID,
+		Name:               name,
+		Type:               old.Type,
+		FileUrl:            req.FileUrl,
+		ExpiryDate:         fields.expiryDate,
+		Notes:              old.Notes,
+		PreviousVersionID:  sql.NullInt32{Int32: old.ID, Valid: true},
+		Category:           old.Category,
+		IssueDate:          fields.issueDate,
+		Issuer:             old.Issuer,
+		PolicyNumber:       old.PolicyNumber,
+		RegistrationNumber: old.RegistrationNumber,
+		Tags:               nonNilStrings(old.Tags),
+	}
+	if req.Type != "" {
+		params.Type = sql.NullString{String: req.Type, Valid: true}
+	}
+	if req.Notes != "" {
+		params.Notes = sql.NullString{String: req.Notes, Valid: true}
+	}
+	if fields.category.Valid {
+		params.Category = fields.category
+	}
+	if req.Issuer != "" {
+		params.Issuer = sql.NullString{String: req.Issuer, Valid: true}
+	}
+	if req.PolicyNumber != "" {
+		params.PolicyNumber = sql.NullString{String: req.PolicyNumber, Valid: true}
+	}
+	if req.RegistrationNumber != "" {
+		params.RegistrationNumber = sql.NullString{String: req.RegistrationNumber, Valid: true}
+	}
+	if len(fields.tags) > 0 {
+		params.Tags = fields.tags
+	}
+
+	// The archive only matches a current document, so of two renewals racing each other
+	// the second finds no row and nothing of it is saved
+	var doc repository.Document
+	err = h.inTx(c.Context(), func(q *repository.Queries) error {
+		if _, err := q.ArchiveDocument(c.Context(), old.ID); err != nil {
+			return err
+		}
+		doc, err = q.CreateDocument(c.Context(), params)
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(409).JSON(fiber.Map{"error": "Document has already been renewed"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to renew document", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapDocumentToResponse(doc)})
+}
+
+// maxDocumentVersions bounds the version walk in case of a corrupted chain.
+const maxDocumentVersions = 100
+
+// ListDocumentVersions returns a document followed by the versions it replaced, newest first.
+func (h *Handler) ListDocumentVersions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid document ID"})
+	}
+
+	var response []DocumentResponse
+	next := sql.NullInt32{Int32: int32(id), Valid: true}
+	for next.Valid && len(response) < maxDocumentVersions {
+		doc, err := h.queries.GetDocument(c.Context(), next.Int32)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				if len(response) > 0 {
+					// An older version was deleted; the chain ends here
+					break
+				}
+				return c.Status(404).JSON(fiber.Map{"error": "Document not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch document", "details": err.Error()})
+		}
+		response = append(response, mapDocumentToResponse(doc))
+		next = doc.PreviousVersionID
+	}
+
+	if err := h.withFiles(c.Context(), response); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch document files", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *Handler) ListDocumentCategories(c *fiber.Ctx) error {
+	categories, err := h.queries.ListDocumentCategories(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch document categories"})
+	}
+
+	response := make([]DocumentCategoryResponse, len(categories))
+	for i, cat := range categories {
+		response[i] = DocumentCategoryResponse{Slug: cat.Slug, Name: cat.Name, TracksExpiry: cat.TracksExpiry}
+	}
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *Handler) CreateDocumentCategory(c *fiber.Ctx) error {
+	var req DocumentCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	req.Slug = strings.ToLower(strings.TrimSpace(req.Slug))
+	if req.Slug == "" || req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Slug and name are required"})
+	}
+
+	cat, err := h.queries.CreateDocumentCategory(c.Context(), repository.CreateDocumentCategoryParams{
+		Slug:         req.Slug,
+		Name:         req.Name,
+		TracksExpiry: req.TracksExpiry,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create document category", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": DocumentCategoryResponse{Slug: cat.Slug, Name: cat.Name, TracksExpiry: cat.TracksExpiry}})
+}
+
+func (h *Handler) DeleteDocumentCategory(c *fiber.Ctx) error {
+	err := h.queries.DeleteDocumentCategory(c.Context(), c.Params("slug"))
+	if err != nil {
+		// Categories still in use are protected by the foreign key
+		return c.Status(409).JSON(fiber.Map{"error": "Failed to delete document category", "details": err.Error()})
+	}
 	return c.JSON(fiber.Map{"message": "Deleted"})
 }
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/thumbnail"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DocumentFileResponse struct {
+	ID           int32  `json:"id"`
+	DocumentID   int32  `json:"document_id"`
+	Label        string `json:"label"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    int64  `json:"size_bytes"`
+	Url          string `json:"url"`
+	ThumbnailUrl string `json:"thumbnail_url,omitempty"` // set once the thumbnail has been generated
+}
+
+func mapDocumentFileToResponse(f repository.DocumentFile) DocumentFileResponse {
+	resp := DocumentFileResponse{
+		ID:          f.ID,
+		DocumentID:  f.DocumentID,
+		Label:       f.Label.String,
+		FileName:    f.FileName,
+		ContentType: f.ContentType,
+		SizeBytes:   f.SizeBytes,
+		Url:         fmt.Sprintf("/api/v1/documents/files/%d", f.ID),
+	}
+	if f.ThumbnailStatus == thumbnail.StatusReady {
+		resp.ThumbnailUrl = fmt.Sprintf("/api/v1/documents/files/%d/thumbnail", f.ID)
+	}
+	return resp
+}
+
+// sniffContentType works out the type of an upload from its first bytes, falling back to
+// the file name's extension, and rewinds it for storing. The content is trusted over the
+// client's header.
+func sniffContentType(src multipart.File, fileName string) (string, error) {
+	sniff := make([]byte, 512)
+	n, _ := src.Read(sniff)
+	contentType := http.DetectContentType(sniff[:n])
+	if contentType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			contentType = byExt
+		}
+	}
+	if _, err := src.Seek(0, 0); err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
+
+// UploadDocumentFile attaches an uploaded file (multipart field "file") to a document.
+// An optional "label" form field names the side or page, e.g. front or back.
+func (h *Handler) UploadDocumentFile(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid document ID"})
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "A file is required"})
+	}
+
+	if _, err := h.queries.GetDocument(c.Context(), int32(id)); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Document not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch document", "details": err.Error()})
+	}
+
+	existing, err := h.queries.ListDocumentFiles(c.Context(), int32(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch document files", "details": err.Error()})
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Failed to read upload", "details": err.Error()})
+	}
+	defer src.Close()
+
+	contentType, err := sniffContentType(src, header.Filename)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to read upload", "details": err.Error()})
+	}
+
+	key, size, err := h.storage.Save(src)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to store file", "details": err.Error()})
+	}
+
+	label := c.FormValue("label")
+	file, err := h.queries.CreateDocumentFile(c.Context(), repository.CreateDocumentFileParams{
+		DocumentID:  int32(id),
+		Label:       sql.NullString{String: label, Valid: label != ""},
+		FileName:    filepath.Base(header.Filename),
+		ContentType: contentType,
+		SizeBytes:   size,
+		StorageKey:  key,
+		Position:    int32(len(existing)),
+	})
+	if err != nil {
+		if delErr := h.storage.Delete(key); delErr != nil {
+			log.Printf("Failed to remove stored file %s: %v", key, delErr)
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save file", "details": err.Error()})
+	}
+
+	if thumbnail.Supported(contentType) {
+		h.scheduler.GenerateThumbnails()
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapDocumentFileToResponse(file)})
+}
+
+// GetDocumentFile streams a stored file. Use ?download=true to force a download.
+func (h *Handler) GetDocumentFile(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("fileId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid file ID"})
+	}
+
+	file, err := h.queries.GetDocumentFile(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "File not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch file", "details": err.Error()})
+	}
+
+	r, err := h.storage.Open(file.StorageKey)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to open file", "details": err.Error()})
+	}
+
+	disposition := "inline"
+	if c.QueryBool("download") {
+		disposition = "attachment"
+	}
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": file.FileName}))
+	c.Set("X-Content-Type-Options", "nosniff")
+	return c.SendStream(r, int(file.SizeBytes))
+}
+
+// GetDocumentFileThumbnail serves a file's JPEG thumbnail. Thumbnails never change for a
+// given file, so they can be cached by the browser indefinitely.
+func (h *Handler) GetDocumentFileThumbnail(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("fileId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid file ID"})
+	}
+
+	file, err := h.queries.GetDocumentFile(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "File not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch file", "details": err.Error()})
+	}
+	if file.ThumbnailStatus != thumbnail.StatusReady {
+		return c.Status(404).JSON(fiber.Map{"error": "Thumbnail not available", "status": file.ThumbnailStatus})
+	}
+
+	etag := `"` + file.StorageKey + `"`
+	c.Set(fiber.HeaderCacheControl, "private, max-age=31536000, immutable")
+	c.Set(fiber.HeaderETag, etag)
+	if c.Get(fiber.HeaderIfNoneMatch) == etag {
+		return c.SendStatus(304)
+	}
+
+	r, err := h.storage.OpenVariant(file.StorageKey, thumbnail.Variant)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to open thumbnail", "details": err.Error()})
+	}
+	c.Set(fiber.HeaderContentType, "image/jpeg")
+	return c.SendStream(r)
+}
+
+func (h *Handler) DeleteDocumentFile(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("fileId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid file ID"})
+	}
+
+	file, err := h.queries.GetDocumentFile(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "File not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch file", "details": err.Error()})
+	}
+
+	if err := h.queries.DeleteDocumentFile(c.Context(), file.ID); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete file"})
+	}
+	if err := h.storage.Delete(file.StorageKey); err != nil {
+		log.Printf("Failed to remove stored file %s: %v", file.StorageKey, err)
+	}
+	return c.JSON(fiber.Map{"message": "Deleted"})
+}
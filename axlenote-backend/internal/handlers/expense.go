@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/expense"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateExpenseRequest struct {
+	VendorID          int32   `json:"vendor_id"` // optional
+	Category          string  `json:"category"`  // toll, parking, wash, insurance, tax, loan, fine, other
+	Date              string  `json:"date"`      // YYYY-MM-DD
+	Amount            float64 `json:"amount"`
+	Odometer          int32   `json:"odometer"` // optional
+	Description       string  `json:"description"`
+	Recurrence        string  `json:"recurrence"`          // none (default), monthly, yearly
+	RecurrenceEndDate string  `json:"recurrence_end_date"` // YYYY-MM-DD, optional
+}
+
+type ExpenseResponse struct {
+	ID                 int32                 `json:"id"`
+	VehicleID          int32                 `json:"vehicle_id"`
+	VendorID           int32                 `json:"vendor_id,omitempty"`
+	Category           string                `json:"category"`
+	Date               string                `json:"date"`
+	Amount             float64               `json:"amount"`
+	Odometer           int32                 `json:"odometer,omitempty"`
+	Description        string                `json:"description"`
+	Recurrence         string                `json:"recurrence"`
+	RecurrenceEndDate  string                `json:"recurrence_end_date,omitempty"`
+	NextDueDate        string                `json:"next_due_date,omitempty"`        // of the next copy, while the series runs
+	RecurringExpenseID int32                 `json:"recurring_expense_id,omitempty"` // set on copies of a recurring expense
+	Files              []ExpenseFileResponse `json:"files"`
+	CreatedAt          string                `json:"created_at"`
+}
+
+func mapExpenseToResponse(e repository.Expense) ExpenseResponse {
+	amount, _ := strconv.ParseFloat(e.Amount, 64)
+	resp := ExpenseResponse{
+		ID:                 e.ID,
+		VehicleID:          e.VehicleID,
+		VendorID:           e.VendorID.Int32,
+		Category:           e.Category,
+		Date:               e.Date.Format("2006-01-02"),
+		Amount:             amount,
+		Odometer:           e.Odometer.Int32,
+		Description:        e.Description.String,
+		Recurrence:         e.Recurrence,
+		RecurringExpenseID: e.RecurringExpenseID.Int32,
+		Files:              []ExpenseFileResponse{},
+		CreatedAt:          e.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if e.RecurrenceEndDate.Valid {
+		resp.RecurrenceEndDate = e.RecurrenceEndDate.Time.Format("2006-01-02")
+	}
+	if e.NextDueDate.Valid {
+		resp.NextDueDate = e.NextDueDate.Time.Format("2006-01-02")
+	}
+	return resp
+}
+
+// validate checks a request and returns its parsed dates, or an error message.
+func (req *CreateExpenseRequest) validate() (date time.Time, end sql.NullTime, msg string) {
+	if req.Recurrence == "" {
+		req.Recurrence = expense.RecurrenceNone
+	}
+	if !slices.Contains(expense.Categories, req.Category) {
+		return date, end, "Invalid category, use one of " + strings.Join(expense.Categories, ", ")
+	}
+	if !slices.Contains(expense.Recurrences, req.Recurrence) {
+		return date, end, "Invalid recurrence, use one of " + strings.Join(expense.Recurrences, ", ")
+	}
+	if req.Amount <= 0 {
+		return date, end, "Amount must be positive"
+	}
+	if req.Odometer < 0 {
+		return date, end, "Odometer must not be negative"
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return date, end, "Invalid date format, use YYYY-MM-DD"
+	}
+	if req.RecurrenceEndDate != "" {
+		if req.Recurrence == expense.RecurrenceNone {
+			return date, end, "recurrence_end_date needs a monthly or yearly recurrence"
+		}
+		parsed, err := time.Parse("2006-01-02", req.RecurrenceEndDate)
+		if err != nil {
+			return date, end, "Invalid recurrence_end_date, use YYYY-MM-DD"
+		}
+		if parsed.Before(date) {
+			return date, end, "recurrence_end_date must not be before date"
+		}
+		end = sql.NullTime{Time: parsed, Valid: true}
+	}
+	return date, end, ""
+}
+
+// vendorExists checks the vendor an expense links to, if any.
+func (h *Handler) vendorExists(ctx context.Context, id int32) (bool, error) {
+	if id <= 0 {
+		return true, nil
+	}
+	if _, err := h.queries.GetVendor(ctx, id); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// nextDue works out the next_due_date to store for an expense dated date: the first
+// copy after after, or NULL when it does not recur or the series has ended.
+func nextDue(date time.Time, recurrence string, end sql.NullTime, after time.Time) sql.NullTime {
+	var until *time.Time
+	if end.Valid {
+		until = &end.Time
+	}
+	next, ok := expense.NextDue(date, recurrence, until, after)
+	return sql.NullTime{Time: next, Valid: ok}
+}
+
+// withExpenseFiles attaches uploaded files to the expenses in place.
+func (h *Handler) withExpenseFiles(ctx context.Context, expenses []ExpenseResponse) error {
+	if len(expenses) == 0 {
+		return nil
+	}
+	ids := make([]int32, len(expenses))
+	index := make(map[int32]int, len(expenses))
+	for i, e := range expenses {
+		ids[i] = e.ID
+		index[e.ID] = i
+	}
+
+	files, err := h.queries.ListExpenseFilesByExpenses(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		i := index[f.ExpenseID]
+		expenses[i].Files = append(expenses[i].Files, mapExpenseFileToResponse(f))
+	}
+	return nil
+}
+
+var expenseSorts = sortKeys[repository.Expense]{
+	"date":   func(e repository.Expense) float64 { return dateKey(e.Date) },
+	"amount": func(e repository.Expense) float64 { return numericKey(e.Amount) },
+}
+
+// listExpenses runs the expense query for sort, like listFuelLogs.
+func (h *Handler) listExpenses(ctx context.Context, sort string, p repository.ListExpensesByDateAscParams) ([]repository.Expense, error) {
+	switch sort {
+	case "date":
+		return h.queries.ListExpensesByDateAsc(ctx, p)
+	case "-date":
+		return h.queries.ListExpensesByDateDesc(ctx, repository.ListExpensesByDateDescParams(p))
+	case "amount":
+		return h.queries.ListExpensesByAmountAsc(ctx, repository.ListExpensesByAmountAscParams(p))
+	case "-amount":
+		return h.queries.ListExpensesByAmountDesc(ctx, repository.ListExpensesByAmountDescParams(p))
+	}
+	return nil, fmt.Errorf("no expense query for sort %q", sort)
+}
+
+// ListExpenses pages through a vehicle's expenses, newest first by default. Besides the
+// common list parameters (see parseListOptions) it accepts ?category= and ?vendor_id=;
+// the cost range applies to the amount.
+func (h *Handler) ListExpenses(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	opts, msg := parseListOptions(c, expenseSorts, "-date")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	category := queryNullString(c, "category")
+	if category.Valid && !slices.Contains(expense.Categories, category.String) {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid category, use one of " + strings.Join(expense.Categories, ", ")})
+	}
+	var vendorID sql.NullInt32
+	if v := c.Query("vendor_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid vendor ID"})
+		}
+		vendorID = sql.NullInt32{Int32: int32(id), Valid: true}
+	}
+
+	filters := repository.CountExpensesByVehicleParams{
+		VehicleID:   int32(vehicleId),
+		DateFrom:    opts.dateFrom,
+		DateTo:      opts.dateTo,
+		OdometerMin: opts.odometerMin,
+		OdometerMax: opts.odometerMax,
+		CostMin:     opts.costMin,
+		CostMax:     opts.costMax,
+		Category:    category,
+		VendorID:    vendorID,
+	}
+	expenses, err := h.listExpenses(c.Context(), opts.sortParam(), repository.ListExpensesByDateAscParams{
+		VehicleID:   filters.VehicleID,
+		DateFrom:    filters.DateFrom,
+		DateTo:      filters.DateTo,
+		OdometerMin: filters.OdometerMin,
+		OdometerMax: filters.OdometerMax,
+		CostMin:     filters.CostMin,
+		CostMax:     filters.CostMax,
+		Category:    filters.Category,
+		VendorID:    filters.VendorID,
+		AfterKey:    opts.afterKey(),
+		AfterID:     opts.afterID(),
+		Limit:       opts.fetchLimit(),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch expenses", "details": err.Error()})
+	}
+	total, err := h.queries.CountExpensesByVehicle(c.Context(), filters)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch expenses", "details": err.Error()})
+	}
+	expenses, meta := pageOf(expenses, opts, total, expenseSorts, func(e repository.Expense) int32 { return e.ID })
+
+	response := make([]ExpenseResponse, len(expenses))
+	for i, e := range expenses {
+		response[i] = mapExpenseToResponse(e)
+	}
+	if err := h.withExpenseFiles(c.Context(), response); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch expense files", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+func (h *Handler) GetExpense(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid expense ID"})
+	}
+
+	e, err := h.queries.GetExpense(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Expense not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	response := []ExpenseResponse{mapExpenseToResponse(e)}
+	if err := h.withExpenseFiles(c.Context(), response); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch expense files", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": response[0]})
+}
+
+// CreateExpense records a toll, parking fee, premium, EMI or other running cost. A
+// monthly or yearly expense is copied by the scheduler on each later due date, including
+// any that have already passed.
+func (h *Handler) CreateExpense(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	var req CreateExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	date, end, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	if _, err := h.queries.GetVehicle(c.Context(), int32(vehicleId)); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+	if ok, err := h.vendorExists(c.Context(), req.VendorID); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	} else if !ok {
+		return c.Status(400).JSON(fiber.Map{"error": "Vendor not found"})
+	}
+
+	e, err := h.queries.CreateExpense(c.Context(), repository.CreateExpenseParams{
+		VehicleID:         int32(vehicleId),
+		VendorID:          sql.NullInt32{Int32: req.VendorID, Valid: req.VendorID > 0},
+		Category:          req.Category,
+		Date:              date,
+		Amount:            strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		Odometer:          sql.NullInt32{Int32: req.Odometer, Valid: req.Odometer > 0},
+		Description:       sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Recurrence:        req.Recurrence,
+		RecurrenceEndDate: end,
+		NextDueDate:       nextDue(date, req.Recurrence, end, date),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create expense", "details": err.Error()})
+	}
+
+	if e.NextDueDate.Valid && !e.NextDueDate.Time.After(forecast.Today()) {
+		h.scheduler.GenerateExpenses()
+	}
+
+	response := mapExpenseToResponse(e)
+	h.emit(c.Context(), webhook.ExpenseCreated, response)
+
+	return c.Status(201).JSON(fiber.Map{"data": response})
+}
+
+// UpdateExpense changes an expense. Changing the date or recurrence of a recurring
+// expense only affects copies due after today; copies already made are left as they are.
+func (h *Handler) UpdateExpense(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid expense ID"})
+	}
+
+	var req CreateExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	date, end, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	existing, err := h.queries.GetExpense(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Expense not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+	if ok, err := h.vendorExists(c.Context(), req.VendorID); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	} else if !ok {
+		return c.Status(400).JSON(fiber.Map{"error": "Vendor not found"})
+	}
+
+	// Carry on from the next copy while the schedule stays the same
+	after := forecast.Today()
+	if existing.NextDueDate.Valid && existing.Recurrence == req.Recurrence && existing.Date.Equal(date) {
+		after = existing.NextDueDate.Time.AddDate(0, 0, -1)
+	}
+	if date.After(after) {
+		after = date
+	}
+
+	e, err := h.queries.UpdateExpense(c.Context(), repository.UpdateExpenseParams{
+		ID:                int32(id),
+		VendorID:          sql.NullInt32{Int32: req.VendorID, Valid: req.VendorID > 0},
+		Category:          req.Category,
+		Date:              date,
+		Amount:            strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		Odometer:          sql.NullInt32{Int32: req.Odometer, Valid: req.Odometer > 0},
+		Description:       sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Recurrence:        req.Recurrence,
+		RecurrenceEndDate: end,
+		NextDueDate:       nextDue(date, req.Recurrence, end, after),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Expense not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update expense", "details": err.Error()})
+	}
+
+	if e.NextDueDate.Valid && !e.NextDueDate.Time.After(forecast.Today()) {
+		h.scheduler.GenerateExpenses()
+	}
+
+	response := []ExpenseResponse{mapExpenseToResponse(e)}
+	if err := h.withExpenseFiles(c.Context(), response); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch expense files", "details": err.Error()})
+	}
+	h.emit(c.Context(), webhook.ExpenseUpdated, response[0])
+
+	return c.JSON(fiber.Map{"data": response[0]})
+}
+
+// DeleteExpense removes an expense and its files. Copies of a recurring expense are kept.
+func (h *Handler) DeleteExpense(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid expense ID"})
+	}
+
+	files, err := h.queries.ListExpenseFiles(c.Context(), int32(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete expense"})
+	}
+
+	if err := h.queries.DeleteExpense(c.Context(), int32(id)); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete expense"})
+	}
+
+	for _, f := range files {
+		if err := h.storage.Delete(f.StorageKey); err != nil {
+			log.Printf("Failed to remove stored file %s: %v", f.StorageKey, err)
+		}
+	}
+	h.emit(c.Context(), webhook.ExpenseDeleted, fiber.Map{"id": id})
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
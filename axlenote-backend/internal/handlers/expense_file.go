@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"mime"
+	"path/filepath"
+	"strconv"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExpenseFileResponse struct {
+	ID          int32  `json:"id"`
+	ExpenseID   int32  `json:"expense_id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	Url         string `json:"url"`
+}
+
+func mapExpenseFileToResponse(f repository.ExpenseFile) ExpenseFileResponse {
+	return ExpenseFileResponse{
+		ID:          f.ID,
+		ExpenseID:   f.ExpenseID,
+		FileName:    f.FileName,
+		ContentType: f.ContentType,
+		SizeBytes:   f.SizeBytes,
+		Url:         fmt.Sprintf("/api/v1/expenses/files/%d", f.ID),
+	}
+}
+
+// UploadExpenseFile attaches an uploaded receipt or other file (multipart field "file")
+// to an expense.
+func (h *Handler) UploadExpenseFile(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid expense ID"})
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "A file is required"})
+	}
+
+	if _, err := h.queries.GetExpense(c.Context(), int32(id)); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Expense not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch expense", "details": err.Error()})
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Failed to read upload", "details": err.Error()})
+	}
+	defer src.Close()
+
+	contentType, err := sniffContentType(src, header.Filename)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to read upload", "details": err.Error()})
+	}
+
+	key, size, err := h.storage.Save(src)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to store file", "details": err.Error()})
+	}
+
+	file, err := h.queries.CreateExpenseFile(c.Context(), repository.CreateExpenseFileParams{
+		ExpenseID:   int32(id),
+		FileName:    filepath.Base(header.Filename),
+		ContentType: contentType,
+		SizeBytes:   size,
+		StorageKey:  key,
+	})
+	if err != nil {
+		if delErr := h.storage.Delete(key); delErr != nil {
+			log.Printf("Failed to remove stored file %s: %v", key, delErr)
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save file", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapExpenseFileToResponse(file)})
+}
+
+// GetExpenseFile streams a stored file. Use ?download=true to force a download.
+func (h *Handler) GetExpenseFile(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("fileId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid file ID"})
+	}
+
+	file, err := h.queries.GetExpenseFile(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "File not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch file", "details": err.Error()})
+	}
+
+	r, err := h.storage.Open(file.StorageKey)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to open file", "details": err.Error()})
+	}
+
+	disposition := "inline"
+	if c.QueryBool("download") {
+		disposition = "attachment"
+	}
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": file.FileName}))
+	c.Set("X-Content-Type-Options", "nosniff")
+	return c.SendStream(r, int(file.SizeBytes))
+}
+
+func (h *Handler) DeleteExpenseFile(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("fileId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid file ID"})
+	}
+
+	file, err := h.queries.GetExpenseFile(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "File not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch file", "details": err.Error()})
+	}
+
+	if err := h.queries.DeleteExpenseFile(c.Context(), file.ID); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete file"})
+	}
+	if err := h.storage.Delete(file.StorageKey); err != nil {
+		log.Printf("Failed to remove stored file %s: %v", file.StorageKey, err)
+	}
+	return c.JSON(fiber.Map{"message": "Deleted"})
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"database/sql"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/tco"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateFixedCostRequest struct {
+	Category  string  `json:"category"` // insurance, tax, financing, depreciation, other
+	Amount    float64 `json:"amount"`
+	Frequency string  `json:"frequency"`  // once, monthly, yearly (default)
+	StartDate string  `json:"start_date"` // YYYY-MM-DD
+	EndDate   string  `json:"end_date"`   // YYYY-MM-DD, optional
+	Notes     string  `json:"notes"`
+}
+
+type FixedCostResponse struct {
+	ID        int32   `json:"id"`
+	VehicleID int32   `json:"vehicle_id"`
+	Category  string  `json:"category"`
+	Amount    float64 `json:"amount"`
+	Frequency string  `json:"frequency"`
+	StartDate string  `json:"start_date"`
+	EndDate   string  `json:"end_date,omitempty"`
+	Notes     string  `json:"notes"`
+}
+
+func mapFixedCostToResponse(fc repository.FixedCost) FixedCostResponse {
+	amount, _ := strconv.ParseFloat(fc.Amount, 64)
+	resp := FixedCostResponse{
+		ID:        fc.ID,
+		VehicleID: fc.VehicleID,
+		Category:  fc.Category,
+		Amount:    amount,
+		Frequency: fc.Frequency,
+		StartDate: fc.StartDate.Format("2006-01-02"),
+		Notes:     fc.Notes.String,
+	}
+	if fc.EndDate.Valid {
+		resp.EndDate = fc.EndDate.Time.Format("2006-01-02")
+	}
+	return resp
+}
+
+// validate checks a request and returns its parsed dates, or an error message.
+func (req *CreateFixedCostRequest) validate() (start time.Time, end sql.NullTime, msg string) {
+	if req.Frequency == "" {
+		req.Frequency = tco.FrequencyYearly
+	}
+	if !slices.Contains(tco.Categories, req.Category) {
+		return start, end, "Invalid category, use one of " + strings.Join(tco.Categories, ", ")
+	}
+	if !slices.Contains(tco.Frequencies, req.Frequency) {
+		return start, end, "Invalid frequency, use one of " + strings.Join(tco.Frequencies, ", ")
+	}
+	if req.Amount <= 0 {
+		return start, end, "Amount must be positive"
+	}
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return start, end, "Invalid start_date, use YYYY-MM-DD"
+	}
+	if req.EndDate != "" {
+		parsed, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return start, end, "Invalid end_date, use YYYY-MM-DD"
+		}
+		if parsed.Before(start) {
+			return start, end, "end_date must not be before start_date"
+		}
+		end = sql.NullTime{Time: parsed, Valid: true}
+	}
+	return start, end, ""
+}
+
+func (h *Handler) ListFixedCosts(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	costs, err := h.queries.ListFixedCostsByVehicle(c.Context(), int32(vehicleId))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch fixed costs", "details": err.Error()})
+	}
+
+	response := make([]FixedCostResponse, len(costs))
+	for i, fc := range costs {
+		response[i] = mapFixedCostToResponse(fc)
+	}
+	return c.JSON(fiber.Map{"data": response})
+}
+
+// CreateFixedCost records a recurring or one-off cost that does not depend on driving,
+// like insurance premiums, road tax or loan interest.
+func (h *Handler) CreateFixedCost(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	var req CreateFixedCostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	start, end, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	if _, err := h.queries.GetVehicle(c.Context(), int32(vehicleId)); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	fc, err := h.queries.CreateFixedCost(c.Context(), repository.CreateFixedCostParams{
+		VehicleID: int32(vehicleId),
+		Category:  req.Category,
+		Amount:    strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		Frequency: req.Frequency,
+		StartDate: start,
+		EndDate:   end,
+		Notes:     sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create fixed cost", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapFixedCostToResponse(fc)})
+}
+
+func (h *Handler) UpdateFixedCost(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid fixed cost ID"})
+	}
+
+	var req CreateFixedCostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	start, end, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	fc, err := h.queries.UpdateFixedCost(c.Context(), repository.UpdateFixedCostParams{
+		ID:        int32(id),
+		Category:  req.Category,
+		Amount:    strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		Frequency: req.Frequency,
+		StartDate: start,
+		EndDate:   end,
+		Notes:     sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Fixed cost not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update fixed cost", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": mapFixedCostToResponse(fc)})
+}
+
+func (h *Handler) DeleteFixedCost(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid fixed cost ID"})
+	}
+
+	if err := h.queries.DeleteFixedCost(c.Context(), int32(id)); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete fixed cost"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
+
+// tcoRange reads ?from= and ?to= (YYYY-MM-DD), defaulting to the year up to today.
+func tcoRange(c *fiber.Ctx) (from, to time.Time, msg string) {
+	to = forecast.Today()
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return from, to, "Invalid to, use YYYY-MM-DD"
+		}
+		to = parsed
+	}
+	from = to.AddDate(-1, 0, 1)
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return from, to, "Invalid from, use YYYY-MM-DD"
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		return from, to, "to must not be before from"
+	}
+	return from, to, ""
+}
+
+// sendTCO writes reports as JSON, or as a printable page with ?format=html.
+func sendTCO(c *fiber.Ctx, reports []tco.Report, data any) error {
+	switch c.Query("format", "json") {
+	case "html":
+		page, err := tco.RenderHTML(reports, time.Now())
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to render report", "details": err.Error()})
+		}
+		c.Type("html")
+		return c.SendString(page)
+	case "json":
+		return c.JSON(fiber.Map{"data": data})
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid format, use json or html"})
+	}
+}
+
+// GetVehicleTCO returns the total cost of ownership of a vehicle over ?from= to ?to=:
+// fuel, maintenance, fixed costs and depreciation, in total, per month and per distance
+// driven. Use ?format=html for a printable report.
+func (h *Handler) GetVehicleTCO(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+	from, to, msg := tcoRange(c)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	vehicle, err := h.queries.GetVehicle(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	report, err := tco.ForVehicle(c.Context(), h.queries, vehicle, from, to)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to build report", "details": err.Error()})
+	}
+	return sendTCO(c, []tco.Report{report}, report)
+}
+
+// GetTCOReport returns the cost of ownership of every vehicle, like GetVehicleTCO.
+func (h *Handler) GetTCOReport(c *fiber.Ctx) error {
+	from, to, msg := tcoRange(c)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	vehicles, err := h.queries.ListVehicles(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vehicles", "details": err.Error()})
+	}
+
+	reports := make([]tco.Report, 0, len(vehicles))
+	for _, v := range vehicles {
+		report, err := tco.ForVehicle(c.Context(), h.queries, v, from, to)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to build report", "details": err.Error()})
+		}
+		reports = append(reports, report)
+	}
+	return sendTCO(c, reports, reports)
+}
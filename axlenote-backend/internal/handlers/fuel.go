@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -79,46 +82,127 @@ func (h *Handler) CreateFuelLog(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create fuel log", "details": err.Error()})
 	}
 
-	return c.Status(201).JSON(fiber.Map{"data": mapFuelLogToResponse(log)})
+	response := mapFuelLogToResponse(log)
+	h.emit(c.Context(), webhook.FuelLogCreated, response)
+
+	return c.Status(201).JSON(fiber.Map{"data": response})
+}
+
+var fuelLogSorts = sortKeys[repository.FuelLog]{
+	"date":            func(f repository.FuelLog) float64 { return dateKey(f.Date) },
+	"odometer":        func(f repository.FuelLog) float64 { return float64(f.Odometer) },
+	"total_cost":      func(f repository.FuelLog) float64 { return numericKey(f.TotalCost) },
+	"liters":          func(f repository.FuelLog) float64 { return numericKey(f.Liters) },
+	"price_per_liter": func(f repository.FuelLog) float64 { return numericKey(f.PricePerLiter) },
 }
 
+// listFuelLogs runs the list query for sort (such as "-date"). Each sort has its own
+// query so the page is read from an index in order.
+func (h *Handler) listFuelLogs(ctx context.Context, sort string, p repository.ListFuelLogsByDateAscParams) ([]repository.FuelLog, error) {
+	switch sort {
+	case "date":
+		return h.queries.ListFuelLogsByDateAsc(ctx, p)
+	case "-date":
+		return h.queries.ListFuelLogsByDateDesc(ctx, repository.ListFuelLogsByDateDescParams(p))
+	case "odometer":
+		return h.queries.ListFuelLogsByOdometerAsc(ctx, repository.ListFuelLogsByOdometerAscParams(p))
+	case "-odometer":
+		return h.queries.ListFuelLogsByOdometerDesc(ctx, repository.ListFuelLogsByOdometerDescParams(p))
+	case "total_cost":
+		return h.queries.ListFuelLogsByTotalCostAsc(ctx, repository.ListFuelLogsByTotalCostAscParams(p))
+	case "-total_cost":
+		return h.queries.ListFuelLogsByTotalCostDesc(ctx, repository.ListFuelLogsByTotalCostDescParams(p))
+	case "liters":
+		return h.queries.ListFuelLogsByLitersAsc(ctx, repository.ListFuelLogsByLitersAscParams(p))
+	case "-liters":
+		return h.queries.ListFuelLogsByLitersDesc(ctx, repository.ListFuelLogsByLitersDescParams(p))
+	case "price_per_liter":
+		return h.queries.ListFuelLogsByPricePerLiterAsc(ctx, repository.ListFuelLogsByPricePerLiterAscParams(p))
+	case "-price_per_liter":
+		return h.queries.ListFuelLogsByPricePerLiterDesc(ctx, repository.ListFuelLogsByPricePerLiterDescParams(p))
+	}
+	return nil, fmt.Errorf("no fuel log query for sort %q", sort)
+}
+
+// ListFuelLogs pages through a vehicle's fuel logs, newest first by default. Besides the
+// common list parameters (see parseListOptions) it accepts ?full_tank=true|false; the
+// cost range applies to total_cost.
 func (h *Handler) ListFuelLogs(c *fiber.Ctx) error {
 	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
 	}
 
-	logs, err := h.queries.ListFuelLogsByVehicle(c.Context(), sql.NullInt32{Int32: int32(vehicleId), Valid: true})
+	opts, msg := parseListOptions(c, fuelLogSorts, "-date")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	fullTank, msg := queryNullBool(c, "full_tank")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	filters := repository.CountFuelLogsByVehicleParams{
+		VehicleID:   int32(vehicleId),
+		DateFrom:    opts.dateFrom,
+		DateTo:      opts.dateTo,
+		OdometerMin: opts.odometerMin,
+		OdometerMax: opts.odometerMax,
+		CostMin:     opts.costMin,
+		CostMax:     opts.costMax,
+		FullTank:    fullTank,
+	}
+	logs, err := h.listFuelLogs(c.Context(), opts.sortParam(), repository.ListFuelLogsByDateAscParams{
+		VehicleID:   filters.VehicleID,
+		DateFrom:    filters.DateFrom,
+		DateTo:      filters.DateTo,
+		OdometerMin: filters.OdometerMin,
+		OdometerMax: filters.OdometerMax,
+		CostMin:     filters.CostMin,
+		CostMax:     filters.CostMax,
+		FullTank:    filters.FullTank,
+		AfterKey:    opts.afterKey(),
+		AfterID:     opts.afterID(),
+		Limit:       opts.fetchLimit(),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch fuel logs"})
+	}
+	total, err := h.queries.CountFuelLogsByVehicle(c.Context(), filters)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch fuel logs"})
 	}
+	logs, meta := pageOf(logs, opts, total, fuelLogSorts, func(f repository.FuelLog) int32 { return f.ID })
+
+	// Mileage (km/L) of a full tank fill = distance since the previous fill / liters filled.
+	// The previous fill may be on another page, so it is looked up for the page's logs.
+	ids := make([]int32, len(logs))
+	for i, l := range logs {
+		ids[i] = l.ID
+	}
+	previous, err := h.queries.ListPreviousFuelOdometers(c.Context(), ids)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch fuel logs"})
+	}
+	prevOdometer := make(map[int32]int32, len(previous))
+	for _, p := range previous {
+		prevOdometer[p.ID] = p.PreviousOdometer
+	}
 
 	response := make([]FuelLogResponse, len(logs))
 	for i, l := range logs {
 		response[i] = mapFuelLogToResponse(l)
-		
-		// Calculate mileage (km/L) for full tank fills
-		// Mileage = (current odometer - previous odometer) / liters filled
-		// Find the previous log (the one with the closest odometer reading that is less than current)
-		if l.FullTank.Bool {
-			var prevOdometer int32 = 0
-			for _, otherLog := range logs {
-				if otherLog.Odometer < l.Odometer && otherLog.Odometer > prevOdometer {
-					prevOdometer = otherLog.Odometer
-				}
-			}
-			
-			if prevOdometer > 0 {
-				distance := float64(l.Odometer - prevOdometer)
-				liters, _ := strconv.ParseFloat(l.Liters, 64)
-				if distance > 0 && liters > 0 {
-					response[i].Mileage = distance / liters
-				}
+
+		if prev := prevOdometer[l.ID]; l.FullTank.Bool && prev > 0 {
+			distance := float64(l.Odometer - prev)
+			liters, _ := strconv.ParseFloat(l.Liters, 64)
+			if distance > 0 && liters > 0 {
+				response[i].Mileage = distance / liters
 			}
 		}
 	}
 
-	return c.JSON(fiber.Map{"data": response})
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
 }
 
 func (h *Handler) UpdateFuelLog(c *fiber.Ctx) error {
@@ -152,7 +236,10 @@ func (h *Handler) UpdateFuelLog(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to update fuel log"})
 	}
 
-	return c.JSON(fiber.Map{"data": mapFuelLogToResponse(log)})
+	response := mapFuelLogToResponse(log)
+	h.emit(c.Context(), webhook.FuelLogUpdated, response)
+
+	return c.JSON(fiber.Map{"data": response})
 }
 
 func (h *Handler) DeleteFuelLog(c *fiber.Ctx) error {
@@ -166,5 +253,7 @@ func (h *Handler) DeleteFuelLog(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete fuel log"})
 	}
 
+	h.emit(c.Context(), webhook.FuelLogDeleted, fiber.Map{"id": id})
+
 	return c.JSON(fiber.Map{"message": "Deleted successfully"})
 }
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// List endpoints page with a cursor rather than an offset, so pages stay stable while
+// rows are added. Every list response carries a meta block next to the data:
+//
+//	{"data": [...], "meta": {"total": 120, "count": 50, "limit": 50, "sort": "-date", "has_more": true, "next_cursor": "..."}}
+//
+// Pass next_cursor back as ?cursor= with the same ?sort= and filters for the next page.
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+
+	// missingSortKey puts rows without a value (a reminder with no due date, ...) after
+	// the rest. The list queries sort those rows by the largest value of the column's
+	// type, and read this key in a cursor as that value.
+	missingSortKey = 1e15
+)
+
+// sortKeys maps the fields a list can be sorted by to the value rows are ordered by.
+// Keys are numbers, with dates as Unix seconds and timestamps as Unix microseconds, so one
+// cursor format serves every list; the list queries turn a cursor's key back into the
+// column's type.
+type sortKeys[T any] map[string]func(T) float64
+
+func dateKey(t time.Time) float64 {
+	return float64(t.Unix())
+}
+
+func nullDateKey(t sql.NullTime) float64 {
+	if !t.Valid {
+		return missingSortKey
+	}
+	return dateKey(t.Time)
+}
+
+func timestampKey(t time.Time) float64 {
+	return float64(t.UnixMicro())
+}
+
+func nullTimestampKey(t sql.NullTime) float64 {
+	if !t.Valid {
+		return 0
+	}
+	return timestampKey(t.Time)
+}
+
+func numericKey(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// ListMeta describes one page of a list.
+type ListMeta struct {
+	Total      int64  `json:"total"` // rows matching the filters, across all pages
+	Count      int    `json:"count"`
+	Limit      int    `json:"limit"`
+	Sort       string `json:"sort"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type listCursor struct {
+	Sort string  `json:"s"`
+	Key  float64 `json:"k"`
+	ID   int32   `json:"i"`
+}
+
+// listOptions holds the paging, sort and filter parameters shared by list endpoints.
+// Each list applies the filters that make sense for it.
+type listOptions struct {
+	limit int
+	sort  string // field name, without the - prefix
+	desc  bool
+	after *listCursor
+
+	dateFrom, dateTo         sql.NullTime
+	odometerMin, odometerMax sql.NullInt32
+	costMin, costMax         sql.NullFloat64
+}
+
+// parseListOptions reads ?limit=, ?cursor=, ?sort=<field> (prefix with - for descending),
+// ?from= and ?to= (YYYY-MM-DD, inclusive), ?odometer_min=, ?odometer_max=, ?cost_min=
+// and ?cost_max=. It returns an error message for invalid input.
+func parseListOptions[T any](c *fiber.Ctx, keys sortKeys[T], defaultSort string) (listOptions, string) {
+	opts := listOptions{limit: c.QueryInt("limit", defaultPageSize)}
+	if opts.limit <= 0 || opts.limit > maxPageSize {
+		return opts, fmt.Sprintf("Limit must be between 1 and %d", maxPageSize)
+	}
+
+	sort := c.Query("sort", defaultSort)
+	opts.desc = strings.HasPrefix(sort, "-")
+	opts.sort = strings.TrimPrefix(sort, "-")
+	if _, ok := keys[opts.sort]; !ok {
+		fields := make([]string, 0, len(keys))
+		for f := range keys {
+			fields = append(fields, f)
+		}
+		slices.Sort(fields)
+		return opts, "Invalid sort, use one of " + strings.Join(fields, ", ")
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(v)
+		var cur listCursor
+		if err != nil || json.Unmarshal(raw, &cur) != nil {
+			return opts, "Invalid cursor"
+		}
+		if cur.Sort != sort {
+			return opts, "Cursor belongs to a different sort order"
+		}
+		opts.after = &cur
+	}
+
+	for _, d := range []struct {
+		key string
+		out *sql.NullTime
+	}{
+		{"from", &opts.dateFrom},
+		{"to", &opts.dateTo},
+	} {
+		if v := c.Query(d.key); v != "" {
+			parsed, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return opts, fmt.Sprintf("Invalid %s, use YYYY-MM-DD", d.key)
+			}
+			*d.out = sql.NullTime{Time: parsed, Valid: true}
+		}
+	}
+	for _, d := range []struct {
+		key string
+		out *sql.NullInt32
+	}{
+		{"odometer_min", &opts.odometerMin},
+		{"odometer_max", &opts.odometerMax},
+	} {
+		if v := c.Query(d.key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return opts, fmt.Sprintf("Invalid %s, use a whole number", d.key)
+			}
+			*d.out = sql.NullInt32{Int32: int32(n), Valid: true}
+		}
+	}
+	for _, d := range []struct {
+		key string
+		out *sql.NullFloat64
+	}{
+		{"cost_min", &opts.costMin},
+		{"cost_max", &opts.costMax},
+	} {
+		if v := c.Query(d.key); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return opts, fmt.Sprintf("Invalid %s, use a number", d.key)
+			}
+			*d.out = sql.NullFloat64{Float64: f, Valid: true}
+		}
+	}
+	return opts, ""
+}
+
+// queryNullBool reads an optional true/false query parameter.
+func queryNullBool(c *fiber.Ctx, key string) (sql.NullBool, string) {
+	v := c.Query(key)
+	if v == "" {
+		return sql.NullBool{}, ""
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return sql.NullBool{}, fmt.Sprintf("Invalid %s, use true or false", key)
+	}
+	return sql.NullBool{Bool: b, Valid: true}, ""
+}
+
+func queryNullString(c *fiber.Ctx, key string) sql.NullString {
+	v := c.Query(key)
+	return sql.NullString{String: v, Valid: v != ""}
+}
+
+// direction is 1 for ascending and -1 for descending, for sorting rows in memory.
+func (o listOptions) direction() float64 {
+	if o.desc {
+		return -1
+	}
+	return 1
+}
+
+func (o listOptions) afterKey() sql.NullFloat64 {
+	if o.after == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: o.after.Key, Valid: true}
+}
+
+func (o listOptions) afterID() sql.NullInt32 {
+	if o.after == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: o.after.ID, Valid: true}
+}
+
+// fetchLimit asks for one row more than the page holds, to tell whether another page follows.
+func (o listOptions) fetchLimit() int32 {
+	return int32(o.limit + 1)
+}
+
+func (o listOptions) sortParam() string {
+	if o.desc {
+		return "-" + o.sort
+	}
+	return o.sort
+}
+
+// pageOf trims rows fetched with fetchLimit to the page and builds its meta block.
+func pageOf[T any](rows []T, opts listOptions, total int64, keys sortKeys[T], id func(T) int32) ([]T, ListMeta) {
+	meta := ListMeta{Total: total, Limit: opts.limit, Sort: opts.sortParam()}
+	if len(rows) > opts.limit {
+		rows = rows[:opts.limit]
+		meta.HasMore = true
+
+		last := rows[len(rows)-1]
+		raw, _ := json.Marshal(listCursor{Sort: meta.Sort, Key: keys[opts.sort](last), ID: id(last)})
+		meta.NextCursor = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	meta.Count = len(rows)
+	return rows, meta
+}
+
+// paginate pages a list that is small enough to load whole, like vehicles or users, the
+// same way the list queries do.
+func paginate[T any](rows []T, opts listOptions, keys sortKeys[T], id func(T) int32) ([]T, ListMeta) {
+	key, dir := keys[opts.sort], opts.direction()
+	compare := func(aKey float64, aID int32, bKey float64, bID int32) int {
+		if c := cmp.Compare(dir*aKey, dir*bKey); c != 0 {
+			return c
+		}
+		return cmp.Compare(dir*float64(aID), dir*float64(bID))
+	}
+
+	sorted := slices.Clone(rows)
+	slices.SortFunc(sorted, func(a, b T) int {
+		return compare(key(a), id(a), key(b), id(b))
+	})
+
+	start := 0
+	if opts.after != nil {
+		for start < len(sorted) && compare(key(sorted[start]), id(sorted[start]), opts.after.Key, opts.after.ID) <= 0 {
+			start++
+		}
+	}
+	end := min(start+opts.limit+1, len(sorted))
+	return pageOf(sorted[start:end], opts, int64(len(sorted)), keys, id)
+}
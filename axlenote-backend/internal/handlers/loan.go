@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/expense"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/loan"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxTenureMonths bounds a loan to 50 years.
+const maxTenureMonths = 600
+
+type CreateLoanRequest struct {
+	Lender       string  `json:"lender"`
+	Principal    float64 `json:"principal"`
+	AnnualRate   float64 `json:"annual_rate"` // percent
+	TenureMonths int32   `json:"tenure_months"`
+	EMI          float64 `json:"emi"`            // worked out from the other figures when left out
+	StartDate    string  `json:"start_date"`     // YYYY-MM-DD, when the loan was disbursed
+	FirstEMIDate string  `json:"first_emi_date"` // YYYY-MM-DD, defaults to a month after start_date
+	Notes        string  `json:"notes"`
+}
+
+type CreateLoanPaymentRequest struct {
+	Date       string  `json:"date"` // YYYY-MM-DD
+	Amount     float64 `json:"amount"`
+	Prepayment bool    `json:"prepayment"` // paid on top of the EMIs
+	Notes      string  `json:"notes"`
+}
+
+type LoanResponse struct {
+	ID            int32       `json:"id"`
+	VehicleID     int32       `json:"vehicle_id"`
+	Lender        string      `json:"lender"`
+	Principal     float64     `json:"principal"`
+	AnnualRate    float64     `json:"annual_rate"`
+	TenureMonths  int32       `json:"tenure_months"`
+	EMI           float64     `json:"emi"`
+	StartDate     string      `json:"start_date"`
+	FirstEMIDate  string      `json:"first_emi_date"`
+	LastEMIDate   string      `json:"last_emi_date"`
+	TotalInterest float64     `json:"total_interest"` // over the whole schedule
+	Notes         string      `json:"notes"`
+	CreatedAt     string      `json:"created_at"`
+	Status        loan.Status `json:"status"`
+}
+
+func mapLoanToResponse(l repository.Loan, ledger loan.Ledger) LoanResponse {
+	principal, _ := strconv.ParseFloat(l.Principal, 64)
+	rate, _ := strconv.ParseFloat(l.AnnualRate, 64)
+	emi, _ := strconv.ParseFloat(l.Emi, 64)
+	resp := LoanResponse{
+		ID:           l.ID,
+		VehicleID:    l.VehicleID,
+		Lender:       l.Lender.String,
+		Principal:    principal,
+		AnnualRate:   rate,
+		TenureMonths: l.TenureMonths,
+		EMI:          emi,
+		StartDate:    l.StartDate.Format("2006-01-02"),
+		FirstEMIDate: l.FirstEmiDate.Format("2006-01-02"),
+		Notes:        l.Notes.String,
+		CreatedAt:    l.CreatedAt.Time.Format(time.RFC3339),
+		Status:       ledger.Status,
+	}
+	for _, i := range ledger.Schedule {
+		resp.TotalInterest += i.Interest
+		resp.LastEMIDate = i.DueDate
+	}
+	resp.TotalInterest = round2(resp.TotalInterest)
+	return resp
+}
+
+// validate checks a request, filling in the EMI and first EMI date when left out, and
+// returns its parsed dates or an error message.
+func (req *CreateLoanRequest) validate() (start, firstEMI time.Time, msg string) {
+	if req.Principal <= 0 {
+		return start, firstEMI, "Principal must be positive"
+	}
+	if req.AnnualRate < 0 || req.AnnualRate > 100 {
+		return start, firstEMI, "annual_rate must be a percentage between 0 and 100"
+	}
+	if req.TenureMonths <= 0 || req.TenureMonths > maxTenureMonths {
+		return start, firstEMI, "tenure_months must be between 1 and 600"
+	}
+	if req.EMI < 0 {
+		return start, firstEMI, "EMI must not be negative"
+	}
+	if req.EMI == 0 {
+		req.EMI = loan.EMI(req.Principal, req.AnnualRate, int(req.TenureMonths))
+	} else if req.EMI <= req.Principal*req.AnnualRate/1200 {
+		return start, firstEMI, "EMI does not cover the first month's interest"
+	}
+
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return start, firstEMI, "Invalid start_date, use YYYY-MM-DD"
+	}
+	firstEMI = expense.Occurrence(start, expense.RecurrenceMonthly, 1)
+	if req.FirstEMIDate != "" {
+		if firstEMI, err = time.Parse("2006-01-02", req.FirstEMIDate); err != nil {
+			return start, firstEMI, "Invalid first_emi_date, use YYYY-MM-DD"
+		}
+		if firstEMI.Before(start) {
+			return start, firstEMI, "first_emi_date must not be before start_date"
+		}
+	}
+	return start, firstEMI, ""
+}
+
+// loanLedger builds a loan's schedule and applies its payments as of today.
+func (h *Handler) loanLedger(ctx context.Context, l repository.Loan) (loan.Ledger, error) {
+	payments, err := h.queries.ListLoanPayments(ctx, l.ID)
+	if err != nil {
+		return loan.Ledger{}, err
+	}
+	return loan.ForLoan(l, payments, forecast.Today()), nil
+}
+
+func (h *Handler) ListLoans(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	loans, err := h.queries.ListLoansByVehicle(c.Context(), int32(vehicleId))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch loans", "details": err.Error()})
+	}
+
+	response := make([]LoanResponse, len(loans))
+	for i, l := range loans {
+		ledger, err := h.loanLedger(c.Context(), l)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch loan payments", "details": err.Error()})
+		}
+		response[i] = mapLoanToResponse(l, ledger)
+	}
+	return c.JSON(fiber.Map{"data": response})
+}
+
+// GetLoan returns a loan with its outstanding balance, interest and principal paid so
+// far, and the next EMI due.
+func (h *Handler) GetLoan(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid loan ID"})
+	}
+
+	l, err := h.queries.GetLoan(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Loan not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	ledger, err := h.loanLedger(c.Context(), l)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch loan payments", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": mapLoanToResponse(l, ledger)})
+}
+
+// GetLoanSchedule returns a loan's amortization schedule, with the instalments covered
+// by the EMIs paid so far marked as paid.
+func (h *Handler) GetLoanSchedule(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid loan ID"})
+	}
+
+	l, err := h.queries.GetLoan(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Loan not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	ledger, err := h.loanLedger(c.Context(), l)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch loan payments", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": ledger.Schedule})
+}
+
+func (h *Handler) CreateLoan(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	var req CreateLoanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	start, firstEMI, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	if _, err := h.queries.GetVehicle(c.Context(), int32(vehicleId)); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	l, err := h.queries.CreateLoan(c.Context(), repository.CreateLoanParams{
+		VehicleID:    int32(vehicleId),
+		Lender:       sql.NullString{String: req.Lender, Valid: req.Lender != ""},
+		Principal:    strconv.FormatFloat(req.Principal, 'f', 2, 64),
+		AnnualRate:   strconv.FormatFloat(req.AnnualRate, 'f', 3, 64),
+		TenureMonths: req.TenureMonths,
+		Emi:          strconv.FormatFloat(req.EMI, 'f', 2, 64),
+		StartDate:    start,
+		FirstEmiDate: firstEMI,
+		Notes:        sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create loan", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapLoanToResponse(l, loan.ForLoan(l, nil, forecast.Today()))})
+}
+
+func (h *Handler) UpdateLoan(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid loan ID"})
+	}
+
+	var req CreateLoanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	start, firstEMI, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	l, err := h.queries.UpdateLoan(c.Context(), repository.UpdateLoanParams{
+		ID:           int32(id),
+		Lender:       sql.NullString{String: req.Lender, Valid: req.Lender != ""},
+		Principal:    strconv.FormatFloat(req.Principal, 'f', 2, 64),
+		AnnualRate:   strconv.FormatFloat(req.AnnualRate, 'f', 3, 64),
+		TenureMonths: req.TenureMonths,
+		Emi:          strconv.FormatFloat(req.EMI, 'f', 2, 64),
+		StartDate:    start,
+		FirstEmiDate: firstEMI,
+		Notes:        sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Loan not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update loan", "details": err.Error()})
+	}
+
+	ledger, err := h.loanLedger(c.Context(), l)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch loan payments", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": mapLoanToResponse(l, ledger)})
+}
+
+func (h *Handler) DeleteLoan(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid loan ID"})
+	}
+
+	if err := h.queries.DeleteLoan(c.Context(), int32(id)); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete loan"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
+
+// ListLoanPayments returns the payments made on a loan, oldest first, each split into
+// the interest and principal it paid.
+func (h *Handler) ListLoanPayments(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid loan ID"})
+	}
+
+	l, err := h.queries.GetLoan(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Loan not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	ledger, err := h.loanLedger(c.Context(), l)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch loan payments", "details": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": ledger.Payments})
+}
+
+// CreateLoanPayment records an EMI or prepayment made on a loan.
+func (h *Handler) CreateLoanPayment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid loan ID"})
+	}
+
+	var req CreateLoanPaymentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid date format, use YYYY-MM-DD"})
+	}
+	if req.Amount <= 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Amount must be positive"})
+	}
+
+	l, err := h.queries.GetLoan(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Loan not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	payment, err := h.queries.CreateLoanPayment(c.Context(), repository.CreateLoanPaymentParams{
+		LoanID:     l.ID,
+		Date:       date,
+		Amount:     strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		Prepayment: req.Prepayment,
+		Notes:      sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to record payment", "details": err.Error()})
+	}
+
+	ledger, err := h.loanLedger(c.Context(), l)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch loan payments", "details": err.Error()})
+	}
+	for _, p := range ledger.Payments {
+		if p.ID == payment.ID {
+			return c.Status(201).JSON(fiber.Map{"data": p, "status": ledger.Status})
+		}
+	}
+	return c.Status(201).JSON(fiber.Map{"data": payment, "status": ledger.Status})
+}
+
+func (h *Handler) DeleteLoanPayment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("paymentId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid payment ID"})
+	}
+
+	if err := h.queries.DeleteLoanPayment(c.Context(), int32(id)); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete payment"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
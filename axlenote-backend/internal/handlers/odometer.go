@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateOdometerReadingRequest struct {
+	Odometer   int32  `json:"odometer"`
+	RecordedAt string `json:"recorded_at"` // RFC3339, defaults to now
+}
+
+type OdometerReadingResponse struct {
+	ID         int32  `json:"id"`
+	VehicleID  int32  `json:"vehicle_id"`
+	Odometer   int32  `json:"odometer"`
+	RecordedAt string `json:"recorded_at"`
+	Source     string `json:"source"`
+}
+
+func mapOdometerReadingToResponse(r repository.OdometerReading) OdometerReadingResponse {
+	return OdometerReadingResponse{
+		ID:         r.ID,
+		VehicleID:  r.VehicleID,
+		Odometer:   r.Odometer,
+		RecordedAt: r.RecordedAt.Format(time.RFC3339),
+		Source:     r.Source,
+	}
+}
+
+// CreateOdometerReading logs a reading without a fill-up or service, e.g. from a trip computer.
+func (h *Handler) CreateOdometerReading(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	var req CreateOdometerReadingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.Odometer <= 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Odometer must be positive"})
+	}
+
+	recordedAt := time.Now()
+	if req.RecordedAt != "" {
+		recordedAt, err = time.Parse(time.RFC3339, req.RecordedAt)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid recorded_at, use RFC 3339"})
+		}
+	}
+
+	reading, err := h.queries.CreateOdometerReading(c.Context(), repository.CreateOdometerReadingParams{
+		VehicleID:  int32(vehicleId),
+		Odometer:   req.Odometer,
+		RecordedAt: recordedAt,
+		Source:     "api",
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save odometer reading", "details": err.Error()})
+	}
+
+	response := mapOdometerReadingToResponse(reading)
+	h.emit(c.Context(), webhook.OdometerReadingCreated, response)
+
+	return c.Status(201).JSON(fiber.Map{"data": response})
+}
+
+var odometerReadingSorts = sortKeys[repository.OdometerReading]{
+	"recorded_at": func(r repository.OdometerReading) float64 { return timestampKey(r.RecordedAt) },
+	"odometer":    func(r repository.OdometerReading) float64 { return float64(r.Odometer) },
+}
+
+// listOdometerReadings runs the reading query for sort, like listFuelLogs.
+func (h *Handler) listOdometerReadings(ctx context.Context, sort string, p repository.ListOdometerReadingsByRecordedAtAscParams) ([]repository.OdometerReading, error) {
+	switch sort {
+	case "recorded_at":
+		return h.queries.ListOdometerReadingsByRecordedAtAsc(ctx, p)
+	case "-recorded_at":
+		return h.queries.ListOdometerReadingsByRecordedAtDesc(ctx, repository.ListOdometerReadingsByRecordedAtDescParams(p))
+	case "odometer":
+		return h.queries.ListOdometerReadingsByOdometerAsc(ctx, repository.ListOdometerReadingsByOdometerAscParams(p))
+	case "-odometer":
+		return h.queries.ListOdometerReadingsByOdometerDesc(ctx, repository.ListOdometerReadingsByOdometerDescParams(p))
+	}
+	return nil, fmt.Errorf("no odometer reading query for sort %q", sort)
+}
+
+// ListOdometerReadings pages through a vehicle's standalone readings, newest first by
+// default. Besides the common list parameters (see parseListOptions) it accepts
+// ?source=api|mqtt.
+func (h *Handler) ListOdometerReadings(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	opts, msg := parseListOptions(c, odometerReadingSorts, "-recorded_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	filters := repository.CountOdometerReadingsByVehicleParams{
+		VehicleID:   int32(vehicleId),
+		Source:      queryNullString(c, "source"),
+		DateFrom:    opts.dateFrom,
+		DateTo:      opts.dateTo,
+		OdometerMin: opts.odometerMin,
+		OdometerMax: opts.odometerMax,
+	}
+	readings, err := h.listOdometerReadings(c.Context(), opts.sortParam(), repository.ListOdometerReadingsByRecordedAtAscParams{
+		VehicleID:   filters.VehicleID,
+		Source:      filters.Source,
+		DateFrom:    filters.DateFrom,
+		DateTo:      filters.DateTo,
+		OdometerMin: filters.OdometerMin,
+		OdometerMax: filters.OdometerMax,
+		AfterKey:    opts.afterKey(),
+		AfterID:     opts.afterID(),
+		Limit:       opts.fetchLimit(),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch odometer readings"})
+	}
+	total, err := h.queries.CountOdometerReadingsByVehicle(c.Context(), filters)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch odometer readings"})
+	}
+	readings, meta := pageOf(readings, opts, total, odometerReadingSorts, func(r repository.OdometerReading) int32 { return r.ID })
+
+	response := make([]OdometerReadingResponse, len(readings))
+	for i, r := range readings {
+		response[i] = mapOdometerReadingToResponse(r)
+	}
+
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/axlenote/axlenote-backend/internal/forecast"
 	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -18,6 +22,7 @@ type CreateReminderRequest struct {
 	IntervalKm     int32  `json:"interval_km"`
 	IntervalMonths int32  `json:"interval_months"`
 	Notes          string `json:"notes"`
+	Type           string `json:"type"` // Service, Insurance, Tax, Other
 }
 
 type ReminderResponse struct {
@@ -31,6 +36,10 @@ type ReminderResponse struct {
 	IntervalMonths int32  `json:"interval_months"`
 	Notes          string `json:"notes"`
 	IsCompleted    bool   `json:"is_completed"`
+	CompletedAt    string `json:"completed_at,omitempty"` // RFC 3339
+	Type           string `json:"type"`
+	// ProjectedDueDate estimates when an odometer reminder falls due at the vehicle's recent driving rate
+	ProjectedDueDate string `json:"projected_due_date,omitempty"`
 }
 
 func mapReminderToResponse(r repository.Reminder) ReminderResponse {
@@ -39,7 +48,7 @@ func mapReminderToResponse(r repository.Reminder) ReminderResponse {
 		dateStr = r.DueDate.Time.Format("2006-01-02")
 	}
 
-	return ReminderResponse{
+	resp := ReminderResponse{
 		ID:             r.ID,
 		VehicleID:      r.VehicleID.Int32,
 		Title:          r.Title,
@@ -50,6 +59,27 @@ func mapReminderToResponse(r repository.Reminder) ReminderResponse {
 		IntervalMonths: r.IntervalMonths.Int32,
 		Notes:          r.Notes.String,
 		IsCompleted:    r.IsCompleted.Bool,
+		Type:           r.Type.String,
+	}
+	if r.CompletedAt.Valid {
+		resp.CompletedAt = r.CompletedAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
+
+// projectDueDates fills in ProjectedDueDate for open odometer reminders of a vehicle.
+// Reminders are returned without a projection when there is too little history.
+func (h *Handler) projectDueDates(ctx context.Context, vehicleID int32, reminders []ReminderResponse) {
+	rate, ok, err := forecast.ForVehicle(ctx, h.queries, vehicleID, time.Now())
+	if err != nil || !ok {
+		return
+	}
+	today := forecast.Today()
+	for i, r := range reminders {
+		if r.IsCompleted || r.DueOdometer <= 0 {
+			continue
+		}
+		reminders[i].ProjectedDueDate = rate.DueDate(r.DueOdometer, today).Format("2006-01-02")
 	}
 }
 
@@ -76,32 +106,156 @@ func (h *Handler) CreateReminder(c *fiber.Ctx) error {
 		IntervalKm:     sql.NullInt32{Int32: req.IntervalKm, Valid: req.IntervalKm > 0},
 		IntervalMonths: sql.NullInt32{Int32: req.IntervalMonths, Valid: req.IntervalMonths > 0},
 		Notes:          sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		Type:           sql.NullString{String: req.Type, Valid: req.Type != ""},
 	})
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create reminder", "details": err.Error()})
 	}
 
-	return c.Status(201).JSON(fiber.Map{"data": mapReminderToResponse(reminder)})
+	response := []ReminderResponse{mapReminderToResponse(reminder)}
+	h.projectDueDates(c.Context(), req.VehicleID, response)
+	h.emit(c.Context(), webhook.ReminderCreated, response[0])
+
+	return c.Status(201).JSON(fiber.Map{"data": response[0]})
+}
+
+func (h *Handler) UpdateReminder(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid reminder ID"})
+	}
+
+	var req CreateReminderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	var dueDate sql.NullTime
+	if req.DueDate != "" {
+		parsedDate, err := time.Parse("2006-01-02", req.DueDate)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid date format, use YYYY-MM-DD"})
+		}
+		dueDate = sql.NullTime{Time: parsedDate, Valid: true}
+	}
+
+	reminder, err := h.queries.UpdateReminder(c.Context(), repository.UpdateReminderParams{
+		ID:             int32(id),
+		Title:          req.Title,
+		DueDate:        dueDate,
+		DueOdometer:    sql.NullInt32{Int32: req.DueOdometer, Valid: req.DueOdometer > 0},
+		IsRecurring:    sql.NullBool{Bool: req.IsRecurring, Valid: true},
+		IntervalKm:     sql.NullInt32{Int32: req.IntervalKm, Valid: req.IntervalKm > 0},
+		IntervalMonths: sql.NullInt32{Int32: req.IntervalMonths, Valid: req.IntervalMonths > 0},
+		Notes:          sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		Type:           sql.NullString{String: req.Type, Valid: req.Type != ""},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Reminder not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update reminder", "details": err.Error()})
+	}
+
+	response := []ReminderResponse{mapReminderToResponse(reminder)}
+	h.projectDueDates(c.Context(), reminder.VehicleID.Int32, response)
+	h.emit(c.Context(), webhook.ReminderUpdated, response[0])
+
+	return c.JSON(fiber.Map{"data": response[0]})
+}
+
+var reminderSorts = sortKeys[repository.Reminder]{
+	"due_date": func(r repository.Reminder) float64 { return nullDateKey(r.DueDate) },
+	"due_odometer": func(r repository.Reminder) float64 {
+		if !r.DueOdometer.Valid {
+			return missingSortKey
+		}
+		return float64(r.DueOdometer.Int32)
+	},
+	"created_at": func(r repository.Reminder) float64 { return nullTimestampKey(r.CreatedAt) },
 }
 
+// listReminders runs the reminder query for sort, like listFuelLogs.
+func (h *Handler) listReminders(ctx context.Context, sort string, p repository.ListRemindersByDueDateAscParams) ([]repository.Reminder, error) {
+	switch sort {
+	case "due_date":
+		return h.queries.ListRemindersByDueDateAsc(ctx, p)
+	case "-due_date":
+		return h.queries.ListRemindersByDueDateDesc(ctx, repository.ListRemindersByDueDateDescParams(p))
+	case "due_odometer":
+		return h.queries.ListRemindersByDueOdometerAsc(ctx, repository.ListRemindersByDueOdometerAscParams(p))
+	case "-due_odometer":
+		return h.queries.ListRemindersByDueOdometerDesc(ctx, repository.ListRemindersByDueOdometerDescParams(p))
+	case "created_at":
+		return h.queries.ListRemindersByCreatedAtAsc(ctx, repository.ListRemindersByCreatedAtAscParams(p))
+	case "-created_at":
+		return h.queries.ListRemindersByCreatedAtDesc(ctx, repository.ListRemindersByCreatedAtDescParams(p))
+	}
+	return nil, fmt.Errorf("no reminder query for sort %q", sort)
+}
+
+// ListReminders pages through a vehicle's reminders, soonest due first by default. Open
+// reminders are listed unless ?status=completed or ?status=all; ?type= filters by type.
+// The date and odometer ranges apply to the due date and due odometer.
 func (h *Handler) ListReminders(c *fiber.Ctx) error {
 	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
 	}
 
-	reminders, err := h.queries.ListRemindersByVehicle(c.Context(), sql.NullInt32{Int32: int32(vehicleId), Valid: true})
+	opts, msg := parseListOptions(c, reminderSorts, "due_date")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	var completed sql.NullBool
+	switch c.Query("status", "open") {
+	case "open":
+		completed = sql.NullBool{Bool: false, Valid: true}
+	case "completed":
+		completed = sql.NullBool{Bool: true, Valid: true}
+	case "all":
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid status, use open, completed or all"})
+	}
+
+	filters := repository.CountRemindersPageParams{
+		VehicleID:   int32(vehicleId),
+		IsCompleted: completed,
+		Type:        queryNullString(c, "type"),
+		DateFrom:    opts.dateFrom,
+		DateTo:      opts.dateTo,
+		OdometerMin: opts.odometerMin,
+		OdometerMax: opts.odometerMax,
+	}
+	reminders, err := h.listReminders(c.Context(), opts.sortParam(), repository.ListRemindersByDueDateAscParams{
+		VehicleID:   filters.VehicleID,
+		IsCompleted: filters.IsCompleted,
+		Type:        filters.Type,
+		DateFrom:    filters.DateFrom,
+		DateTo:      filters.DateTo,
+		OdometerMin: filters.OdometerMin,
+		OdometerMax: filters.OdometerMax,
+		AfterKey:    opts.afterKey(),
+		AfterID:     opts.afterID(),
+		Limit:       opts.fetchLimit(),
+	})
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch reminders"})
 	}
+	total, err := h.queries.CountRemindersPage(c.Context(), filters)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch reminders"})
+	}
+	reminders, meta := pageOf(reminders, opts, total, reminderSorts, func(r repository.Reminder) int32 { return r.ID })
 
 	response := make([]ReminderResponse, len(reminders))
 	for i, r := range reminders {
 		response[i] = mapReminderToResponse(r)
 	}
+	h.projectDueDates(c.Context(), int32(vehicleId), response)
 
-	return c.JSON(fiber.Map{"data": response})
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
 }
 
 func (h *Handler) CompleteReminder(c *fiber.Ctx) error {
@@ -115,5 +269,25 @@ func (h *Handler) CompleteReminder(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to complete reminder"})
 	}
 
+	if reminder, err := h.queries.GetReminder(c.Context(), int32(id)); err == nil {
+		h.emit(c.Context(), webhook.ReminderCompleted, mapReminderToResponse(reminder))
+	}
+
 	return c.JSON(fiber.Map{"message": "Reminder completed"})
 }
+
+func (h *Handler) DeleteReminder(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid reminder ID"})
+	}
+
+	err = h.queries.DeleteReminder(c.Context(), int32(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete reminder"})
+	}
+
+	h.emit(c.Context(), webhook.ReminderDeleted, fiber.Map{"id": id})
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
@@ -1,46 +1,71 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
 	"github.com/gofiber/fiber/v2"
 )
 
 type CreateServiceRequest struct {
-	VehicleId   int32   `json:"vehicle_id"`
-	Date        string  `json:"date"` // YYYY-MM-DD
-	Odometer    int32   `json:"odometer"`
-	Cost        float64 `json:"cost"`
-	Notes       string  `json:"notes"`
-	ServiceType string  `json:"service_type"`
-	DocumentUrl string  `json:"document_url"`
+	VehicleId     int32   `json:"vehicle_id"`
+	Date          string  `json:"date"` // YYYY-MM-DD
+	Odometer      int32   `json:"odometer"`
+	Cost          float64 `json:"cost"`
+	Notes         string  `json:"notes"`
+	ServiceType   string  `json:"service_type"`
+	DocumentUrl   string  `json:"document_url"`
+	CompletedDate string  `json:"completed_date"` // YYYY-MM-DD, when the vehicle came back; optional
+}
+
+// parseDates validates the service date and the optional completion date.
+func (req *CreateServiceRequest) parseDates() (time.Time, sql.NullTime, string) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return date, sql.NullTime{}, "Invalid date format, use YYYY-MM-DD"
+	}
+	if req.CompletedDate == "" {
+		return date, sql.NullTime{}, ""
+	}
+	completed, err := time.Parse("2006-01-02", req.CompletedDate)
+	if err != nil {
+		return date, sql.NullTime{}, "Invalid completed_date, use YYYY-MM-DD"
+	}
+	if completed.Before(date) {
+		return date, sql.NullTime{}, "completed_date cannot be before date"
+	}
+	return date, sql.NullTime{Time: completed, Valid: true}, ""
 }
 
 type ServiceRecordResponse struct {
-	ID          int32   `json:"id"`
-	VehicleID   int32   `json:"vehicle_id"`
-	Date        string  `json:"date"`
-	Odometer    int32   `json:"odometer"`
-	Cost        float64 `json:"cost"`
-	Notes       string  `json:"notes"`
-	ServiceType string  `json:"service_type"`
-	DocumentUrl string  `json:"document_url"`
+	ID            int32   `json:"id"`
+	VehicleID     int32   `json:"vehicle_id"`
+	Date          string  `json:"date"`
+	CompletedDate string  `json:"completed_date"`
+	Odometer      int32   `json:"odometer"`
+	Cost          float64 `json:"cost"`
+	Notes         string  `json:"notes"`
+	ServiceType   string  `json:"service_type"`
+	DocumentUrl   string  `json:"document_url"`
 }
 
 func mapServiceToResponse(s repository.ServiceRecord) ServiceRecordResponse {
 	cost, _ := strconv.ParseFloat(s.Cost, 64)
 	return ServiceRecordResponse{
-		ID:          s.ID,
-		VehicleID:   s.VehicleID.Int32,
-		Date:        s.Date.Format("2006-01-02"),
-		Odometer:    s.Odometer,
-		Cost:        cost,
-		Notes:       s.Notes.String,
-		ServiceType: s.ServiceType.String,
-		DocumentUrl: s.DocumentUrl.String, // Now available from query gen
+		ID:            s.ID,
+		VehicleID:     s.VehicleID.Int32,
+		Date:          s.Date.Format("2006-01-02"),
+		CompletedDate: formatDate(s.CompletedDate),
+		Odometer:      s.Odometer,
+		Cost:          cost,
+		Notes:         s.Notes.String,
+		ServiceType:   s.ServiceType.String,
+		DocumentUrl:   s.DocumentUrl.String, // Now available from query gen
 	}
 }
 
@@ -50,26 +75,30 @@ func (h *Handler) CreateServiceRecord(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	parsedDate, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid date format, use YYYY-MM-DD"})
+	parsedDate, completedDate, msg := req.parseDates()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
 	}
 
 	record, err := h.queries.CreateServiceRecord(c.Context(), repository.CreateServiceRecordParams{
-		VehicleID:   sql.NullInt32{Int32: req.VehicleId, Valid: true},
-		Date:        parsedDate,
-		Odometer:    req.Odometer,
-		Cost:        stringToNumeric(req.Cost),
-		Notes:       sql.NullString{String: req.Notes, Valid: req.Notes != ""},
-		ServiceType: sql.NullString{String: req.ServiceType, Valid: req.ServiceType != ""},
-		DocumentUrl: sql.NullString{String: req.DocumentUrl, Valid: req.DocumentUrl != ""},
+		VehicleID:     sql.NullInt32{Int32: req.VehicleId, Valid: true},
+		Date:          parsedDate,
+		Odometer:      req.Odometer,
+		Cost:          stringToNumeric(req.Cost),
+		Notes:         sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		ServiceType:   sql.NullString{String: req.ServiceType, Valid: req.ServiceType != ""},
+		DocumentUrl:   sql.NullString{String: req.DocumentUrl, Valid: req.DocumentUrl != ""},
+		CompletedDate: completedDate,
 	})
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create service record", "details": err.Error()})
 	}
 
-	return c.Status(201).JSON(fiber.Map{"data": mapServiceToResponse(record)})
+	response := mapServiceToResponse(record)
+	h.emit(c.Context(), webhook.ServiceRecordCreated, response)
+
+	return c.Status(201).JSON(fiber.Map{"data": response})
 }
 
 // Helper for decimal
@@ -77,23 +106,82 @@ func stringToNumeric(val float64) string {
 	return strconv.FormatFloat(val, 'f', 2, 64)
 }
 
+var serviceRecordSorts = sortKeys[repository.ServiceRecord]{
+	"date":     func(r repository.ServiceRecord) float64 { return dateKey(r.Date) },
+	"odometer": func(r repository.ServiceRecord) float64 { return float64(r.Odometer) },
+	"cost":     func(r repository.ServiceRecord) float64 { return numericKey(r.Cost) },
+}
+
+// listServiceRecords runs the service record query for sort, like listFuelLogs.
+func (h *Handler) listServiceRecords(ctx context.Context, sort string, p repository.ListServiceRecordsByDateAscParams) ([]repository.ServiceRecord, error) {
+	switch sort {
+	case "date":
+		return h.queries.ListServiceRecordsByDateAsc(ctx, p)
+	case "-date":
+		return h.queries.ListServiceRecordsByDateDesc(ctx, repository.ListServiceRecordsByDateDescParams(p))
+	case "odometer":
+		return h.queries.ListServiceRecordsByOdometerAsc(ctx, repository.ListServiceRecordsByOdometerAscParams(p))
+	case "-odometer":
+		return h.queries.ListServiceRecordsByOdometerDesc(ctx, repository.ListServiceRecordsByOdometerDescParams(p))
+	case "cost":
+		return h.queries.ListServiceRecordsByCostAsc(ctx, repository.ListServiceRecordsByCostAscParams(p))
+	case "-cost":
+		return h.queries.ListServiceRecordsByCostDesc(ctx, repository.ListServiceRecordsByCostDescParams(p))
+	}
+	return nil, fmt.Errorf("no service record query for sort %q", sort)
+}
+
+// ListServiceRecords pages through a vehicle's service records, newest first by default.
+// Besides the common list parameters (see parseListOptions) it accepts ?service_type=.
 func (h *Handler) ListServiceRecords(c *fiber.Ctx) error {
 	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
 	}
 
-	records, err := h.queries.ListServiceRecordsByVehicle(c.Context(), sql.NullInt32{Int32: int32(vehicleId), Valid: true})
+	opts, msg := parseListOptions(c, serviceRecordSorts, "-date")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	filters := repository.CountServiceRecordsByVehicleParams{
+		VehicleID:   int32(vehicleId),
+		DateFrom:    opts.dateFrom,
+		DateTo:      opts.dateTo,
+		OdometerMin: opts.odometerMin,
+		OdometerMax: opts.odometerMax,
+		CostMin:     opts.costMin,
+		CostMax:     opts.costMax,
+		ServiceType: queryNullString(c, "service_type"),
+	}
+	records, err := h.listServiceRecords(c.Context(), opts.sortParam(), repository.ListServiceRecordsByDateAscParams{
+		VehicleID:   filters.VehicleID,
+		DateFrom:    filters.DateFrom,
+		DateTo:      filters.DateTo,
+		OdometerMin: filters.OdometerMin,
+		OdometerMax: filters.OdometerMax,
+		CostMin:     filters.CostMin,
+		CostMax:     filters.CostMax,
+		ServiceType: filters.ServiceType,
+		AfterKey:    opts.afterKey(),
+		AfterID:     opts.afterID(),
+		Limit:       opts.fetchLimit(),
+	})
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch records"})
 	}
+	total, err := h.queries.CountServiceRecordsByVehicle(c.Context(), filters)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch records"})
+	}
+	records, meta := pageOf(records, opts, total, serviceRecordSorts, func(r repository.ServiceRecord) int32 { return r.ID })
 
 	response := make([]ServiceRecordResponse, len(records))
 	for i, r := range records {
 		response[i] = mapServiceToResponse(r)
 	}
 
-	return c.JSON(fiber.Map{"data": response})
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
 }
 
 func (h *Handler) UpdateServiceRecord(c *fiber.Ctx) error {
@@ -107,26 +195,30 @@ func (h *Handler) UpdateServiceRecord(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	parsedDate, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid date format, use YYYY-MM-DD"})
+	parsedDate, completedDate, msg := req.parseDates()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
 	}
 
 	record, err := h.queries.UpdateServiceRecord(c.Context(), repository.UpdateServiceRecordParams{
-		ID:          int32(id),
-		Date:        parsedDate,
-		Odometer:    req.Odometer,
-		Cost:        stringToNumeric(req.Cost),
-		Notes:       sql.NullString{String: req.Notes, Valid: req.Notes != ""},
-		ServiceType: sql.NullString{String: req.ServiceType, Valid: req.ServiceType != ""},
-		DocumentUrl: sql.NullString{String: req.DocumentUrl, Valid: req.DocumentUrl != ""},
+		ID:            int32(id),
+		Date:          parsedDate,
+		Odometer:      req.Odometer,
+		Cost:          stringToNumeric(req.Cost),
+		Notes:         sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+		ServiceType:   sql.NullString{String: req.ServiceType, Valid: req.ServiceType != ""},
+		DocumentUrl:   sql.NullString{String: req.DocumentUrl, Valid: req.DocumentUrl != ""},
+		CompletedDate: completedDate,
 	})
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to update service record"})
 	}
 
-	return c.JSON(fiber.Map{"data": mapServiceToResponse(record)})
+	response := mapServiceToResponse(record)
+	h.emit(c.Context(), webhook.ServiceRecordUpdated, response)
+
+	return c.JSON(fiber.Map{"data": response})
 }
 
 func (h *Handler) DeleteServiceRecord(c *fiber.Ctx) error {
@@ -140,5 +232,7 @@ func (h *Handler) DeleteServiceRecord(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete service record"})
 	}
 
+	h.emit(c.Context(), webhook.ServiceRecordDeleted, fiber.Map{"id": id})
+
 	return c.JSON(fiber.Map{"message": "Deleted successfully"})
 }
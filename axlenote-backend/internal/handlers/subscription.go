@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/axlenote/axlenote-backend/internal/notification"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateSubscriptionRequest struct {
+	UserID        int32    `json:"user_id"`
+	VehicleID     int32    `json:"vehicle_id"`     // 0 = all vehicles
+	Channel       string   `json:"channel"`        // ntfy, email
+	ReminderTypes []string `json:"reminder_types"` // empty = all types
+	Triggers      []string `json:"triggers"`       // overdue, upcoming; empty = all
+	LeadDays      int32    `json:"lead_days"`
+	Digest        string   `json:"digest"` // none, daily, weekly
+	Enabled       *bool    `json:"enabled"`
+}
+
+type SubscriptionResponse struct {
+	ID            int32    `json:"id"`
+	UserID        int32    `json:"user_id"`
+	VehicleID     int32    `json:"vehicle_id"`
+	Channel       string   `json:"channel"`
+	ReminderTypes []string `json:"reminder_types"`
+	Triggers      []string `json:"triggers"`
+	LeadDays      int32    `json:"lead_days"`
+	Digest        string   `json:"digest"`
+	Enabled       bool     `json:"enabled"`
+}
+
+func mapSubscriptionToResponse(s repository.NotificationSubscription) SubscriptionResponse {
+	return SubscriptionResponse{
+		ID:            s.ID,
+		UserID:        s.UserID,
+		VehicleID:     s.VehicleID.Int32,
+		Channel:       s.Channel,
+		ReminderTypes: nonNilStrings(s.ReminderTypes),
+		Triggers:      nonNilStrings(s.Triggers),
+		LeadDays:      s.LeadDays,
+		Digest:        s.Digest,
+		Enabled:       s.Enabled,
+	}
+}
+
+// normalize fills in defaults and returns a validation error message, if any.
+func (req *CreateSubscriptionRequest) normalize() string {
+	if req.Channel == "" {
+		req.Channel = notification.ChannelNtfy
+	}
+	if !notification.ValidChannel(req.Channel) {
+		return "Invalid channel, use ntfy or email"
+	}
+	if req.Digest == "" {
+		req.Digest = notification.DigestNone
+	}
+	if !notification.ValidDigest(req.Digest) {
+		return "Invalid digest, use none, daily or weekly"
+	}
+	for _, t := range req.Triggers {
+		if !notification.ValidTrigger(t) {
+			return "Invalid trigger, use overdue or upcoming"
+		}
+	}
+	if req.LeadDays < 0 {
+		return "Lead days cannot be negative"
+	}
+	if req.LeadDays == 0 {
+		req.LeadDays = 7
+	}
+	req.ReminderTypes = nonNilStrings(req.ReminderTypes)
+	req.Triggers = nonNilStrings(req.Triggers)
+	return ""
+}
+
+func (req *CreateSubscriptionRequest) enabled() bool {
+	return req.Enabled == nil || *req.Enabled
+}
+
+func (h *Handler) CreateSubscription(c *fiber.Ctx) error {
+	var req CreateSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if req.UserID == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "User ID is required"})
+	}
+	if msg := req.normalize(); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	sub, err := h.queries.CreateSubscription(c.Context(), repository.CreateSubscriptionParams{
+		UserID:        req.UserID,
+		VehicleID:     sql.NullInt32{Int32: req.VehicleID, Valid: req.VehicleID > 0},
+		Channel:       req.Channel,
+		ReminderTypes: req.ReminderTypes,
+		Triggers:      req.Triggers,
+		LeadDays:      req.LeadDays,
+		Digest:        req.Digest,
+		Enabled:       req.enabled(),
+	})
+
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create subscription", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapSubscriptionToResponse(sub)})
+}
+
+var subscriptionSorts = sortKeys[repository.NotificationSubscription]{
+	"created_at": func(s repository.NotificationSubscription) float64 { return nullTimestampKey(s.CreatedAt) },
+}
+
+func (h *Handler) ListSubscriptions(c *fiber.Ctx) error {
+	userId, err := strconv.Atoi(c.Params("userId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	opts, msg := parseListOptions(c, subscriptionSorts, "created_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	subs, err := h.queries.ListSubscriptionsByUser(c.Context(), int32(userId))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch subscriptions"})
+	}
+	subs, meta := paginate(subs, opts, subscriptionSorts, func(s repository.NotificationSubscription) int32 { return s.ID })
+
+	response := make([]SubscriptionResponse, len(subs))
+	for i, s := range subs {
+		response[i] = mapSubscriptionToResponse(s)
+	}
+
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+func (h *Handler) UpdateSubscription(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid subscription ID"})
+	}
+
+	var req CreateSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if msg := req.normalize(); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	sub, err := h.queries.UpdateSubscription(c.Context(), repository.UpdateSubscriptionParams{
+		ID:            int32(id),
+		VehicleID:     sql.NullInt32{Int32: req.VehicleID, Valid: req.VehicleID > 0},
+		Channel:       req.Channel,
+		ReminderTypes: req.ReminderTypes,
+		Triggers:      req.Triggers,
+		LeadDays:      req.LeadDays,
+		Digest:        req.Digest,
+		Enabled:       req.enabled(),
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Subscription not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update subscription"})
+	}
+
+	return c.JSON(fiber.Map{"data": mapSubscriptionToResponse(sub)})
+}
+
+func (h *Handler) DeleteSubscription(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid subscription ID"})
+	}
+
+	err = h.queries.DeleteSubscription(c.Context(), int32(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete subscription"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
+
+// nonNilStrings keeps empty lists as [] in JSON and '{}' in Postgres rather than null.
+func nonNilStrings(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SaveTemplateRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type TemplateResponse struct {
+	Kind   string `json:"kind"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Custom bool   `json:"custom"` // false when using the bundled translation
+}
+
+func (h *Handler) GetLocales(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"data": messages.Locales()})
+}
+
+// ListTemplates returns the effective notification templates for a user.
+func (h *Handler) ListTemplates(c *fiber.Ctx) error {
+	userId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	user, err := h.queries.GetUser(c.Context(), int32(userId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	custom, err := h.queries.ListNotificationTemplatesByUser(c.Context(), user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch templates"})
+	}
+
+	response := make([]TemplateResponse, 0, len(messages.Kinds))
+	for _, kind := range messages.Kinds {
+		t := messages.Bundled(user.Locale, kind)
+		item := TemplateResponse{Kind: kind, Title: t.Title, Body: t.Body}
+		for _, ct := range custom {
+			if ct.Kind == kind {
+				item = TemplateResponse{Kind: kind, Title: ct.Title, Body: ct.Body, Custom: true}
+			}
+		}
+		response = append(response, item)
+	}
+
+	return c.JSON(fiber.Map{"data": response, "placeholders": messages.SampleData()})
+}
+
+// SaveTemplate validates and stores a user's template for one message kind.
+func (h *Handler) SaveTemplate(c *fiber.Ctx) error {
+	userId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	kind := c.Params("kind")
+	if !messages.ValidKind(kind) {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid template kind, use one of " + strings.Join(messages.Kinds, ", ")})
+	}
+
+	var req SaveTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	title, body, err := messages.Validate(messages.Template{Title: req.Title, Body: req.Body})
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid template", "details": err.Error()})
+	}
+
+	t, err := h.queries.UpsertNotificationTemplate(c.Context(), repository.UpsertNotificationTemplateParams{
+		UserID: int32(userId),
+		Kind:   kind,
+		Title:  req.Title,
+		Body:   req.Body,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save template", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":    TemplateResponse{Kind: t.Kind, Title: t.Title, Body: t.Body, Custom: true},
+		"preview": fiber.Map{"title": title, "body": body},
+	})
+}
+
+// DeleteTemplate reverts a user's template to the bundled translation.
+func (h *Handler) DeleteTemplate(c *fiber.Ctx) error {
+	userId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	err = h.queries.DeleteNotificationTemplate(c.Context(), repository.DeleteNotificationTemplateParams{
+		UserID: int32(userId),
+		Kind:   c.Params("kind"),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete template"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Template reset to default"})
+}
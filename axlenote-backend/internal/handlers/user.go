@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateUserRequest struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	NtfyTopic string `json:"ntfy_topic"`
+	Locale    string `json:"locale"`
+}
+
+type UserResponse struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	NtfyTopic string `json:"ntfy_topic"`
+	Locale    string `json:"locale"`
+	CreatedAt string `json:"created_at"`
+}
+
+func mapUserToResponse(u repository.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Name:      u.Name,
+		Email:     u.Email.String,
+		NtfyTopic: u.NtfyTopic.String,
+		Locale:    u.Locale,
+		CreatedAt: u.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
+
+func (h *Handler) CreateUser(c *fiber.Ctx) error {
+	var req CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
+	}
+	if req.Locale == "" {
+		req.Locale = messages.DefaultLocale
+	}
+	if !messages.SupportedLocale(req.Locale) {
+		return c.Status(400).JSON(fiber.Map{"error": "Unsupported locale", "locales": messages.Locales()})
+	}
+
+	user, err := h.queries.CreateUser(c.Context(), repository.CreateUserParams{
+		Name:      req.Name,
+		Email:     sql.NullString{String: req.Email, Valid: req.Email != ""},
+		NtfyTopic: sql.NullString{String: req.NtfyTopic, Valid: req.NtfyTopic != ""},
+		Locale:    req.Locale,
+	})
+
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create user", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapUserToResponse(user)})
+}
+
+var userSorts = sortKeys[repository.User]{
+	"created_at": func(u repository.User) float64 { return nullTimestampKey(u.CreatedAt) },
+}
+
+func (h *Handler) GetUsers(c *fiber.Ctx) error {
+	opts, msg := parseListOptions(c, userSorts, "created_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	users, err := h.queries.ListUsers(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch users"})
+	}
+	users, meta := paginate(users, opts, userSorts, func(u repository.User) int32 { return u.ID })
+
+	response := make([]UserResponse, len(users))
+	for i, u := range users {
+		response[i] = mapUserToResponse(u)
+	}
+
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+func (h *Handler) GetUser(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	user, err := h.queries.GetUser(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "User not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	return c.JSON(fiber.Map{"data": mapUserToResponse(user)})
+}
+
+func (h *Handler) UpdateUser(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	var req CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
+	}
+	if req.Locale == "" {
+		req.Locale = messages.DefaultLocale
+	}
+	if !messages.SupportedLocale(req.Locale) {
+		return c.Status(400).JSON(fiber.Map{"error": "Unsupported locale", "locales": messages.Locales()})
+	}
+
+	user, err := h.queries.UpdateUser(c.Context(), repository.UpdateUserParams{
+		ID:        int32(id),
+		Name:      req.Name,
+		Email:     sql.NullString{String: req.Email, Valid: req.Email != ""},
+		NtfyTopic: sql.NullString{String: req.NtfyTopic, Valid: req.NtfyTopic != ""},
+		Locale:    req.Locale,
+	})
+
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update user", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": mapUserToResponse(user)})
+}
+
+func (h *Handler) DeleteUser(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	err = h.queries.DeleteUser(c.Context(), int32(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete user"})
+	}
+
+	return c.JSON(fiber.Map{"message": "User deleted successfully"})
+}
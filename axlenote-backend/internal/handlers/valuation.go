@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/depreciation"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/tco"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateValuationRequest struct {
+	Date   string  `json:"date"` // YYYY-MM-DD
+	Value  float64 `json:"value"`
+	Source string  `json:"source"` // e.g. dealer quote, insurer's declared value
+	Notes  string  `json:"notes"`
+}
+
+type ValuationResponse struct {
+	ID        int32   `json:"id"`
+	VehicleID int32   `json:"vehicle_id"`
+	Date      string  `json:"date"`
+	Value     float64 `json:"value"`
+	Source    string  `json:"source"`
+	Notes     string  `json:"notes"`
+}
+
+func mapValuationToResponse(v repository.VehicleValuation) ValuationResponse {
+	value, _ := strconv.ParseFloat(v.Value, 64)
+	return ValuationResponse{
+		ID:        v.ID,
+		VehicleID: v.VehicleID,
+		Date:      v.Date.Format("2006-01-02"),
+		Value:     value,
+		Source:    v.Source.String,
+		Notes:     v.Notes.String,
+	}
+}
+
+// validate checks a request and returns its parsed date, or an error message.
+func (req CreateValuationRequest) validate() (time.Time, string) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return date, "Invalid date format, use YYYY-MM-DD"
+	}
+	if req.Value < 0 {
+		return date, "Value must not be negative"
+	}
+	return date, ""
+}
+
+type VehicleValueResponse struct {
+	VehicleID           int32                `json:"vehicle_id"`
+	Model               string               `json:"model"`
+	Rate                float64              `json:"rate,omitempty"` // percent per year, for the rate-based models
+	PurchasePrice       float64              `json:"purchase_price"`
+	PurchaseDate        string               `json:"purchase_date,omitempty"`
+	ResidualValue       float64              `json:"residual_value"`
+	Currency            string               `json:"currency"`
+	CurrentValue        float64              `json:"current_value"` // the sale price once sold
+	SaleDate            string               `json:"sale_date,omitempty"`
+	Depreciation        float64              `json:"depreciation"` // lost since the purchase, or the first valuation
+	DepreciationPercent *float64             `json:"depreciation_percent"`
+	History             []depreciation.Point `json:"history"`
+}
+
+// GetVehicleValue estimates what a vehicle is worth today with its depreciation model,
+// and how its value developed month by month up to today or its sale.
+func (h *Handler) GetVehicleValue(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	vehicle, err := h.queries.GetVehicle(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	valuations, err := h.queries.ListValuationsByVehicle(c.Context(), vehicle.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch valuations", "details": err.Error()})
+	}
+	estimate, ok := depreciation.ForVehicle(vehicle, valuations)
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "No value to estimate from, set the vehicle's purchase price and date or record a valuation"})
+	}
+
+	history := estimate.History(forecast.Today())
+	initial, current := history[0].Value, history[len(history)-1].Value
+	response := VehicleValueResponse{
+		VehicleID:     vehicle.ID,
+		Model:         estimate.Model,
+		PurchasePrice: estimate.PurchasePrice,
+		ResidualValue: estimate.Residual,
+		Currency:      tco.Currency(),
+		CurrentValue:  current,
+		Depreciation:  round2(initial - current),
+		History:       history,
+	}
+	if estimate.Model != depreciation.ModelValuations {
+		response.Rate = estimate.Rate
+	}
+	if !estimate.PurchaseDate.IsZero() {
+		response.PurchaseDate = estimate.PurchaseDate.Format("2006-01-02")
+	}
+	if !estimate.SaleDate.IsZero() {
+		response.SaleDate = estimate.SaleDate.Format("2006-01-02")
+	}
+	if initial > 0 {
+		percent := round2((initial - current) / initial * 100)
+		response.DepreciationPercent = &percent
+	}
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *Handler) ListValuations(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	valuations, err := h.queries.ListValuationsByVehicle(c.Context(), int32(vehicleId))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch valuations", "details": err.Error()})
+	}
+
+	response := make([]ValuationResponse, len(valuations))
+	for i, v := range valuations {
+		response[i] = mapValuationToResponse(v)
+	}
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *Handler) CreateValuation(c *fiber.Ctx) error {
+	vehicleId, err := strconv.Atoi(c.Params("vehicleId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	var req CreateValuationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	date, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	if _, err := h.queries.GetVehicle(c.Context(), int32(vehicleId)); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	valuation, err := h.queries.CreateValuation(c.Context(), repository.CreateValuationParams{
+		VehicleID: int32(vehicleId),
+		Date:      date,
+		Value:     strconv.FormatFloat(req.Value, 'f', 2, 64),
+		Source:    sql.NullString{String: req.Source, Valid: req.Source != ""},
+		Notes:     sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create valuation", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapValuationToResponse(valuation)})
+}
+
+func (h *Handler) UpdateValuation(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid valuation ID"})
+	}
+
+	var req CreateValuationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	date, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	valuation, err := h.queries.UpdateValuation(c.Context(), repository.UpdateValuationParams{
+		ID:     int32(id),
+		Date:   date,
+		Value:  strconv.FormatFloat(req.Value, 'f', 2, 64),
+		Source: sql.NullString{String: req.Source, Valid: req.Source != ""},
+		Notes:  sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Valuation not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update valuation", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": mapValuationToResponse(valuation)})
+}
+
+func (h *Handler) DeleteValuation(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid valuation ID"})
+	}
+
+	if err := h.queries.DeleteValuation(c.Context(), int32(id)); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete valuation"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
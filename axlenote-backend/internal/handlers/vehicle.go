@@ -2,9 +2,14 @@ package handlers
 
 import (
 	"database/sql"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/axlenote/axlenote-backend/internal/depreciation"
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
 	"github.com/axlenote/axlenote-backend/internal/repository"
 	"github.com/gofiber/fiber/v2"
 )
@@ -18,6 +23,14 @@ type CreateVehicleRequest struct {
 	Vin          string `json:"vin"`
 	LicensePlate string `json:"license_plate"`
 	ImageUrl     string `json:"image_url"`
+	FuelType     string `json:"fuel_type"`     // petrol, diesel, lpg, cng, e85, electric
+	DistanceUnit string `json:"distance_unit"` // km or miles, defaults to METRICS_UNIT
+
+	PurchasePrice     float64  `json:"purchase_price"`
+	PurchaseDate      string   `json:"purchase_date"`      // YYYY-MM-DD, required with purchase_price
+	DepreciationModel string   `json:"depreciation_model"` // straight_line, declining_balance or valuations
+	DepreciationRate  *float64 `json:"depreciation_rate"`  // percent per year, DEPRECIATION_RATE when left out
+	ResidualValue     float64  `json:"residual_value"`
 }
 
 type VehicleResponse struct {
@@ -30,11 +43,31 @@ type VehicleResponse struct {
 	Vin          string `json:"vin"`
 	LicensePlate string `json:"license_plate"`
 	ImageUrl     string `json:"image_url"`
+	FuelType     string `json:"fuel_type"`
+	DistanceUnit string `json:"distance_unit"`
 	CreatedAt    string `json:"created_at"`
+
+	PurchasePrice     float64 `json:"purchase_price"`
+	PurchaseDate      string  `json:"purchase_date"`
+	DepreciationModel string  `json:"depreciation_model"`
+	DepreciationRate  float64 `json:"depreciation_rate"`
+	ResidualValue     float64 `json:"residual_value"`
+
+	Status     string   `json:"status"`
+	SaleDate   string   `json:"sale_date,omitempty"`
+	SalePrice  *float64 `json:"sale_price"`
+	BuyerNotes string   `json:"buyer_notes"`
+}
+
+type VehicleStatusRequest struct {
+	Status     string   `json:"status"`    // active, stored, sold or scrapped
+	SaleDate   string   `json:"sale_date"` // YYYY-MM-DD, defaults to today for sold and scrapped
+	SalePrice  *float64 `json:"sale_price"`
+	BuyerNotes string   `json:"buyer_notes"`
 }
 
 func mapVehicleToResponse(v repository.Vehicle) VehicleResponse {
-	return VehicleResponse{
+	resp := VehicleResponse{
 		ID:           v.ID,
 		Name:         v.Name,
 		Make:         v.Make.String,
@@ -44,8 +77,64 @@ func mapVehicleToResponse(v repository.Vehicle) VehicleResponse {
 		Vin:          v.Vin.String,
 		LicensePlate: v.LicensePlate.String,
 		ImageUrl:     v.ImageUrl.String,
+		FuelType:     fleet.FuelOf(v),
+		DistanceUnit: fleet.UnitOf(v),
 		CreatedAt:    v.CreatedAt.Time.Format(time.RFC3339),
+
+		DepreciationModel: depreciation.ModelOf(v),
+		DepreciationRate:  depreciation.RateOf(v),
+
+		Status:     v.Status,
+		BuyerNotes: v.BuyerNotes.String,
+	}
+	resp.PurchasePrice, _ = strconv.ParseFloat(v.PurchasePrice.String, 64)
+	resp.ResidualValue, _ = strconv.ParseFloat(v.ResidualValue.String, 64)
+	if v.PurchaseDate.Valid {
+		resp.PurchaseDate = v.PurchaseDate.Time.Format("2006-01-02")
+	}
+	if v.SaleDate.Valid {
+		resp.SaleDate = v.SaleDate.Time.Format("2006-01-02")
+	}
+	if v.SalePrice.Valid {
+		price, _ := strconv.ParseFloat(v.SalePrice.String, 64)
+		resp.SalePrice = &price
+	}
+	return resp
+}
+
+// validate checks a request and returns its parsed purchase date, or an error message.
+func (req CreateVehicleRequest) validate() (sql.NullTime, string) {
+	var purchased sql.NullTime
+	if req.FuelType != "" && !slices.Contains(fleet.FuelTypes, req.FuelType) {
+		return purchased, "Invalid fuel_type, use one of " + strings.Join(fleet.FuelTypes, ", ")
+	}
+	if req.DistanceUnit != "" && !slices.Contains(fleet.Units, req.DistanceUnit) {
+		return purchased, "Invalid distance_unit, use km or miles"
+	}
+
+	if req.PurchaseDate != "" {
+		date, err := time.Parse("2006-01-02", req.PurchaseDate)
+		if err != nil {
+			return purchased, "Invalid purchase_date, use YYYY-MM-DD"
+		}
+		purchased = sql.NullTime{Time: date, Valid: true}
+	}
+	if req.PurchasePrice < 0 || req.ResidualValue < 0 {
+		return purchased, "purchase_price and residual_value must not be negative"
+	}
+	if req.PurchasePrice > 0 && !purchased.Valid {
+		return purchased, "purchase_date is required with purchase_price"
+	}
+	if req.ResidualValue > req.PurchasePrice {
+		return purchased, "residual_value must not exceed purchase_price"
+	}
+	if req.DepreciationModel != "" && !slices.Contains(depreciation.Models, req.DepreciationModel) {
+		return purchased, "Invalid depreciation_model, use one of " + strings.Join(depreciation.Models, ", ")
+	}
+	if req.DepreciationRate != nil && (*req.DepreciationRate < 0 || *req.DepreciationRate > 100) {
+		return purchased, "depreciation_rate must be a percentage between 0 and 100"
 	}
+	return purchased, ""
 }
 
 func (h *Handler) CreateVehicle(c *fiber.Ctx) error {
@@ -57,6 +146,14 @@ func (h *Handler) CreateVehicle(c *fiber.Ctx) error {
 	if req.Name == "" {
 		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
 	}
+	purchased, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	var rate sql.NullString
+	if req.DepreciationRate != nil {
+		rate = sql.NullString{String: strconv.FormatFloat(*req.DepreciationRate, 'f', 2, 64), Valid: true}
+	}
 
 	vehicle, err := h.queries.CreateVehicle(c.Context(), repository.CreateVehicleParams{
 		Name:         req.Name,
@@ -67,12 +164,22 @@ func (h *Handler) CreateVehicle(c *fiber.Ctx) error {
 		Vin:          sql.NullString{String: req.Vin, Valid: req.Vin != ""},
 		LicensePlate: sql.NullString{String: req.LicensePlate, Valid: req.LicensePlate != ""},
 		ImageUrl:     sql.NullString{String: req.ImageUrl, Valid: req.ImageUrl != ""},
+		FuelType:     sql.NullString{String: req.FuelType, Valid: req.FuelType != ""},
+		DistanceUnit: sql.NullString{String: req.DistanceUnit, Valid: req.DistanceUnit != ""},
+
+		PurchasePrice:     sql.NullString{String: strconv.FormatFloat(req.PurchasePrice, 'f', 2, 64), Valid: req.PurchasePrice > 0},
+		PurchaseDate:      purchased,
+		DepreciationModel: sql.NullString{String: req.DepreciationModel, Valid: req.DepreciationModel != ""},
+		DepreciationRate:  rate,
+		ResidualValue:     sql.NullString{String: strconv.FormatFloat(req.ResidualValue, 'f', 2, 64), Valid: req.ResidualValue > 0},
 	})
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create vehicle", "details": err.Error()})
 	}
 
+	h.scheduler.PublishMQTT()
+
 	return c.Status(201).JSON(fiber.Map{"data": mapVehicleToResponse(vehicle)})
 }
 
@@ -90,6 +197,14 @@ func (h *Handler) UpdateVehicle(c *fiber.Ctx) error {
 	if req.Name == "" {
 		return c.Status(400).JSON(fiber.Map{"error": "Name is required"})
 	}
+	purchased, msg := req.validate()
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	var rate sql.NullString
+	if req.DepreciationRate != nil {
+		rate = sql.NullString{String: strconv.FormatFloat(*req.DepreciationRate, 'f', 2, 64), Valid: true}
+	}
 
 	vehicle, err := h.queries.UpdateVehicle(c.Context(), repository.UpdateVehicleParams{
 		ID:           int32(id),
@@ -101,26 +216,60 @@ func (h *Handler) UpdateVehicle(c *fiber.Ctx) error {
 		Vin:          sql.NullString{String: req.Vin, Valid: req.Vin != ""},
 		LicensePlate: sql.NullString{String: req.LicensePlate, Valid: req.LicensePlate != ""},
 		ImageUrl:     sql.NullString{String: req.ImageUrl, Valid: req.ImageUrl != ""},
+		FuelType:     sql.NullString{String: req.FuelType, Valid: req.FuelType != ""},
+		DistanceUnit: sql.NullString{String: req.DistanceUnit, Valid: req.DistanceUnit != ""},
+
+		PurchasePrice:     sql.NullString{String: strconv.FormatFloat(req.PurchasePrice, 'f', 2, 64), Valid: req.PurchasePrice > 0},
+		PurchaseDate:      purchased,
+		DepreciationModel: sql.NullString{String: req.DepreciationModel, Valid: req.DepreciationModel != ""},
+		DepreciationRate:  rate,
+		ResidualValue:     sql.NullString{String: strconv.FormatFloat(req.ResidualValue, 'f', 2, 64), Valid: req.ResidualValue > 0},
 	})
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to update vehicle", "details": err.Error()})
 	}
 
+	h.scheduler.PublishMQTT()
+
 	return c.JSON(fiber.Map{"data": mapVehicleToResponse(vehicle)})
 }
 
+// DeleteVehicle removes a vehicle along with its history. A vehicle that still has records
+// must be sold or scrapped first, unless ?force=true is passed, so they are not lost by
+// accident.
 func (h *Handler) DeleteVehicle(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
 	}
 
-	err = h.queries.DeleteVehicle(c.Context(), int32(id))
+	vehicle, err := h.queries.GetVehicle(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+	if !fleet.IsArchived(vehicle) && !c.QueryBool("force") {
+		hasHistory, err := h.queries.VehicleHasHistory(c.Context(), vehicle.ID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to check vehicle history", "details": err.Error()})
+		}
+		if hasHistory {
+			return c.Status(409).JSON(fiber.Map{
+				"error": "Vehicle has history. Archive it with PUT /api/v1/vehicles/:id/status to keep its records, or pass ?force=true to delete it with them",
+			})
+		}
+	}
+
+	err = h.queries.DeleteVehicle(c.Context(), vehicle.ID)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete vehicle"})
 	}
 
+	h.scheduler.RemoveMQTTVehicle(vehicle.ID)
+
 	return c.JSON(fiber.Map{"message": "Vehicle deleted successfully"})
 }
 
@@ -141,16 +290,125 @@ func (h *Handler) GetVehicle(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"data": mapVehicleToResponse(vehicle)})
 }
 
+var vehicleSorts = sortKeys[repository.Vehicle]{
+	"created_at": func(v repository.Vehicle) float64 { return nullTimestampKey(v.CreatedAt) },
+	"year":       func(v repository.Vehicle) float64 { return float64(v.Year.Int32) },
+}
+
+// GetVehicles pages through vehicles, newest first by default (?sort=created_at or year).
+// Sold and scrapped vehicles are left out unless ?status=archived or ?status=all; a
+// single status such as ?status=stored lists only those.
 func (h *Handler) GetVehicles(c *fiber.Ctx) error {
-	vehicles, err := h.queries.ListVehicles(c.Context())
+	opts, msg := parseListOptions(c, vehicleSorts, "-created_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+	var keep func(repository.Vehicle) bool
+	switch status := c.Query("status", "current"); {
+	case status == "current":
+		keep = func(v repository.Vehicle) bool { return !fleet.IsArchived(v) }
+	case status == "archived":
+		keep = fleet.IsArchived
+	case status == "all":
+		keep = func(repository.Vehicle) bool { return true }
+	case slices.Contains(fleet.Statuses, status):
+		keep = func(v repository.Vehicle) bool { return v.Status == status }
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid status, use current, archived, all or one of " + strings.Join(fleet.Statuses, ", ")})
+	}
+
+	all, err := h.queries.ListVehicles(c.Context())
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
+	vehicles := slices.DeleteFunc(all, func(v repository.Vehicle) bool { return !keep(v) })
+	vehicles, meta := paginate(vehicles, opts, vehicleSorts, func(v repository.Vehicle) int32 { return v.ID })
 
 	response := make([]VehicleResponse, len(vehicles))
 	for i, v := range vehicles {
 		response[i] = mapVehicleToResponse(v)
 	}
 
-	return c.JSON(fiber.Map{"data": response})
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+// validate checks a request, defaulting the sale date of a sold or scrapped vehicle to
+// today, and returns the parsed date or an error message.
+func (req VehicleStatusRequest) validate(v repository.Vehicle) (sql.NullTime, string) {
+	var sold sql.NullTime
+	if !slices.Contains(fleet.Statuses, req.Status) {
+		return sold, "Invalid status, use one of " + strings.Join(fleet.Statuses, ", ")
+	}
+	if req.Status == fleet.StatusActive || req.Status == fleet.StatusStored {
+		if req.SaleDate != "" || req.SalePrice != nil || req.BuyerNotes != "" {
+			return sold, "sale_date, sale_price and buyer_notes only apply to sold or scrapped vehicles"
+		}
+		return sold, ""
+	}
+
+	sold = sql.NullTime{Time: forecast.Today(), Valid: true}
+	if req.SaleDate != "" {
+		date, err := time.Parse("2006-01-02", req.SaleDate)
+		if err != nil {
+			return sold, "Invalid sale_date, use YYYY-MM-DD"
+		}
+		sold.Time = date
+	}
+	if v.PurchaseDate.Valid && sold.Time.Before(v.PurchaseDate.Time) {
+		return sold, "sale_date must not be before purchase_date"
+	}
+	if req.SalePrice != nil && *req.SalePrice < 0 {
+		return sold, "sale_price must not be negative"
+	}
+	return sold, ""
+}
+
+// UpdateVehicleStatus moves a vehicle through its life: active, stored, sold or scrapped.
+// Archiving keeps the vehicle's history, unlike deleting it. Going back to active or
+// stored clears the sale details.
+func (h *Handler) UpdateVehicleStatus(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vehicle ID"})
+	}
+
+	var req VehicleStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	vehicle, err := h.queries.GetVehicle(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vehicle not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+	sold, msg := req.validate(vehicle)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	var price sql.NullString
+	if req.SalePrice != nil {
+		price = sql.NullString{String: strconv.FormatFloat(*req.SalePrice, 'f', 2, 64), Valid: true}
+	}
+	vehicle, err = h.queries.UpdateVehicleStatus(c.Context(), repository.UpdateVehicleStatusParams{
+		ID:         vehicle.ID,
+		Status:     req.Status,
+		SaleDate:   sold,
+		SalePrice:  price,
+		BuyerNotes: sql.NullString{String: req.BuyerNotes, Valid: req.BuyerNotes != ""},
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update vehicle status", "details": err.Error()})
+	}
+
+	if fleet.IsArchived(vehicle) {
+		h.scheduler.RemoveMQTTVehicle(vehicle.ID)
+	} else {
+		h.scheduler.PublishMQTT()
+	}
+
+	return c.JSON(fiber.Map{"data": mapVehicleToResponse(vehicle)})
 }
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateVendorRequest struct {
+	Name    string `json:"name"`
+	Website string `json:"website"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+	Notes   string `json:"notes"`
+}
+
+type VendorResponse struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Website   string `json:"website"`
+	Phone     string `json:"phone"`
+	Address   string `json:"address"`
+	Notes     string `json:"notes"`
+	CreatedAt string `json:"created_at"`
+}
+
+func mapVendorToResponse(v repository.Vendor) VendorResponse {
+	return VendorResponse{
+		ID:        v.ID,
+		Name:      v.Name,
+		Website:   v.Website.String,
+		Phone:     v.Phone.String,
+		Address:   v.Address.String,
+		Notes:     v.Notes.String,
+		CreatedAt: v.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
+
+func (req *CreateVendorRequest) validate() string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "Name is required"
+	}
+	if len(req.Name) > 100 {
+		return "Name must be at most 100 characters"
+	}
+	if len(req.Phone) > 30 {
+		return "Phone must be at most 30 characters"
+	}
+	return ""
+}
+
+func (h *Handler) ListVendors(c *fiber.Ctx) error {
+	vendors, err := h.queries.ListVendors(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch vendors", "details": err.Error()})
+	}
+
+	response := make([]VendorResponse, len(vendors))
+	for i, v := range vendors {
+		response[i] = mapVendorToResponse(v)
+	}
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *Handler) GetVendor(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vendor ID"})
+	}
+
+	vendor, err := h.queries.GetVendor(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vendor not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	return c.JSON(fiber.Map{"data": mapVendorToResponse(vendor)})
+}
+
+func (h *Handler) CreateVendor(c *fiber.Ctx) error {
+	var req CreateVendorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if msg := req.validate(); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	vendor, err := h.queries.CreateVendor(c.Context(), repository.CreateVendorParams{
+		Name:    req.Name,
+		Website: sql.NullString{String: req.Website, Valid: req.Website != ""},
+		Phone:   sql.NullString{String: req.Phone, Valid: req.Phone != ""},
+		Address: sql.NullString{String: req.Address, Valid: req.Address != ""},
+		Notes:   sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create vendor", "details": err.Error()})
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": mapVendorToResponse(vendor)})
+}
+
+func (h *Handler) UpdateVendor(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vendor ID"})
+	}
+
+	var req CreateVendorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if msg := req.validate(); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	vendor, err := h.queries.UpdateVendor(c.Context(), repository.UpdateVendorParams{
+		ID:      int32(id),
+		Name:    req.Name,
+		Website: sql.NullString{String: req.Website, Valid: req.Website != ""},
+		Phone:   sql.NullString{String: req.Phone, Valid: req.Phone != ""},
+		Address: sql.NullString{String: req.Address, Valid: req.Address != ""},
+		Notes:   sql.NullString{String: req.Notes, Valid: req.Notes != ""},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Vendor not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update vendor", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": mapVendorToResponse(vendor)})
+}
+
+// DeleteVendor removes a vendor. Expenses paid to it are kept, without the link.
+func (h *Handler) DeleteVendor(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid vendor ID"})
+	}
+
+	if err := h.queries.DeleteVendor(c.Context(), int32(id)); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete vendor"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateWebhookRequest struct {
+	Url         string   `json:"url"`
+	Secret      string   `json:"secret"`      // generated when empty; only accepted on create
+	EventTypes  []string `json:"event_types"` // empty = all events
+	Description string   `json:"description"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+type WebhookResponse struct {
+	ID          int32    `json:"id"`
+	Url         string   `json:"url"`
+	Secret      string   `json:"secret,omitempty"` // only returned when the webhook is created
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description"`
+	Enabled     bool     `json:"enabled"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int32           `json:"id"`
+	WebhookID      int32           `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttemptAt  string          `json:"next_attempt_at,omitempty"` // set while pending
+	ResponseStatus int32           `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	CreatedAt      string          `json:"created_at"`
+	DeliveredAt    string          `json:"delivered_at,omitempty"`
+}
+
+func mapWebhookToResponse(w repository.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:          w.ID,
+		Url:         w.Url,
+		EventTypes:  nonNilStrings(w.EventTypes),
+		Description: w.Description.String,
+		Enabled:     w.Enabled,
+		CreatedAt:   w.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
+
+func mapWebhookDeliveryToResponse(d repository.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus.Int32,
+		Error:          d.Error.String,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.Status == webhook.StatusPending {
+		resp.NextAttemptAt = d.NextAttemptAt.Format(time.RFC3339)
+	}
+	if d.DeliveredAt.Valid {
+		resp.DeliveredAt = d.DeliveredAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
+
+// normalize validates the request and returns an error message, if any.
+func (req *CreateWebhookRequest) normalize(ctx context.Context) string {
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "A valid http or https URL is required"
+	}
+	if err := webhook.CheckURL(ctx, req.Url); err != nil {
+		return "Webhook URL must not point to a loopback, private or link-local address"
+	}
+	for _, e := range req.EventTypes {
+		if !webhook.ValidEvent(e) {
+			return "Invalid event type " + e
+		}
+	}
+	req.EventTypes = nonNilStrings(req.EventTypes)
+	return ""
+}
+
+func (req *CreateWebhookRequest) enabled() bool {
+	return req.Enabled == nil || *req.Enabled
+}
+
+// ListWebhookEvents returns the event types webhooks can subscribe to.
+func (h *Handler) ListWebhookEvents(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"data": webhook.Events})
+}
+
+var webhookSorts = sortKeys[repository.Webhook]{
+	"created_at": func(w repository.Webhook) float64 { return nullTimestampKey(w.CreatedAt) },
+}
+
+func (h *Handler) ListWebhooks(c *fiber.Ctx) error {
+	opts, msg := parseListOptions(c, webhookSorts, "created_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	hooks, err := h.queries.ListWebhooks(c.Context())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch webhooks"})
+	}
+	hooks, meta := paginate(hooks, opts, webhookSorts, func(w repository.Webhook) int32 { return w.ID })
+
+	response := make([]WebhookResponse, len(hooks))
+	for i, w := range hooks {
+		response[i] = mapWebhookToResponse(w)
+	}
+
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+func (h *Handler) CreateWebhook(c *fiber.Ctx) error {
+	var req CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if msg := req.normalize(c.Context()); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	if req.Secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to generate secret", "details": err.Error()})
+		}
+		req.Secret = hex.EncodeToString(buf)
+	}
+
+	hook, err := h.queries.CreateWebhook(c.Context(), repository.CreateWebhookParams{
+		Url:         req.Url,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Enabled:     req.enabled(),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create webhook", "details": err.Error()})
+	}
+
+	response := mapWebhookToResponse(hook)
+	response.Secret = hook.Secret
+	return c.Status(201).JSON(fiber.Map{"data": response})
+}
+
+func (h *Handler) UpdateWebhook(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid webhook ID"})
+	}
+
+	var req CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if msg := req.normalize(c.Context()); msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	hook, err := h.queries.UpdateWebhook(c.Context(), repository.UpdateWebhookParams{
+		ID:          int32(id),
+		Url:         req.Url,
+		EventTypes:  req.EventTypes,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Enabled:     req.enabled(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Webhook not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update webhook", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": mapWebhookToResponse(hook)})
+}
+
+func (h *Handler) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid webhook ID"})
+	}
+
+	if err := h.queries.DeleteWebhook(c.Context(), int32(id)); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete webhook"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
+
+// TestWebhook queues a ping event for a webhook, even if it is disabled or filters it out.
+func (h *Handler) TestWebhook(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid webhook ID"})
+	}
+
+	hook, err := h.queries.GetWebhook(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Webhook not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch webhook", "details": err.Error()})
+	}
+
+	payload, err := webhook.Payload(webhook.Ping, fiber.Map{"webhook_id": hook.ID})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to build payload", "details": err.Error()})
+	}
+	delivery, err := h.queries.CreateWebhookDelivery(c.Context(), repository.CreateWebhookDeliveryParams{
+		WebhookID: hook.ID,
+		EventType: webhook.Ping,
+		Payload:   payload,
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to queue delivery", "details": err.Error()})
+	}
+	h.scheduler.DeliverWebhooks()
+
+	return c.Status(202).JSON(fiber.Map{"data": mapWebhookDeliveryToResponse(delivery)})
+}
+
+var webhookDeliverySorts = sortKeys[repository.WebhookDelivery]{
+	"created_at": func(d repository.WebhookDelivery) float64 { return timestampKey(d.CreatedAt) },
+}
+
+// listWebhookDeliveries runs the delivery query for sort, like listFuelLogs.
+func (h *Handler) listWebhookDeliveries(ctx context.Context, sort string, p repository.ListWebhookDeliveriesByCreatedAtAscParams) ([]repository.WebhookDelivery, error) {
+	switch sort {
+	case "created_at":
+		return h.queries.ListWebhookDeliveriesByCreatedAtAsc(ctx, p)
+	case "-created_at":
+		return h.queries.ListWebhookDeliveriesByCreatedAtDesc(ctx, repository.ListWebhookDeliveriesByCreatedAtDescParams(p))
+	}
+	return nil, fmt.Errorf("no webhook delivery query for sort %q", sort)
+}
+
+// ListWebhookDeliveries pages through a webhook's delivery log, newest first. Filter with
+// ?status=pending|success|failed, ?event= and ?from= / ?to= for the day it was queued.
+func (h *Handler) ListWebhookDeliveries(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid webhook ID"})
+	}
+
+	opts, msg := parseListOptions(c, webhookDeliverySorts, "-created_at")
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	filters := repository.CountWebhookDeliveriesParams{
+		WebhookID: int32(id),
+		Status:    queryNullString(c, "status"),
+		EventType: queryNullString(c, "event"),
+		DateFrom:  opts.dateFrom,
+		DateTo:    opts.dateTo,
+	}
+	deliveries, err := h.listWebhookDeliveries(c.Context(), opts.sortParam(), repository.ListWebhookDeliveriesByCreatedAtAscParams{
+		WebhookID: filters.WebhookID,
+		Status:    filters.Status,
+		EventType: filters.EventType,
+		DateFrom:  filters.DateFrom,
+		DateTo:    filters.DateTo,
+		AfterKey:  opts.afterKey(),
+		AfterID:   opts.afterID(),
+		Limit:     opts.fetchLimit(),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch deliveries", "details": err.Error()})
+	}
+	total, err := h.queries.CountWebhookDeliveries(c.Context(), filters)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch deliveries", "details": err.Error()})
+	}
+	deliveries, meta := pageOf(deliveries, opts, total, webhookDeliverySorts, func(d repository.WebhookDelivery) int32 { return d.ID })
+
+	response := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		response[i] = mapWebhookDeliveryToResponse(d)
+	}
+
+	return c.JSON(fiber.Map{"data": response, "meta": meta})
+}
+
+// RetryWebhookDelivery queues a delivery to be sent again right away, e.g. after fixing
+// the receiving end. Its attempt count is kept.
+func (h *Handler) RetryWebhookDelivery(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid delivery ID"})
+	}
+
+	delivery, err := h.queries.RetryWebhookDelivery(c.Context(), int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Delivery not found"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to retry delivery", "details": err.Error()})
+	}
+	h.scheduler.DeliverWebhooks()
+
+	return c.Status(202).JSON(fiber.Map{"data": mapWebhookDeliveryToResponse(delivery)})
+}
@@ -0,0 +1,101 @@
+// Package ical writes iCalendar (RFC 5545) feeds of all-day events.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const prodID = "-//AxleNote//Reminders//EN"
+
+// Event is a single all-day event. UID must stay the same across feed refreshes so
+// calendar clients update the event instead of adding a duplicate.
+type Event struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	Categories  []string
+}
+
+// Calendar is a named collection of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write renders the calendar. Lines end with CRLF and are folded at 75 octets as the spec requires.
+func (c Calendar) Write(w io.Writer) error {
+	lw := &lineWriter{w: w}
+	now := time.Now().UTC().Format("20060102T150405Z")
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + prodID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	lw.line("X-WR-CALNAME:" + escape(c.Name))
+	// Ask clients to refresh a few times a day
+	lw.line("REFRESH-INTERVAL;VALUE=DURATION:PT6H")
+	lw.line("X-PUBLISHED-TTL:PT6H")
+
+	for _, e := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + e.UID)
+		lw.line("DTSTAMP:" + now)
+		lw.line("DTSTART;VALUE=DATE:" + e.Date.Format("20060102"))
+		lw.line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format("20060102"))
+		lw.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if len(e.Categories) > 0 {
+			cats := make([]string, len(e.Categories))
+			for i, cat := range e.Categories {
+				cats[i] = escape(cat)
+			}
+			lw.line("CATEGORIES:" + strings.Join(cats, ","))
+		}
+		lw.line("TRANSP:TRANSPARENT")
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes a content line, folding it without splitting multi-byte characters.
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = 74
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	_, lw.err = fmt.Fprint(lw.w, b.String())
+}
@@ -0,0 +1,98 @@
+// Package insights spots trends in a vehicle's logs worth telling the owner about, like a
+// sudden drop in fuel economy, which is often the first sign of a dragging brake or low
+// tyre pressure.
+package insights
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// Insight kinds.
+const (
+	KindEconomyDrop = "economy_drop"
+)
+
+const (
+	// RecentFills is how many of the latest full-tank fills are checked against the history.
+	RecentFills = 3
+	// minBaselineFills is how much history is needed before a drop can be told from noise.
+	minBaselineFills = 5
+	// maxBaselineFills limits the history to recent driving, so a change of habits or
+	// season settles into the baseline over time.
+	maxBaselineFills = 20
+	// minDropPercent ignores drops too small to be worth a look, however consistent.
+	minDropPercent = 10
+	// zThreshold is the one-sided 99% level the recent average has to fall below.
+	zThreshold = 2.33
+	// minSpread keeps very consistent histories from flagging tiny dips, as a share of the
+	// baseline economy.
+	minSpread = 0.02
+)
+
+// EconomyDrop describes a significant drop in fuel economy.
+type EconomyDrop struct {
+	Baseline      float64 // average economy of the fills before the recent ones
+	Recent        float64 // average economy of the recent fills
+	ChangePercent float64 // negative for a drop
+	ZScore        float64 // how many standard errors the recent average is below the baseline
+	FuelLogID     int32   // latest fill
+}
+
+// DetectEconomyDrop checks the economy of the latest full-tank fills, oldest first, for
+// a statistically significant drop compared to the fills before them. The recent average
+// has to be more than zThreshold standard errors below the baseline and at least
+// minDropPercent lower.
+func DetectEconomyDrop(fills []repository.ListFullTankEconomyRow) (EconomyDrop, bool) {
+	if len(fills) < minBaselineFills+RecentFills {
+		return EconomyDrop{}, false
+	}
+	recent := fills[len(fills)-RecentFills:]
+	baseline := fills[max(0, len(fills)-RecentFills-maxBaselineFills) : len(fills)-RecentFills]
+
+	mean, sd := meanStdDev(baseline)
+	recentMean, _ := meanStdDev(recent)
+	if mean <= 0 {
+		return EconomyDrop{}, false
+	}
+	sd = math.Max(sd, mean*minSpread)
+
+	drop := EconomyDrop{
+		Baseline:      round2(mean),
+		Recent:        round2(recentMean),
+		ChangePercent: round2((recentMean - mean) / mean * 100),
+		ZScore:        round2((mean - recentMean) / (sd / math.Sqrt(RecentFills))),
+		FuelLogID:     recent[len(recent)-1].ID,
+	}
+	if -drop.ChangePercent < minDropPercent || drop.ZScore < zThreshold {
+		return EconomyDrop{}, false
+	}
+	return drop, true
+}
+
+// Message describes an economy drop in English, for API responses.
+func (d EconomyDrop) Message(unit string) string {
+	return fmt.Sprintf("Fuel economy over the last %d full-tank fills averaged %.1f %s/L, %.0f%% below the usual %.1f %s/L. Check tyre pressures and for dragging brakes.",
+		RecentFills, d.Recent, unit, -d.ChangePercent, d.Baseline, unit)
+}
+
+func meanStdDev(fills []repository.ListFullTankEconomyRow) (mean, sd float64) {
+	for _, f := range fills {
+		mean += f.Economy
+	}
+	mean /= float64(len(fills))
+	if len(fills) < 2 {
+		return mean, 0
+	}
+	var squares float64
+	for _, f := range fills {
+		squares += (f.Economy - mean) * (f.Economy - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(fills)-1))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,198 @@
+// Package loan works out the repayment schedule of a vehicle loan and applies the
+// payments made against it: interest paid, principal repaid and the balance outstanding.
+package loan
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/expense"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// EMI is the equal monthly instalment that repays principal at annualRate percent over
+// months, with interest on the reducing balance.
+func EMI(principal, annualRate float64, months int) float64 {
+	if months <= 0 {
+		return 0
+	}
+	r := annualRate / 1200
+	if r == 0 {
+		return round2(principal / float64(months))
+	}
+	pow := math.Pow(1+r, float64(months))
+	return round2(principal * r * pow / (pow - 1))
+}
+
+// Instalment is one row of the amortization schedule.
+type Instalment struct {
+	Number    int     `json:"number"`
+	DueDate   string  `json:"due_date"` // YYYY-MM-DD
+	EMI       float64 `json:"emi"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"` // outstanding after the instalment
+	Paid      bool    `json:"paid"`    // covered by the payments made
+	due       time.Time
+}
+
+// Payment is a payment made, split into the interest and principal it paid.
+type Payment struct {
+	ID         int32   `json:"id"`
+	LoanID     int32   `json:"loan_id"`
+	Date       string  `json:"date"` // YYYY-MM-DD
+	Amount     float64 `json:"amount"`
+	Prepayment bool    `json:"prepayment"`
+	Interest   float64 `json:"interest"`
+	Principal  float64 `json:"principal"`
+	Balance    float64 `json:"balance"` // outstanding after the payment
+	Notes      string  `json:"notes"`
+	date       time.Time
+}
+
+// Status sums up where a loan stands.
+type Status struct {
+	Outstanding     float64 `json:"outstanding"`
+	PrincipalPaid   float64 `json:"principal_paid"`
+	InterestPaid    float64 `json:"interest_paid"`
+	TotalPaid       float64 `json:"total_paid"`
+	InstalmentsPaid int     `json:"instalments_paid"`
+	NextDueDate     string  `json:"next_due_date,omitempty"` // unset once the loan is repaid
+	NextAmount      float64 `json:"next_amount,omitempty"`
+	Overdue         bool    `json:"overdue"`
+	Closed          bool    `json:"closed"`
+	nextDue         time.Time
+}
+
+// NextDue returns the due date of the next instalment, or false once the loan is repaid.
+func (s Status) NextDue() (time.Time, bool) {
+	return s.nextDue, !s.Closed && !s.nextDue.IsZero()
+}
+
+// Ledger is a loan's schedule and payments, and the status they add up to.
+type Ledger struct {
+	Schedule []Instalment `json:"schedule"`
+	Payments []Payment    `json:"payments"`
+	Status   Status       `json:"status"`
+}
+
+// ForLoan builds the ledger of a loan from its payments, oldest first, as of today.
+//
+// The schedule is the lender's projection: interest at a twelfth of the annual rate on
+// the balance each month. Payments are applied as they were made: each pays the interest
+// accrued day by day since the previous payment (or the start date) first, and the rest
+// off the principal. EMIs paid cover instalments in order; prepayments only bring the
+// balance down, which ends the loan early.
+func ForLoan(l repository.Loan, payments []repository.LoanPayment, today time.Time) Ledger {
+	principal, _ := strconv.ParseFloat(l.Principal, 64)
+	rate, _ := strconv.ParseFloat(l.AnnualRate, 64)
+	emi, _ := strconv.ParseFloat(l.Emi, 64)
+
+	ledger := Ledger{
+		Schedule: schedule(principal, rate, emi, int(l.TenureMonths), l.FirstEmiDate),
+		Payments: make([]Payment, 0, len(payments)),
+	}
+
+	balance, accrued, last, emisPaid := principal, 0.0, l.StartDate, 0.0
+	status := &ledger.Status
+	for _, p := range payments {
+		amount, _ := strconv.ParseFloat(p.Amount, 64)
+		if p.Date.After(last) {
+			accrued += balance * rate / 100 / 365 * p.Date.Sub(last).Hours() / 24
+			last = p.Date
+		}
+		interest := math.Min(amount, accrued)
+		accrued -= interest
+		repaid := math.Min(amount-interest, balance)
+		balance -= repaid
+
+		status.InterestPaid += interest
+		status.PrincipalPaid += repaid
+		status.TotalPaid += amount
+		if !p.Prepayment {
+			emisPaid += amount
+		}
+		ledger.Payments = append(ledger.Payments, Payment{
+			ID:         p.ID,
+			LoanID:     p.LoanID,
+			Date:       p.Date.Format("2006-01-02"),
+			Amount:     round2(amount),
+			Prepayment: p.Prepayment,
+			Interest:   round2(interest),
+			Principal:  round2(repaid),
+			Balance:    round2(balance),
+			Notes:      p.Notes.String,
+			date:       p.Date,
+		})
+	}
+	status.Outstanding = round2(balance)
+	status.PrincipalPaid = round2(status.PrincipalPaid)
+	status.InterestPaid = round2(status.InterestPaid)
+	status.TotalPaid = round2(status.TotalPaid)
+	status.Closed = status.Outstanding <= 0
+
+	// Instalments count as paid in order, as far as the EMIs paid go
+	var covered float64
+	for i := range ledger.Schedule {
+		covered += ledger.Schedule[i].EMI
+		if covered > emisPaid+0.005 && !status.Closed {
+			break
+		}
+		ledger.Schedule[i].Paid = true
+		status.InstalmentsPaid++
+	}
+
+	if !status.Closed && len(ledger.Schedule) > 0 {
+		if status.InstalmentsPaid < len(ledger.Schedule) {
+			next := ledger.Schedule[status.InstalmentsPaid]
+			status.nextDue, status.NextAmount = next.due, math.Min(next.EMI, status.Outstanding)
+		} else {
+			// Paid as scheduled but late, so interest is still owed after the last instalment
+			status.nextDue, status.NextAmount = ledger.Schedule[len(ledger.Schedule)-1].due, status.Outstanding
+		}
+		status.NextDueDate = status.nextDue.Format("2006-01-02")
+		status.Overdue = status.nextDue.Before(today)
+	}
+	return ledger
+}
+
+// InterestBetween adds up the interest paid between from and to, inclusive.
+func (l Ledger) InterestBetween(from, to time.Time) float64 {
+	var interest float64
+	for _, p := range l.Payments {
+		if !p.date.Before(from) && !p.date.After(to) {
+			interest += p.Interest
+		}
+	}
+	return interest
+}
+
+func schedule(principal, annualRate, emi float64, months int, firstDue time.Time) []Instalment {
+	r := annualRate / 1200
+	balance := principal
+	out := make([]Instalment, 0, months)
+	for n := 1; n <= months && balance > 0; n++ {
+		interest := round2(balance * r)
+		repaid := emi - interest
+		if n == months || repaid > balance {
+			repaid = balance
+		}
+		balance = round2(balance - repaid)
+		due := expense.Occurrence(firstDue, expense.RecurrenceMonthly, n-1)
+		out = append(out, Instalment{
+			Number:    n,
+			DueDate:   due.Format("2006-01-02"),
+			EMI:       round2(repaid + interest),
+			Principal: round2(repaid),
+			Interest:  interest,
+			Balance:   balance,
+			due:       due,
+		})
+	}
+	return out
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,235 @@
+package messages
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const DefaultLocale = "en"
+
+// Message kinds that can be customised per user.
+const (
+	KindReminderOverdue  = "reminder_overdue"
+	KindReminderUpcoming = "reminder_upcoming"
+	KindEconomyDrop      = "economy_drop"
+	KindBudgetThreshold  = "budget_threshold"
+	KindEMIDue           = "emi_due"
+)
+
+var Kinds = []string{KindReminderOverdue, KindReminderUpcoming, KindEconomyDrop, KindBudgetThreshold, KindEMIDue}
+
+// Trigger kinds describing why a reminder or document alert fired.
+const (
+	TriggerDateDue             = "date_due"
+	TriggerDateUpcoming        = "date_upcoming"
+	TriggerOdometerReached     = "odometer_reached"
+	TriggerOdometerApproaching = "odometer_approaching"
+	TriggerOdometerProjected   = "odometer_projected"
+	TriggerDocumentExpired     = "document_expired"
+	TriggerDocumentExpiring    = "document_expiring"
+)
+
+// Template is a title and body pair using text/template placeholders, e.g. {{.Vehicle}}.
+type Template struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Data holds the placeholders available to notification templates.
+type Data struct {
+	Vehicle         string
+	Reminder        string
+	Type            string
+	Level           string // overdue or upcoming
+	Trigger         string // localized description of why the reminder fired
+	DueDate         string
+	ProjectedDate   string // when an odometer reminder is expected to fall due at the current driving rate
+	DueOdometer     int32
+	CurrentOdometer int32
+	DaysRemaining   int
+	KmRemaining     int32
+	Unit            string
+	Economy         string // recent fuel economy, for economy drop alerts
+	UsualEconomy    string
+	DropPercent     int
+	Currency        string
+	Budget          string // budget amount, for budget alerts
+	Spent           string
+	Percent         int // of the budget spent
+	Threshold       int // percentage crossed
+	Period          string
+	Lender          string // for EMI reminders
+	Amount          string // of the EMI
+	Outstanding     string // loan balance
+}
+
+type bundle struct {
+	ReminderOverdue  Template          `json:"reminder_overdue"`
+	ReminderUpcoming Template          `json:"reminder_upcoming"`
+	EconomyDrop      Template          `json:"economy_drop"`
+	BudgetThreshold  Template          `json:"budget_threshold"`
+	EMIDue           Template          `json:"emi_due"`
+	Triggers         map[string]string `json:"triggers"`
+}
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var bundles = loadBundles()
+
+func loadBundles() map[string]bundle {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	out := make(map[string]bundle)
+	for _, e := range entries {
+		raw, err := localeFS.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var b bundle
+		if err := json.Unmarshal(raw, &b); err != nil {
+			panic(fmt.Sprintf("messages: invalid locale file %s: %v", e.Name(), err))
+		}
+		out[strings.TrimSuffix(e.Name(), ".json")] = b
+	}
+	return out
+}
+
+// Locales lists the bundled translations.
+func Locales() []string {
+	locales := make([]string, 0, len(bundles))
+	for l := range bundles {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func SupportedLocale(locale string) bool {
+	_, ok := bundles[locale]
+	return ok
+}
+
+func ValidKind(kind string) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func lookup(locale string) bundle {
+	if b, ok := bundles[locale]; ok {
+		return b
+	}
+	return bundles[DefaultLocale]
+}
+
+// Bundled returns the stock template for a kind in the given locale, falling back to
+// English for kinds the locale has not translated.
+func Bundled(locale, kind string) Template {
+	if t := lookup(locale).template(kind); t.Title != "" {
+		return t
+	}
+	return bundles[DefaultLocale].template(kind)
+}
+
+func (b bundle) template(kind string) Template {
+	switch kind {
+	case KindReminderOverdue:
+		return b.ReminderOverdue
+	case KindEconomyDrop:
+		return b.EconomyDrop
+	case KindBudgetThreshold:
+		return b.BudgetThreshold
+	case KindEMIDue:
+		return b.EMIDue
+	}
+	return b.ReminderUpcoming
+}
+
+// TriggerText describes why a reminder fired, in the given locale.
+func TriggerText(locale, trigger string, data Data) string {
+	text, ok := lookup(locale).Triggers[trigger]
+	if !ok {
+		text = bundles[DefaultLocale].Triggers[trigger]
+	}
+	out, err := execute("trigger", text, data)
+	if err != nil {
+		return trigger
+	}
+	return out
+}
+
+// Render fills in a template's placeholders.
+func Render(t Template, data Data) (title, body string, err error) {
+	title, err = execute("title", t.Title, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err = execute("body", t.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+// Validate checks that a template parses and only uses known placeholders,
+// returning the template rendered against sample data.
+func Validate(t Template) (title, body string, err error) {
+	if strings.TrimSpace(t.Title) == "" || strings.TrimSpace(t.Body) == "" {
+		return "", "", fmt.Errorf("title and body are required")
+	}
+	return Render(t, SampleData())
+}
+
+// SampleData is used to validate and preview templates.
+func SampleData() Data {
+	return Data{
+		Vehicle:         "My Bike",
+		Reminder:        "Oil Change",
+		Type:            "Service",
+		Level:           "upcoming",
+		Trigger:         "Odometer Approaching: 15000 km (Current: 14700)",
+		DueDate:         "2025-01-31",
+		ProjectedDate:   "2025-01-24",
+		DueOdometer:     15000,
+		CurrentOdometer: 14700,
+		DaysRemaining:   5,
+		KmRemaining:     300,
+		Unit:            "km",
+		Economy:         "11.8",
+		UsualEconomy:    "14.2",
+		DropPercent:     17,
+		Currency:        "₹",
+		Budget:          "5000.00",
+		Spent:           "4125.50",
+		Percent:         82,
+		Threshold:       80,
+		Period:          "2025-01-01 to 2025-01-31",
+		Lender:          "HDFC Bank",
+		Amount:          "15899.87",
+		Outstanding:     "341128.70",
+	}
+}
+
+func execute(name, text string, data Data) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,221 @@
+package mqtt
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// State is the retained JSON document published per vehicle; every sensor reads one field.
+type State struct {
+	Odometer        int32    `json:"odometer"`
+	FuelEconomy     *float64 `json:"fuel_economy"` // distance per litre over the last full tank
+	MonthlyCost     float64  `json:"monthly_cost"` // fuel, service and expense spend this calendar month
+	NextReminder    *string  `json:"next_reminder"`
+	NextReminderDue *string  `json:"next_reminder_due"` // YYYY-MM-DD
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+type sensor struct {
+	key         string
+	name        string
+	icon        string
+	unit        string
+	deviceClass string
+	stateClass  string
+	attributes  string // optional json_attributes_template
+}
+
+func (b *Bridge) sensors() []sensor {
+	return []sensor{
+		{key: "odometer", name: "Odometer", icon: "mdi:counter", unit: b.distanceUnit(), deviceClass: "distance", stateClass: "total_increasing"},
+		{key: "fuel_economy", name: "Fuel economy", icon: "mdi:gas-station", unit: b.distanceUnit() + "/L", stateClass: "measurement"},
+		{key: "monthly_cost", name: "Monthly cost", icon: "mdi:cash", unit: b.cfg.currency},
+		{key: "next_reminder_due", name: "Next reminder due", icon: "mdi:calendar-clock", deviceClass: "date",
+			attributes: "{{ {'reminder': value_json.next_reminder} | tojson }}"},
+	}
+}
+
+func (b *Bridge) distanceUnit() string {
+	if b.cfg.unit == "miles" {
+		return "mi"
+	}
+	return "km"
+}
+
+// PublishAll publishes discovery config and state for every vehicle that has not been
+// sold or scrapped.
+func (b *Bridge) PublishAll(ctx context.Context) error {
+	vehicles, err := b.queries.ListVehicles(ctx)
+	if err != nil {
+		return fmt.Errorf("list vehicles: %w", err)
+	}
+	for _, v := range vehicles {
+		if fleet.IsArchived(v) {
+			continue
+		}
+		if err := b.publishVehicle(ctx, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PublishVehicle refreshes the discovery config and state of one vehicle, unless it has
+// been sold or scrapped.
+func (b *Bridge) PublishVehicle(ctx context.Context, vehicleID int32) error {
+	v, err := b.queries.GetVehicle(ctx, vehicleID)
+	if err != nil {
+		return fmt.Errorf("get vehicle %d: %w", vehicleID, err)
+	}
+	if fleet.IsArchived(v) {
+		return nil
+	}
+	return b.publishVehicle(ctx, v)
+}
+
+// RemoveVehicle clears a deleted or archived vehicle's retained discovery config, which removes
+// its device from Home Assistant.
+func (b *Bridge) RemoveVehicle(vehicleID int32) error {
+	for _, s := range b.sensors() {
+		if err := b.publish(b.discoveryTopic(vehicleID, s), true, ""); err != nil {
+			return err
+		}
+	}
+	return b.publish(b.stateTopic(vehicleID), true, "")
+}
+
+func (b *Bridge) publishVehicle(ctx context.Context, v repository.Vehicle) error {
+	// Discovery is retained, so republishing it is cheap and keeps names up to date
+	for _, s := range b.sensors() {
+		payload, err := b.discoveryConfig(v, s)
+		if err != nil {
+			return err
+		}
+		if err := b.publish(b.discoveryTopic(v.ID, s), true, payload); err != nil {
+			return err
+		}
+	}
+
+	state, err := b.vehicleState(ctx, v.ID)
+	if err != nil {
+		return fmt.Errorf("state for vehicle %d: %w", v.ID, err)
+	}
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return b.publish(b.stateTopic(v.ID), true, payload)
+}
+
+// discoveryConfig builds the Home Assistant discovery message for one sensor. All of a
+// vehicle's sensors share a device, so they are grouped together in Home Assistant.
+func (b *Bridge) discoveryConfig(v repository.Vehicle, s sensor) ([]byte, error) {
+	objectID := deviceID(v.ID)
+	device := map[string]any{
+		"identifiers": []string{objectID},
+		"name":        v.Name,
+	}
+	if v.Make.Valid && v.Make.String != "" {
+		device["manufacturer"] = v.Make.String
+	}
+	if v.Model.Valid && v.Model.String != "" {
+		device["model"] = v.Model.String
+	}
+	config := map[string]any{
+		"name":               s.name,
+		"unique_id":          objectID + "_" + s.key,
+		"state_topic":        b.stateTopic(v.ID),
+		"value_template":     fmt.Sprintf("{{ value_json.%s }}", s.key),
+		"availability_topic": b.availabilityTopic(),
+		"icon":               s.icon,
+		"device":             device,
+	}
+	if s.unit != "" {
+		config["unit_of_measurement"] = s.unit
+	}
+	if s.deviceClass != "" {
+		config["device_class"] = s.deviceClass
+	}
+	if s.stateClass != "" {
+		config["state_class"] = s.stateClass
+	}
+	if s.attributes != "" {
+		config["json_attributes_topic"] = b.stateTopic(v.ID)
+		config["json_attributes_template"] = s.attributes
+	}
+
+	return json.Marshal(config)
+}
+
+func (b *Bridge) discoveryTopic(vehicleID int32, s sensor) string {
+	return fmt.Sprintf("%s/sensor/%s/%s/config", b.cfg.discoveryPrefix, deviceID(vehicleID), s.key)
+}
+
+func deviceID(vehicleID int32) string {
+	return fmt.Sprintf("axlenote_vehicle_%d", vehicleID)
+}
+
+func (b *Bridge) vehicleState(ctx context.Context, vehicleID int32) (State, error) {
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	telemetry, err := b.queries.GetVehicleTelemetry(ctx, repository.GetVehicleTelemetryParams{
+		VehicleID: vehicleID,
+		Since:     monthStart,
+	})
+	if err != nil {
+		return State{}, err
+	}
+	state := State{
+		Odometer:    telemetry.Odometer,
+		MonthlyCost: telemetry.PeriodCost,
+		UpdatedAt:   now.UTC().Format(time.RFC3339),
+	}
+
+	// Economy of the latest fill: distance since the previous fill over litres filled
+	logs, err := b.queries.ListLatestFuelLogs(ctx, repository.ListLatestFuelLogsParams{
+		VehicleID: sql.NullInt32{Int32: vehicleID, Valid: true},
+		Limit:     2,
+	})
+	if err != nil {
+		return State{}, err
+	}
+	if len(logs) == 2 && logs[0].FullTank.Bool {
+		liters, _ := strconv.ParseFloat(logs[0].Liters, 64)
+		distance := float64(logs[0].Odometer - logs[1].Odometer)
+		if liters > 0 && distance > 0 {
+			economy := math.Round(distance/liters*100) / 100
+			state.FuelEconomy = &economy
+		}
+	}
+
+	reminders, err := b.queries.ListRemindersByVehicle(ctx, sql.NullInt32{Int32: vehicleID, Valid: true})
+	if err != nil {
+		return State{}, err
+	}
+	rate, hasRate, err := forecast.ForVehicle(ctx, b.queries, vehicleID, now)
+	if err != nil {
+		return State{}, err
+	}
+	today := forecast.Today()
+	var next time.Time
+	for _, r := range reminders {
+		due, _, ok := forecast.ReminderDue(r, rate, hasRate, today)
+		if !ok || (!next.IsZero() && !due.Before(next)) {
+			continue
+		}
+		next = due
+		title, date := r.Title, due.Format("2006-01-02")
+		state.NextReminder, state.NextReminderDue = &title, &date
+	}
+	return state, nil
+}
@@ -0,0 +1,265 @@
+// Package mqtt publishes vehicles to Home Assistant through MQTT discovery and accepts
+// odometer readings sent back over MQTT.
+package mqtt
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// publishTimeout bounds how long a single publish may wait for the broker.
+const publishTimeout = 10 * time.Second
+
+type config struct {
+	broker          string
+	username        string
+	password        string
+	clientID        string
+	topicPrefix     string
+	discoveryPrefix string
+	unit            string
+	currency        string
+}
+
+// configFromEnv reads the MQTT_* variables. The bridge is off unless MQTT_BROKER is set.
+func configFromEnv() config {
+	cfg := config{
+		broker:          strings.TrimSpace(os.Getenv("MQTT_BROKER")),
+		username:        os.Getenv("MQTT_USERNAME"),
+		password:        os.Getenv("MQTT_PASSWORD"),
+		clientID:        os.Getenv("MQTT_CLIENT_ID"),
+		topicPrefix:     strings.TrimSuffix(os.Getenv("MQTT_TOPIC_PREFIX"), "/"),
+		discoveryPrefix: strings.TrimSuffix(os.Getenv("MQTT_DISCOVERY_PREFIX"), "/"),
+		unit:            os.Getenv("METRICS_UNIT"),
+		currency:        os.Getenv("APP_CURRENCY"),
+	}
+	if cfg.clientID == "" {
+		// Each replica needs its own ID, or the broker disconnects the others
+		host, _ := os.Hostname()
+		cfg.clientID = "axlenote-" + host
+	}
+	if cfg.topicPrefix == "" {
+		cfg.topicPrefix = "axlenote"
+	}
+	if cfg.discoveryPrefix == "" {
+		cfg.discoveryPrefix = "homeassistant"
+	}
+	if cfg.unit == "" {
+		cfg.unit = "km"
+	}
+	return cfg
+}
+
+// Options hook the bridge into the rest of the application.
+type Options struct {
+	// Accept reports whether this replica should handle inbound messages, so a reading
+	// is stored once even when several replicas are subscribed.
+	Accept func() bool
+	// OnReading is called after an odometer reading received over MQTT has been stored.
+	OnReading func(repository.OdometerReading)
+}
+
+// Bridge keeps a connection to the broker and publishes vehicle state.
+type Bridge struct {
+	cfg     config
+	queries *repository.Queries
+	client  paho.Client
+	opts    Options
+	ctx     context.Context
+}
+
+// New returns a bridge configured from the environment, or nil when MQTT is not configured.
+func New(queries *repository.Queries) *Bridge {
+	cfg := configFromEnv()
+	if cfg.broker == "" {
+		return nil
+	}
+	return &Bridge{cfg: cfg, queries: queries}
+}
+
+// Start connects in the background and keeps reconnecting until Close. Discovery and
+// state are published on every connect, and again whenever Home Assistant restarts.
+func (b *Bridge) Start(ctx context.Context, opts Options) {
+	b.ctx = ctx
+	b.opts = opts
+
+	co := paho.NewClientOptions().
+		AddBroker(b.cfg.broker).
+		SetClientID(b.cfg.clientID).
+		SetUsername(b.cfg.username).
+		SetPassword(b.cfg.password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(10*time.Second).
+		SetWill(b.availabilityTopic(), "offline", 1, true).
+		SetOnConnectHandler(b.onConnect).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			log.Printf("MQTT: Connection lost: %v", err)
+		})
+	b.client = paho.NewClient(co)
+	b.client.Connect()
+	log.Printf("MQTT: Connecting to %s as %s", b.cfg.broker, b.cfg.clientID)
+}
+
+// Close marks the bridge offline and disconnects.
+func (b *Bridge) Close() {
+	if b.client == nil {
+		return
+	}
+	if b.client.IsConnected() {
+		b.client.Publish(b.availabilityTopic(), 1, true, "offline").WaitTimeout(publishTimeout)
+	}
+	b.client.Disconnect(1000)
+}
+
+func (b *Bridge) onConnect(c paho.Client) {
+	log.Printf("MQTT: Connected to %s", b.cfg.broker)
+	if err := b.publish(b.availabilityTopic(), true, "online"); err != nil {
+		log.Printf("MQTT: %v", err)
+	}
+
+	subs := map[string]paho.MessageHandler{
+		b.cfg.topicPrefix + "/vehicle/+/odometer/set": b.handleOdometer,
+		b.cfg.discoveryPrefix + "/status":             b.handleHomeAssistantStatus,
+	}
+	for topic, handler := range subs {
+		if t := c.Subscribe(topic, 1, handler); t.WaitTimeout(publishTimeout) && t.Error() != nil {
+			log.Printf("MQTT: Failed to subscribe to %s: %v", topic, t.Error())
+		}
+	}
+
+	// Publishing needs the connection handler to return first, so run it separately
+	go func() {
+		if err := b.PublishAll(b.ctx); err != nil {
+			log.Printf("MQTT: %v", err)
+		}
+	}()
+}
+
+// handleHomeAssistantStatus republishes everything when Home Assistant comes back
+// online, since it forgets non-retained state on restart.
+func (b *Bridge) handleHomeAssistantStatus(_ paho.Client, msg paho.Message) {
+	if string(msg.Payload()) != "online" || !b.accepting() {
+		return
+	}
+	if err := b.PublishAll(b.ctx); err != nil {
+		log.Printf("MQTT: %v", err)
+	}
+}
+
+// odometerMessage is the JSON form of an inbound reading. A bare number is accepted too.
+type odometerMessage struct {
+	Odometer   int32      `json:"odometer"`
+	RecordedAt *time.Time `json:"recorded_at"`
+}
+
+func (b *Bridge) handleOdometer(_ paho.Client, msg paho.Message) {
+	if msg.Retained() || !b.accepting() {
+		// Retained commands would be replayed on every reconnect
+		return
+	}
+
+	// Topic: <prefix>/vehicle/<id>/odometer/set
+	parts := strings.Split(strings.TrimPrefix(msg.Topic(), b.cfg.topicPrefix+"/"), "/")
+	vehicleID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		log.Printf("MQTT: Ignoring odometer reading on %s: invalid vehicle ID", msg.Topic())
+		return
+	}
+
+	reading, err := parseOdometer(msg.Payload())
+	if err != nil {
+		log.Printf("MQTT: Ignoring odometer reading on %s: %v", msg.Topic(), err)
+		return
+	}
+
+	// Anyone who can publish to the broker can reach this, so only known vehicles still in
+	// use take readings
+	vehicle, err := b.queries.GetVehicle(b.ctx, int32(vehicleID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Printf("MQTT: Ignoring odometer reading on %s: vehicle not found", msg.Topic())
+		} else {
+			log.Printf("MQTT: Failed to fetch vehicle %d: %v", vehicleID, err)
+		}
+		return
+	}
+	if fleet.IsArchived(vehicle) {
+		log.Printf("MQTT: Ignoring odometer reading on %s: vehicle is %s", msg.Topic(), vehicle.Status)
+		return
+	}
+
+	recordedAt := time.Now()
+	if reading.RecordedAt != nil {
+		recordedAt = *reading.RecordedAt
+	}
+
+	saved, err := b.queries.CreateOdometerReading(b.ctx, repository.CreateOdometerReadingParams{
+		VehicleID:  vehicle.ID,
+		Odometer:   reading.Odometer,
+		RecordedAt: recordedAt,
+		Source:     "mqtt",
+	})
+	if err != nil {
+		log.Printf("MQTT: Failed to save odometer reading for vehicle %d: %v", vehicleID, err)
+		return
+	}
+	log.Printf("MQTT: Logged odometer %d for vehicle %d", saved.Odometer, saved.VehicleID)
+
+	if b.opts.OnReading != nil {
+		b.opts.OnReading(saved)
+	}
+}
+
+func parseOdometer(payload []byte) (odometerMessage, error) {
+	var m odometerMessage
+	text := strings.TrimSpace(string(payload))
+	if n, err := strconv.ParseFloat(text, 64); err == nil {
+		m.Odometer = int32(n)
+	} else if err := json.Unmarshal(payload, &m); err != nil {
+		return m, errors.New("payload must be a number or {\"odometer\": ...}")
+	}
+	if m.Odometer <= 0 {
+		return m, errors.New("odometer must be positive")
+	}
+	return m, nil
+}
+
+func (b *Bridge) accepting() bool {
+	return b.opts.Accept == nil || b.opts.Accept()
+}
+
+func (b *Bridge) availabilityTopic() string {
+	return b.cfg.topicPrefix + "/status"
+}
+
+func (b *Bridge) stateTopic(vehicleID int32) string {
+	return fmt.Sprintf("%s/vehicle/%d/state", b.cfg.topicPrefix, vehicleID)
+}
+
+func (b *Bridge) publish(topic string, retained bool, payload any) error {
+	if b.client == nil || !b.client.IsConnectionOpen() {
+		return errors.New("not connected")
+	}
+	t := b.client.Publish(topic, 1, retained, payload)
+	if !t.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("publish to %s timed out", topic)
+	}
+	if err := t.Error(); err != nil {
+		return fmt.Errorf("publish to %s: %w", topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package mqtt
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// message is one publish seen by the test broker.
+type message struct {
+	topic   string
+	payload string
+}
+
+// startBroker runs an embedded broker on a free local port and returns it with its URL
+// and every message published to it.
+func startBroker(t *testing.T) (*mochi.Server, string, <-chan message) {
+	t.Helper()
+	server := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	tcp := listeners.NewTCP(listeners.Config{ID: "test", Address: "127.0.0.1:0"})
+	if err := server.AddListener(tcp); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(chan message, 100)
+	err := server.Subscribe("#", 1, func(_ *mochi.Client, _ packets.Subscription, pk packets.Packet) {
+		seen <- message{topic: pk.TopicName, payload: string(pk.Payload)}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve()
+	t.Cleanup(func() { server.Close() })
+	return server, "tcp://" + tcp.Address(), seen
+}
+
+// startBridge connects a bridge to the broker and returns the readings it stores.
+func startBridge(t *testing.T, broker string, db *fakeDB) <-chan repository.OdometerReading {
+	t.Helper()
+	t.Setenv("MQTT_BROKER", broker)
+	t.Setenv("MQTT_CLIENT_ID", "axlenote-test")
+	t.Setenv("MQTT_TOPIC_PREFIX", "axlenote")
+	t.Setenv("MQTT_DISCOVERY_PREFIX", "homeassistant")
+
+	readings := make(chan repository.OdometerReading, 10)
+	b := New(repository.New(sql.OpenDB(db)))
+	b.Start(context.Background(), Options{
+		OnReading: func(r repository.OdometerReading) { readings <- r },
+	})
+	t.Cleanup(b.Close)
+	return readings
+}
+
+// waitFor returns the payload of the next message on a topic, along with the topics of
+// the messages before it.
+func waitFor(t *testing.T, seen <-chan message, topic string) (string, []string) {
+	t.Helper()
+	var before []string
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case m := <-seen:
+			if m.topic == topic {
+				return m.payload, before
+			}
+			before = append(before, m.topic)
+		case <-timeout:
+			t.Fatalf("no message on %s, saw %v", topic, before)
+		}
+	}
+}
+
+func TestBridgePublishesActiveVehicles(t *testing.T) {
+	_, broker, seen := startBroker(t)
+	db := newFakeDB(
+		repository.Vehicle{ID: 2, Name: "Old Polo", Status: fleet.StatusSold},
+		repository.Vehicle{ID: 1, Name: "Golf", Make: sql.NullString{String: "VW", Valid: true}, Status: fleet.StatusActive},
+	)
+	db.odometer = 42000
+	startBridge(t, broker, db)
+
+	if online, _ := waitFor(t, seen, "axlenote/status"); online != "online" {
+		t.Fatalf("availability = %q, want online", online)
+	}
+
+	config, _ := waitFor(t, seen, "homeassistant/sensor/axlenote_vehicle_1/odometer/config")
+	var discovery struct {
+		StateTopic string `json:"state_topic"`
+		Device     struct {
+			Name         string `json:"name"`
+			Manufacturer string `json:"manufacturer"`
+		} `json:"device"`
+	}
+	if err := json.Unmarshal([]byte(config), &discovery); err != nil {
+		t.Fatalf("discovery config: %v", err)
+	}
+	if discovery.StateTopic != "axlenote/vehicle/1/state" || discovery.Device.Name != "Golf" || discovery.Device.Manufacturer != "VW" {
+		t.Errorf("discovery config = %s", config)
+	}
+
+	payload, before := waitFor(t, seen, "axlenote/vehicle/1/state")
+	var state State
+	if err := json.Unmarshal([]byte(payload), &state); err != nil {
+		t.Fatalf("state: %v", err)
+	}
+	if state.Odometer != 42000 {
+		t.Errorf("state odometer = %d, want 42000", state.Odometer)
+	}
+
+	// The sold vehicle comes first in the list, so it would have been published by now
+	for _, topic := range before {
+		if strings.Contains(topic, "vehicle_2") || strings.Contains(topic, "vehicle/2") {
+			t.Errorf("sold vehicle was published on %s", topic)
+		}
+	}
+}
+
+func TestBridgeStoresOdometerReadings(t *testing.T) {
+	server, broker, seen := startBroker(t)
+	db := newFakeDB(
+		repository.Vehicle{ID: 1, Name: "Golf", Status: fleet.StatusActive},
+		repository.Vehicle{ID: 2, Name: "Old Polo", Status: fleet.StatusScrapped},
+	)
+
+	// A retained command is delivered when the bridge subscribes, and must not be stored
+	if err := server.Publish("axlenote/vehicle/1/odometer/set", []byte("500"), true, 1); err != nil {
+		t.Fatal(err)
+	}
+	readings := startBridge(t, broker, db)
+	// Vehicles are published once the subscriptions are in place
+	waitFor(t, seen, "axlenote/vehicle/1/state")
+
+	ignored := []message{
+		{"axlenote/vehicle/99/odometer/set", "12000"},        // unknown vehicle
+		{"axlenote/vehicle/2/odometer/set", "12000"},         // scrapped
+		{"axlenote/vehicle/golf/odometer/set", "12000"},      // invalid ID
+		{"axlenote/vehicle/1/odometer/set", "-5"},            // not positive
+		{"axlenote/vehicle/1/odometer/set", `{"km": 12000}`}, // no odometer
+	}
+	for _, m := range append(ignored,
+		message{"axlenote/vehicle/1/odometer/set", "12345.6"},
+		message{"axlenote/vehicle/1/odometer/set", `{"odometer": 12400, "recorded_at": "2024-05-01T08:00:00Z"}`},
+	) {
+		if err := server.Publish(m.topic, []byte(m.payload), false, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Messages are handled in order, so the ignored ones have been dealt with by now
+	for _, want := range []int32{12345, 12400} {
+		select {
+		case r := <-readings:
+			if r.VehicleID != 1 || r.Odometer != want || r.Source != "mqtt" {
+				t.Errorf("stored reading %+v, want %d km for vehicle 1 from mqtt", r, want)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("reading of %d was not stored", want)
+		}
+	}
+	if got := db.stored(); len(got) != 2 {
+		t.Fatalf("stored %d readings, want 2: %+v", len(got), got)
+	}
+	if at := db.stored()[1].RecordedAt; !at.Equal(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("recorded_at = %s, want the one sent", at)
+	}
+}
+
+// fakeDB answers the few queries the bridge runs, so it can be tested without Postgres.
+// Other queries return no rows.
+type fakeDB struct {
+	vehicles []repository.Vehicle
+	odometer int32
+
+	mu       sync.Mutex
+	readings []repository.OdometerReading
+}
+
+func newFakeDB(vehicles ...repository.Vehicle) *fakeDB {
+	return &fakeDB{vehicles: vehicles}
+}
+
+func (db *fakeDB) stored() []repository.OdometerReading {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return slices.Clone(db.readings)
+}
+
+func (db *fakeDB) query(name string, args []driver.NamedValue) ([]any, error) {
+	switch name {
+	case "ListVehicles":
+		rows := make([]any, len(db.vehicles))
+		for i, v := range db.vehicles {
+			rows[i] = v
+		}
+		return rows, nil
+	case "GetVehicle":
+		for _, v := range db.vehicles {
+			if int64(v.ID) == args[0].Value.(int64) {
+				return []any{v}, nil
+			}
+		}
+		return nil, nil
+	case "GetVehicleTelemetry":
+		return []any{repository.GetVehicleTelemetryRow{Odometer: db.odometer}}, nil
+	case "CreateOdometerReading":
+		db.mu.Lock()
+		defer db.mu.Unlock()
+		r := repository.OdometerReading{
+			ID:         int32(len(db.readings) + 1),
+			VehicleID:  int32(args[0].Value.(int64)),
+			Odometer:   int32(args[1].Value.(int64)),
+			RecordedAt: args[2].Value.(time.Time),
+			Source:     args[3].Value.(string),
+			CreatedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+		}
+		db.readings = append(db.readings, r)
+		return []any{r}, nil
+	}
+	return nil, nil
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	// sqlc starts every query with "-- name: <Name> :<kind>"
+	name, _, _ := strings.Cut(strings.TrimPrefix(query, "-- name: "), " ")
+	rows, err := c.db.query(name, args)
+	if err != nil {
+		return nil, err
+	}
+	return newFakeRows(rows)
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+// fakeRows returns structs as rows, one column per field in declaration order, which is
+// the order sqlc scans them in.
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func newFakeRows(structs []any) (*fakeRows, error) {
+	r := &fakeRows{}
+	for _, s := range structs {
+		v := reflect.ValueOf(s)
+		row := make([]driver.Value, v.NumField())
+		for i := range row {
+			value, err := driver.DefaultParameterConverter.ConvertValue(v.Field(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			row[i] = value
+		}
+		r.rows = append(r.rows, row)
+		if r.columns == nil {
+			for i := range row {
+				r.columns = append(r.columns, v.Type().Field(i).Name)
+			}
+		}
+	}
+	return r, nil
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
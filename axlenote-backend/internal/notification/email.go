@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+const mimeBoundary = "axlenote-alternative-boundary"
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func smtpConfigFromEnv() SMTPConfig {
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	return SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+func (s *Service) sendEmail(to string, m Message) error {
+	if s.SMTP.Host == "" || s.SMTP.From == "" {
+		return errors.New("email notifications require SMTP_HOST and SMTP_FROM")
+	}
+	if to == "" {
+		return errors.New("no email address for recipient")
+	}
+	// Line breaks would let an address or title inject headers or a body
+	if strings.ContainsAny(to, "\r\n") {
+		return errors.New("email address contains a line break")
+	}
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("invalid email address: %w", err)
+	}
+	title := strings.Join(strings.Fields(m.Title), " ")
+
+	headers := []string{
+		"From: " + s.SMTP.From,
+		"To: " + rcpt.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", title),
+		"MIME-Version: 1.0",
+	}
+
+	var msg string
+	if m.HTML == "" {
+		msg = strings.Join(append(headers,
+			"Content-Type: text/plain; charset=UTF-8",
+			"",
+			m.Body,
+		), "\r\n")
+	} else {
+		// Send both parts so clients without HTML support still get something readable
+		msg = strings.Join(append(headers,
+			"Content-Type: multipart/alternative; boundary="+mimeBoundary,
+			"",
+			"--"+mimeBoundary,
+			"Content-Type: text/plain; charset=UTF-8",
+			"",
+			m.Body,
+			"--"+mimeBoundary,
+			"Content-Type: text/html; charset=UTF-8",
+			"",
+			m.HTML,
+			"--"+mimeBoundary+"--",
+		), "\r\n")
+	}
+
+	var auth smtp.Auth
+	if s.SMTP.Username != "" {
+		auth = smtp.PlainAuth("", s.SMTP.Username, s.SMTP.Password, s.SMTP.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%s", s.SMTP.Host, s.SMTP.Port)
+	return smtp.SendMail(addr, auth, s.SMTP.From, []string{rcpt.Address}, []byte(msg))
+}
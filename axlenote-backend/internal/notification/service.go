@@ -7,10 +7,43 @@ import (
 	"os"
 )
 
+// Delivery channels a subscription can use.
+const (
+	ChannelNtfy  = "ntfy"
+	ChannelEmail = "email"
+)
+
+// Trigger levels a subscription can filter on.
+const (
+	TriggerOverdue  = "overdue"
+	TriggerUpcoming = "upcoming"
+)
+
+// Digest modes for a subscription.
+const (
+	DigestNone   = "none"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// Message is a notification body. HTML is optional and only used by channels that can render it.
+type Message struct {
+	Title string
+	Body  string
+	HTML  string
+}
+
+// Target is where a single notification is delivered: an ntfy topic or an email address.
+type Target struct {
+	Channel string
+	Address string
+}
+
 type Service struct {
 	BaseURL string
 	Topic   string
 	Enabled bool
+	SMTP    SMTPConfig
 }
 
 func New() *Service {
@@ -18,15 +51,42 @@ func New() *Service {
 		BaseURL: os.Getenv("NOTIFY_BASE_URL"),
 		Topic:   os.Getenv("NOTIFY_TOPIC"),
 		Enabled: os.Getenv("NOTIFY_ENABLED") == "true",
+		SMTP:    smtpConfigFromEnv(),
 	}
 }
 
+// Send posts a message to the default ntfy topic.
 func (s *Service) Send(title, message string) error {
+	return s.Deliver(Target{Channel: ChannelNtfy, Address: s.Topic}, title, message)
+}
+
+// Deliver sends a plain-text message to a specific target.
+func (s *Service) Deliver(target Target, title, message string) error {
+	return s.DeliverMessage(target, Message{Title: title, Body: message})
+}
+
+// DeliverMessage sends a message to a specific target. An empty ntfy topic falls back to the default topic.
+func (s *Service) DeliverMessage(target Target, msg Message) error {
 	if !s.Enabled {
 		return nil
 	}
 
-	url := fmt.Sprintf("%s/%s", s.BaseURL, s.Topic)
+	switch target.Channel {
+	case ChannelNtfy, "":
+		topic := target.Address
+		if topic == "" {
+			topic = s.Topic
+		}
+		return s.sendNtfy(topic, msg.Title, msg.Body)
+	case ChannelEmail:
+		return s.sendEmail(target.Address, msg)
+	default:
+		return fmt.Errorf("unknown notification channel: %s", target.Channel)
+	}
+}
+
+func (s *Service) sendNtfy(topic, title, message string) error {
+	url := fmt.Sprintf("%s/%s", s.BaseURL, topic)
 	req, err := http.NewRequest("POST", url, bytes.NewBufferString(message))
 	if err != nil {
 		return err
@@ -48,3 +108,15 @@ func (s *Service) Send(title, message string) error {
 
 	return nil
 }
+
+func ValidChannel(channel string) bool {
+	return channel == ChannelNtfy || channel == ChannelEmail
+}
+
+func ValidTrigger(trigger string) bool {
+	return trigger == TriggerOverdue || trigger == TriggerUpcoming
+}
+
+func ValidDigest(digest string) bool {
+	return digest == DigestNone || digest == DigestDaily || digest == DigestWeekly
+}
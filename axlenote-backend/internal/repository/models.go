@@ -6,20 +6,113 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
-type Document struct {
+type Budget struct {
 	ID         int32
 	VehicleID  sql.NullInt32
-	Name       string
-	Type       sql.NullString
-	FileUrl    string
-	ExpiryDate sql.NullTime
-	Notes      sql.NullString
+	Category   string
+	Period     string
+	Amount     string
+	Thresholds []int32
+	CreatedAt  sql.NullTime
+}
+
+type BudgetAlert struct {
+	BudgetID    int32
+	PeriodStart time.Time
+	Threshold   int32
+	CreatedAt   sql.NullTime
+}
+
+type Document struct {
+	ID                 int32
+	VehicleID          sql.NullInt32
+	Name               string
+	Type               sql.NullString
+	FileUrl            string
+	ExpiryDate         sql.NullTime
+	Notes              sql.NullString
+	CreatedAt          sql.NullTime
+	ArchivedAt         sql.NullTime
+	PreviousVersionID  sql.NullInt32
+	Category           sql.NullString
+	IssueDate          sql.NullTime
+	Issuer             sql.NullString
+	PolicyNumber       sql.NullString
+	RegistrationNumber sql.NullString
+	Tags               []string
+}
+
+type DocumentAlert struct {
+	DocumentID int32
+	ExpiryDate time.Time
+	Level      string
+	Target     string
 	CreatedAt  sql.NullTime
 }
 
+type DocumentCategory struct {
+	Slug         string
+	Name         string
+	TracksExpiry bool
+	CreatedAt    sql.NullTime
+}
+
+type DocumentFile struct {
+	ID              int32
+	DocumentID      int32
+	Label           sql.NullString
+	FileName        string
+	ContentType     string
+	SizeBytes       int64
+	StorageKey      string
+	Position        int32
+	CreatedAt       sql.NullTime
+	ThumbnailStatus string
+	ThumbnailError  sql.NullString
+}
+
+type Expense struct {
+	ID                 int32
+	VehicleID          int32
+	VendorID           sql.NullInt32
+	Category           string
+	Date               time.Time
+	Amount             string
+	Odometer           sql.NullInt32
+	Description        sql.NullString
+	Recurrence         string
+	RecurrenceEndDate  sql.NullTime
+	NextDueDate        sql.NullTime
+	RecurringExpenseID sql.NullInt32
+	CreatedAt          sql.NullTime
+}
+
+type ExpenseFile struct {
+	ID          int32
+	ExpenseID   int32
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+	StorageKey  string
+	CreatedAt   sql.NullTime
+}
+
+type FixedCost struct {
+	ID        int32
+	VehicleID int32
+	Category  string
+	Amount    string
+	Frequency string
+	StartDate time.Time
+	EndDate   sql.NullTime
+	Notes     sql.NullString
+	CreatedAt sql.NullTime
+}
+
 type FuelLog struct {
 	ID            int32
 	VehicleID     sql.NullInt32
@@ -33,6 +126,77 @@ type FuelLog struct {
 	CreatedAt     sql.NullTime
 }
 
+type JobRun struct {
+	ID         int32
+	JobName    string
+	Trigger    string
+	Status     string
+	Error      sql.NullString
+	StartedAt  time.Time
+	FinishedAt sql.NullTime
+}
+
+type Loan struct {
+	ID           int32
+	VehicleID    int32
+	Lender       sql.NullString
+	Principal    string
+	AnnualRate   string
+	TenureMonths int32
+	Emi          string
+	StartDate    time.Time
+	FirstEmiDate time.Time
+	Notes        sql.NullString
+	CreatedAt    sql.NullTime
+}
+
+type LoanPayment struct {
+	ID         int32
+	LoanID     int32
+	Date       time.Time
+	Amount     string
+	Prepayment bool
+	Notes      sql.NullString
+	CreatedAt  sql.NullTime
+}
+
+type LoanReminder struct {
+	LoanID    int32
+	DueDate   time.Time
+	CreatedAt sql.NullTime
+}
+
+type NotificationSubscription struct {
+	ID            int32
+	UserID        int32
+	VehicleID     sql.NullInt32
+	Channel       string
+	ReminderTypes []string
+	Triggers      []string
+	LeadDays      int32
+	Digest        string
+	Enabled       bool
+	CreatedAt     sql.NullTime
+}
+
+type NotificationTemplate struct {
+	ID        int32
+	UserID    int32
+	Kind      string
+	Title     string
+	Body      string
+	UpdatedAt sql.NullTime
+}
+
+type OdometerReading struct {
+	ID         int32
+	VehicleID  int32
+	Odometer   int32
+	RecordedAt time.Time
+	Source     string
+	CreatedAt  sql.NullTime
+}
+
 type Part struct {
 	ID              int32
 	ServiceRecordID sql.NullInt32
@@ -56,30 +220,110 @@ type Reminder struct {
 	IsCompleted    sql.NullBool
 	CreatedAt      sql.NullTime
 	Type           sql.NullString
+	CompletedAt    sql.NullTime
 }
 
 type ServiceRecord struct {
+	ID            int32
+	VehicleID     sql.NullInt32
+	Date          time.Time
+	Odometer      int32
+	Cost          string
+	Notes         sql.NullString
+	ServiceType   sql.NullString
+	CreatedAt     sql.NullTime
+	DocumentUrl   sql.NullString
+	CompletedDate sql.NullTime
+}
+
+type User struct {
+	ID        int32
+	Name      string
+	Email     sql.NullString
+	NtfyTopic sql.NullString
+	CreatedAt sql.NullTime
+	Locale    string
+	FeedToken sql.NullString
+}
+
+type Vehicle struct {
+	ID                int32
+	Name              string
+	Make              sql.NullString
+	Model             sql.NullString
+	Year              sql.NullInt32
+	Type              sql.NullString
+	Vin               sql.NullString
+	LicensePlate      sql.NullString
+	ImageUrl          sql.NullString
+	CreatedAt         sql.NullTime
+	UpdatedAt         sql.NullTime
+	FuelType          sql.NullString
+	DistanceUnit      sql.NullString
+	PurchasePrice     sql.NullString
+	PurchaseDate      sql.NullTime
+	DepreciationModel sql.NullString
+	DepreciationRate  sql.NullString
+	ResidualValue     sql.NullString
+	Status            string
+	SaleDate          sql.NullTime
+	SalePrice         sql.NullString
+	BuyerNotes        sql.NullString
+}
+
+type VehicleInsight struct {
+	ID            int32
+	VehicleID     int32
+	Kind          string
+	FuelLogID     sql.NullInt32
+	Baseline      float64
+	Recent        float64
+	ChangePercent float64
+	ZScore        float64
+	CreatedAt     time.Time
+	ResolvedAt    sql.NullTime
+}
+
+type VehicleValuation struct {
+	ID        int32
+	VehicleID int32
+	Date      time.Time
+	Value     string
+	Source    sql.NullString
+	Notes     sql.NullString
+	CreatedAt sql.NullTime
+}
+
+type Vendor struct {
+	ID        int32
+	Name      string
+	Website   sql.NullString
+	Phone     sql.NullString
+	Address   sql.NullString
+	Notes     sql.NullString
+	CreatedAt sql.NullTime
+}
+
+type Webhook struct {
 	ID          int32
-	VehicleID   sql.NullInt32
-	Date        time.Time
-	Odometer    int32
-	Cost        string
-	Notes       sql.NullString
-	ServiceType sql.NullString
+	Url         string
+	Secret      string
+	EventTypes  []string
+	Description sql.NullString
+	Enabled     bool
 	CreatedAt   sql.NullTime
-	DocumentUrl sql.NullString
 }
 
-type Vehicle struct {
-	ID           int32
-	Name         string
-	Make         sql.NullString
-	Model        sql.NullString
-	Year         sql.NullInt32
-	Type         sql.NullString
-	Vin          sql.NullString
-	LicensePlate sql.NullString
-	ImageUrl     sql.NullString
-	CreatedAt    sql.NullTime
-	UpdatedAt    sql.NullTime
+type WebhookDelivery struct {
+	ID             int32
+	WebhookID      int32
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	ResponseStatus sql.NullInt32
+	Error          sql.NullString
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
 }
@@ -8,11 +8,103 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/lib/pq"
+)
+
+const archiveDocument = `-- name: ArchiveDocument :one
+UPDATE documents
+SET archived_at = NOW()
+WHERE id = $1 AND archived_at IS NULL
+RETURNING id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags
+`
+
+func (q *Queries) ArchiveDocument(ctx context.Context, id int32) (Document, error) {
+	row := q.db.QueryRowContext(ctx, archiveDocument, id)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Name,
+		&i.Type,
+		&i.FileUrl,
+		&i.ExpiryDate,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.PreviousVersionID,
+		&i.Category,
+		&i.IssueDate,
+		&i.Issuer,
+		&i.PolicyNumber,
+		&i.RegistrationNumber,
+		pq.Array(&i.Tags),
+	)
+	return i, err
+}
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET next_attempt_at = NOW() + make_interval(secs => $1::int)
+WHERE id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at ASC
+    LIMIT $2::int
+    FOR UPDATE SKIP LOCKED
 )
+RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, response_status, error, created_at, delivered_at
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseSeconds int32
+	Batch        int32
+}
+
+// Leases due deliveries so concurrent workers don't send the same one twice.
+// A delivery whose worker dies is picked up again once the lease expires.
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebhookDeliveries,
+		arg.LeaseSeconds,
+		arg.Batch,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.ResponseStatus,
+			&i.Error,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
 const completeReminder = `-- name: CompleteReminder :exec
-UPDATE reminders SET is_completed = TRUE WHERE id = $1
+UPDATE reminders SET is_completed = TRUE, completed_at = NOW() WHERE id = $1
 `
 
 func (q *Queries) CompleteReminder(ctx context.Context, id int32) error {
@@ -20,19 +112,408 @@ func (q *Queries) CompleteReminder(ctx context.Context, id int32) error {
 	return err
 }
 
+const countActivity = `-- name: CountActivity :one
+WITH activity AS (
+    SELECT 'fuel_log' AS type, 1 AS type_rank, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC' AS occurred_at,
+           'Refuelled ' || liters::text || ' L' AS title, total_cost::float8 AS amount, odometer
+    FROM fuel_logs
+    UNION ALL
+    SELECT 'service_record', 2, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(COALESCE(NULLIF(service_type, ''), 'service')), cost::float8, odometer
+    FROM service_records
+    UNION ALL
+    SELECT 'reminder_completed', 3, id, vehicle_id, completed_at, title, 0, due_odometer
+    FROM reminders
+    WHERE completed_at IS NOT NULL
+    UNION ALL
+    SELECT 'document_upload', 4, f.id, d.vehicle_id, f.created_at,
+           d.name || ': ' || f.file_name, 0, NULL
+    FROM document_files f
+    JOIN documents d ON d.id = f.document_id
+    UNION ALL
+    SELECT 'expense', 5, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(category) || COALESCE(': ' || NULLIF(description, ''), ''), amount::float8, odometer
+    FROM expenses
+), keyed AS (
+
+
+    SELECT *, (FLOOR(EXTRACT(EPOCH FROM occurred_at) * 1000) * 8 + type_rank)::float8 AS sort_key
+    FROM activity
+    WHERE (COALESCE(cardinality($1::int[]), 0) = 0 OR vehicle_id = ANY($1::int[]))
+      AND (COALESCE(cardinality($2::text[]), 0) = 0 OR type = ANY($2::text[]))
+      AND ($3::date IS NULL OR occurred_at >= $3::date)
+      AND ($4::date IS NULL OR occurred_at < $4::date + 1)
+)
+SELECT COUNT(*) FROM keyed
+`
+
+type CountActivityParams struct {
+	VehicleIds []int32
+	Types      []string
+	DateFrom   sql.NullTime
+	DateTo     sql.NullTime
+}
+
+func (q *Queries) CountActivity(ctx context.Context, arg CountActivityParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActivity,
+		pq.Array(arg.VehicleIds),
+		pq.Array(arg.Types),
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countDocuments = `-- name: CountDocuments :one
+SELECT COUNT(*) FROM documents
+WHERE ($1::int IS NULL OR vehicle_id = $1::int)
+  AND ($2::text IS NULL OR category = $2::text)
+  AND ($3::text IS NULL OR $3::text = ANY(tags))
+  AND ($4::date IS NULL OR expiry_date >= $4::date)
+  AND ($5::date IS NULL OR expiry_date <= $5::date)
+  AND ($6::text IS NULL
+       OR name ILIKE '%' || $6::text || '%'
+       OR issuer ILIKE '%' || $6::text || '%'
+       OR policy_number ILIKE '%' || $6::text || '%'
+       OR registration_number ILIKE '%' || $6::text || '%'
+       OR notes ILIKE '%' || $6::text || '%')
+  AND ($7::bool OR archived_at IS NULL)
+`
+
+type CountDocumentsParams struct {
+	VehicleID       sql.NullInt32
+	Category        sql.NullString
+	Tag             sql.NullString
+	ExpiresFrom     sql.NullTime
+	ExpiresTo       sql.NullTime
+	Query           sql.NullString
+	IncludeArchived bool
+}
+
+func (q *Queries) CountDocuments(ctx context.Context, arg CountDocumentsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDocuments,
+		arg.VehicleID,
+		arg.Category,
+		arg.Tag,
+		arg.ExpiresFrom,
+		arg.ExpiresTo,
+		arg.Query,
+		arg.IncludeArchived,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countExpensesByVehicle = `-- name: CountExpensesByVehicle :one
+SELECT COUNT(*) FROM expenses
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR amount >= $6::float8)
+  AND ($7::float8 IS NULL OR amount <= $7::float8)
+  AND ($8::text IS NULL OR category = $8::text)
+  AND ($9::int IS NULL OR vendor_id = $9::int)
+`
+
+type CountExpensesByVehicleParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	Category    sql.NullString
+	VendorID    sql.NullInt32
+}
+
+func (q *Queries) CountExpensesByVehicle(ctx context.Context, arg CountExpensesByVehicleParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countExpensesByVehicle,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.Category,
+		arg.VendorID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFuelLogsByVehicle = `-- name: CountFuelLogsByVehicle :one
+SELECT COUNT(*) FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+`
+
+type CountFuelLogsByVehicleParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+}
+
+func (q *Queries) CountFuelLogsByVehicle(ctx context.Context, arg CountFuelLogsByVehicleParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFuelLogsByVehicle,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countJobRuns = `-- name: CountJobRuns :one
+SELECT COUNT(*) FROM job_runs
+WHERE ($1::text IS NULL OR job_name = $1::text)
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::date IS NULL OR started_at >= $3::date)
+  AND ($4::date IS NULL OR started_at < $4::date + 1)
+`
+
+type CountJobRunsParams struct {
+	JobName  sql.NullString
+	Status   sql.NullString
+	DateFrom sql.NullTime
+	DateTo   sql.NullTime
+}
+
+func (q *Queries) CountJobRuns(ctx context.Context, arg CountJobRunsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countJobRuns,
+		arg.JobName,
+		arg.Status,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOdometerReadingsByVehicle = `-- name: CountOdometerReadingsByVehicle :one
+SELECT COUNT(*) FROM odometer_readings
+WHERE vehicle_id = $1::int
+  AND ($2::text IS NULL OR source = $2::text)
+  AND ($3::date IS NULL OR recorded_at >= $3::date)
+  AND ($4::date IS NULL OR recorded_at < $4::date + 1)
+  AND ($5::int IS NULL OR odometer >= $5::int)
+  AND ($6::int IS NULL OR odometer <= $6::int)
+`
+
+type CountOdometerReadingsByVehicleParams struct {
+	VehicleID   int32
+	Source      sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+}
+
+func (q *Queries) CountOdometerReadingsByVehicle(ctx context.Context, arg CountOdometerReadingsByVehicleParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOdometerReadingsByVehicle,
+		arg.VehicleID,
+		arg.Source,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countRemindersPage = `-- name: CountRemindersPage :one
+SELECT COUNT(*) FROM reminders
+WHERE vehicle_id = $1::int
+  AND ($2::bool IS NULL OR is_completed = $2::bool)
+  AND ($3::text IS NULL OR type = $3::text)
+  AND ($4::date IS NULL OR due_date >= $4::date)
+  AND ($5::date IS NULL OR due_date <= $5::date)
+  AND ($6::int IS NULL OR due_odometer >= $6::int)
+  AND ($7::int IS NULL OR due_odometer <= $7::int)
+`
+
+type CountRemindersPageParams struct {
+	VehicleID   int32
+	IsCompleted sql.NullBool
+	Type        sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+}
+
+func (q *Queries) CountRemindersPage(ctx context.Context, arg CountRemindersPageParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRemindersPage,
+		arg.VehicleID,
+		arg.IsCompleted,
+		arg.Type,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countServiceRecordsByVehicle = `-- name: CountServiceRecordsByVehicle :one
+SELECT COUNT(*) FROM service_records
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR cost >= $6::float8)
+  AND ($7::float8 IS NULL OR cost <= $7::float8)
+  AND ($8::text IS NULL OR service_type = $8::text)
+`
+
+type CountServiceRecordsByVehicleParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	ServiceType sql.NullString
+}
+
+func (q *Queries) CountServiceRecordsByVehicle(ctx context.Context, arg CountServiceRecordsByVehicleParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countServiceRecordsByVehicle,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.ServiceType,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWebhookDeliveries = `-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) FROM webhook_deliveries
+WHERE webhook_id = $1::int
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::text IS NULL OR event_type = $3::text)
+  AND ($4::date IS NULL OR created_at >= $4::date)
+  AND ($5::date IS NULL OR created_at < $5::date + 1)
+`
+
+type CountWebhookDeliveriesParams struct {
+	WebhookID int32
+	Status    sql.NullString
+	EventType sql.NullString
+	DateFrom  sql.NullTime
+	DateTo    sql.NullTime
+}
+
+func (q *Queries) CountWebhookDeliveries(ctx context.Context, arg CountWebhookDeliveriesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWebhookDeliveries,
+		arg.WebhookID,
+		arg.Status,
+		arg.EventType,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createBudget = `-- name: CreateBudget :one
+INSERT INTO budgets (vehicle_id, category, period, amount, thresholds)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, vehicle_id, category, period, amount, thresholds, created_at
+`
+
+type CreateBudgetParams struct {
+	VehicleID  sql.NullInt32
+	Category   string
+	Period     string
+	Amount     string
+	Thresholds []int32
+}
+
+func (q *Queries) CreateBudget(ctx context.Context, arg CreateBudgetParams) (Budget, error) {
+	row := q.db.QueryRowContext(ctx, createBudget,
+		arg.VehicleID,
+		arg.Category,
+		arg.Period,
+		arg.Amount,
+		pq.Array(arg.Thresholds),
+	)
+	var i Budget
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Category,
+		&i.Period,
+		&i.Amount,
+		pq.Array(&i.Thresholds),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createDocument = `-- name: CreateDocument :one
-INSERT INTO documents (vehicle_id, name, type, file_url, expiry_date, notes)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, vehicle_id, name, type, file_url, expiry_date, notes, created_at
+INSERT INTO documents (
+  vehicle_id, name, type, file_url, expiry_date, notes, previous_version_id,
+  category, issue_date, issuer, policy_number, registration_number, tags
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags
 `
 
 type CreateDocumentParams struct {
-	VehicleID  sql.NullInt32
-	Name       string
-	Type       sql.NullString
-	FileUrl    string
-	ExpiryDate sql.NullTime
-	Notes      sql.NullString
+	VehicleID          sql.NullInt32
+	Name               string
+	Type               sql.NullString
+	FileUrl            string
+	ExpiryDate         sql.NullTime
+	Notes              sql.NullString
+	PreviousVersionID  sql.NullInt32
+	Category           sql.NullString
+	IssueDate          sql.NullTime
+	Issuer             sql.NullString
+	PolicyNumber       sql.NullString
+	RegistrationNumber sql.NullString
+	Tags               []string
 }
 
 func (q *Queries) CreateDocument(ctx context.Context, arg CreateDocumentParams) (Document, error) {
@@ -43,6 +524,13 @@ func (q *Queries) CreateDocument(ctx context.Context, arg CreateDocumentParams)
 		arg.FileUrl,
 		arg.ExpiryDate,
 		arg.Notes,
+		arg.PreviousVersionID,
+		arg.Category,
+		arg.IssueDate,
+		arg.Issuer,
+		arg.PolicyNumber,
+		arg.RegistrationNumber,
+		pq.Array(arg.Tags),
 	)
 	var i Document
 	err := row.Scan(
@@ -54,6 +542,212 @@ func (q *Queries) CreateDocument(ctx context.Context, arg CreateDocumentParams)
 		&i.ExpiryDate,
 		&i.Notes,
 		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.PreviousVersionID,
+		&i.Category,
+		&i.IssueDate,
+		&i.Issuer,
+		&i.PolicyNumber,
+		&i.RegistrationNumber,
+		pq.Array(&i.Tags),
+	)
+	return i, err
+}
+
+const createDocumentCategory = `-- name: CreateDocumentCategory :one
+INSERT INTO document_categories (slug, name, tracks_expiry)
+VALUES ($1, $2, $3)
+RETURNING slug, name, tracks_expiry, created_at
+`
+
+type CreateDocumentCategoryParams struct {
+	Slug         string
+	Name         string
+	TracksExpiry bool
+}
+
+func (q *Queries) CreateDocumentCategory(ctx context.Context, arg CreateDocumentCategoryParams) (DocumentCategory, error) {
+	row := q.db.QueryRowContext(ctx, createDocumentCategory,
+		arg.Slug,
+		arg.Name,
+		arg.TracksExpiry,
+	)
+	var i DocumentCategory
+	err := row.Scan(
+		&i.Slug,
+		&i.Name,
+		&i.TracksExpiry,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createDocumentFile = `-- name: CreateDocumentFile :one
+INSERT INTO document_files (document_id, label, file_name, content_type, size_bytes, storage_key, position)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, document_id, label, file_name, content_type, size_bytes, storage_key, position, created_at, thumbnail_status, thumbnail_error
+`
+
+type CreateDocumentFileParams struct {
+	DocumentID  int32
+	Label       sql.NullString
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+	StorageKey  string
+	Position    int32
+}
+
+func (q *Queries) CreateDocumentFile(ctx context.Context, arg CreateDocumentFileParams) (DocumentFile, error) {
+	row := q.db.QueryRowContext(ctx, createDocumentFile,
+		arg.DocumentID,
+		arg.Label,
+		arg.FileName,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.StorageKey,
+		arg.Position,
+	)
+	var i DocumentFile
+	err := row.Scan(
+		&i.ID,
+		&i.DocumentID,
+		&i.Label,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.Position,
+		&i.CreatedAt,
+		&i.ThumbnailStatus,
+		&i.ThumbnailError,
+	)
+	return i, err
+}
+
+const createExpense = `-- name: CreateExpense :one
+INSERT INTO expenses (vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date, recurring_expense_id, created_at
+`
+
+type CreateExpenseParams struct {
+	VehicleID         int32
+	VendorID          sql.NullInt32
+	Category          string
+	Date              time.Time
+	Amount            string
+	Odometer          sql.NullInt32
+	Description       sql.NullString
+	Recurrence        string
+	RecurrenceEndDate sql.NullTime
+	NextDueDate       sql.NullTime
+}
+
+func (q *Queries) CreateExpense(ctx context.Context, arg CreateExpenseParams) (Expense, error) {
+	row := q.db.QueryRowContext(ctx, createExpense,
+		arg.VehicleID,
+		arg.VendorID,
+		arg.Category,
+		arg.Date,
+		arg.Amount,
+		arg.Odometer,
+		arg.Description,
+		arg.Recurrence,
+		arg.RecurrenceEndDate,
+		arg.NextDueDate,
+	)
+	var i Expense
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.VendorID,
+		&i.Category,
+		&i.Date,
+		&i.Amount,
+		&i.Odometer,
+		&i.Description,
+		&i.Recurrence,
+		&i.RecurrenceEndDate,
+		&i.NextDueDate,
+		&i.RecurringExpenseID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createExpenseFile = `-- name: CreateExpenseFile :one
+INSERT INTO expense_files (expense_id, file_name, content_type, size_bytes, storage_key)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, expense_id, file_name, content_type, size_bytes, storage_key, created_at
+`
+
+type CreateExpenseFileParams struct {
+	ExpenseID   int32
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+	StorageKey  string
+}
+
+func (q *Queries) CreateExpenseFile(ctx context.Context, arg CreateExpenseFileParams) (ExpenseFile, error) {
+	row := q.db.QueryRowContext(ctx, createExpenseFile,
+		arg.ExpenseID,
+		arg.FileName,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.StorageKey,
+	)
+	var i ExpenseFile
+	err := row.Scan(
+		&i.ID,
+		&i.ExpenseID,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createFixedCost = `-- name: CreateFixedCost :one
+INSERT INTO fixed_costs (vehicle_id, category, amount, frequency, start_date, end_date, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, vehicle_id, category, amount, frequency, start_date, end_date, notes, created_at
+`
+
+type CreateFixedCostParams struct {
+	VehicleID int32
+	Category  string
+	Amount    string
+	Frequency string
+	StartDate time.Time
+	EndDate   sql.NullTime
+	Notes     sql.NullString
+}
+
+func (q *Queries) CreateFixedCost(ctx context.Context, arg CreateFixedCostParams) (FixedCost, error) {
+	row := q.db.QueryRowContext(ctx, createFixedCost,
+		arg.VehicleID,
+		arg.Category,
+		arg.Amount,
+		arg.Frequency,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Notes,
+	)
+	var i FixedCost
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Category,
+		&i.Amount,
+		&i.Frequency,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Notes,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -102,57 +796,264 @@ func (q *Queries) CreateFuelLog(ctx context.Context, arg CreateFuelLogParams) (F
 	return i, err
 }
 
-const createPart = `-- name: CreatePart :one
-INSERT INTO parts (
-  service_record_id, name, part_number, cost, link
-) VALUES (
-  $1, $2, $3, $4, $5
+const createInsight = `-- name: CreateInsight :execrows
+INSERT INTO vehicle_insights (vehicle_id, kind, fuel_log_id, baseline, recent, change_percent, z_score)
+SELECT $1::int, $2::text, $3::int,
+       $4::float8, $5::float8, $6::float8, $7::float8
+WHERE NOT EXISTS (
+    SELECT 1 FROM vehicle_insights
+    WHERE vehicle_id = $1::int AND kind = $2::text
+      AND (resolved_at IS NULL OR fuel_log_id = $3::int)
 )
-RETURNING id, service_record_id, name, part_number, cost, link, created_at
 `
 
-type CreatePartParams struct {
-	ServiceRecordID sql.NullInt32
-	Name            string
-	PartNumber      sql.NullString
-	Cost            sql.NullString
-	Link            sql.NullString
+type CreateInsightParams struct {
+	VehicleID     int32
+	Kind          string
+	FuelLogID     int32
+	Baseline      float64
+	Recent        float64
+	ChangePercent float64
+	ZScore        float64
 }
 
-func (q *Queries) CreatePart(ctx context.Context, arg CreatePartParams) (Part, error) {
-	row := q.db.QueryRowContext(ctx, createPart,
-		arg.ServiceRecordID,
-		arg.Name,
-		arg.PartNumber,
-		arg.Cost,
-		arg.Link,
+// Records an insight unless the vehicle already has an open one of the kind, or one was
+// already raised (and perhaps dismissed) at the same fill.
+func (q *Queries) CreateInsight(ctx context.Context, arg CreateInsightParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createInsight,
+		arg.VehicleID,
+		arg.Kind,
+		arg.FuelLogID,
+		arg.Baseline,
+		arg.Recent,
+		arg.ChangePercent,
+		arg.ZScore,
 	)
-	var i Part
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createJobRun = `-- name: CreateJobRun :one
+INSERT INTO job_runs (job_name, trigger)
+VALUES ($1, $2)
+RETURNING id, job_name, trigger, status, error, started_at, finished_at
+`
+
+type CreateJobRunParams struct {
+	JobName string
+	Trigger string
+}
+
+func (q *Queries) CreateJobRun(ctx context.Context, arg CreateJobRunParams) (JobRun, error) {
+	row := q.db.QueryRowContext(ctx, createJobRun,
+		arg.JobName,
+		arg.Trigger,
+	)
+	var i JobRun
 	err := row.Scan(
 		&i.ID,
-		&i.ServiceRecordID,
-		&i.Name,
-		&i.PartNumber,
-		&i.Cost,
-		&i.Link,
-		&i.CreatedAt,
+		&i.JobName,
+		&i.Trigger,
+		&i.Status,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
 	)
 	return i, err
 }
 
-const createReminder = `-- name: CreateReminder :one
-INSERT INTO reminders (vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, type)
+const createLoan = `-- name: CreateLoan :one
+INSERT INTO loans (vehicle_id, lender, principal, annual_rate, tenure_months, emi, start_date, first_emi_date, notes)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-RETURNING id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type
+RETURNING id, vehicle_id, lender, principal, annual_rate, tenure_months, emi, start_date, first_emi_date, notes, created_at
 `
 
-type CreateReminderParams struct {
-	VehicleID      sql.NullInt32
-	Title          string
-	DueDate        sql.NullTime
-	DueOdometer    sql.NullInt32
-	IsRecurring    sql.NullBool
-	IntervalKm     sql.NullInt32
+type CreateLoanParams struct {
+	VehicleID    int32
+	Lender       sql.NullString
+	Principal    string
+	AnnualRate   string
+	TenureMonths int32
+	Emi          string
+	StartDate    time.Time
+	FirstEmiDate time.Time
+	Notes        sql.NullString
+}
+
+func (q *Queries) CreateLoan(ctx context.Context, arg CreateLoanParams) (Loan, error) {
+	row := q.db.QueryRowContext(ctx, createLoan,
+		arg.VehicleID,
+		arg.Lender,
+		arg.Principal,
+		arg.AnnualRate,
+		arg.TenureMonths,
+		arg.Emi,
+		arg.StartDate,
+		arg.FirstEmiDate,
+		arg.Notes,
+	)
+	var i Loan
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Lender,
+		&i.Principal,
+		&i.AnnualRate,
+		&i.TenureMonths,
+		&i.Emi,
+		&i.StartDate,
+		&i.FirstEmiDate,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createLoanPayment = `-- name: CreateLoanPayment :one
+INSERT INTO loan_payments (loan_id, date, amount, prepayment, notes)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, loan_id, date, amount, prepayment, notes, created_at
+`
+
+type CreateLoanPaymentParams struct {
+	LoanID     int32
+	Date       time.Time
+	Amount     string
+	Prepayment bool
+	Notes      sql.NullString
+}
+
+func (q *Queries) CreateLoanPayment(ctx context.Context, arg CreateLoanPaymentParams) (LoanPayment, error) {
+	row := q.db.QueryRowContext(ctx, createLoanPayment,
+		arg.LoanID,
+		arg.Date,
+		arg.Amount,
+		arg.Prepayment,
+		arg.Notes,
+	)
+	var i LoanPayment
+	err := row.Scan(
+		&i.ID,
+		&i.LoanID,
+		&i.Date,
+		&i.Amount,
+		&i.Prepayment,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createOdometerReading = `-- name: CreateOdometerReading :one
+INSERT INTO odometer_readings (vehicle_id, odometer, recorded_at, source)
+VALUES ($1, $2, $3, $4)
+RETURNING id, vehicle_id, odometer, recorded_at, source, created_at
+`
+
+type CreateOdometerReadingParams struct {
+	VehicleID  int32
+	Odometer   int32
+	RecordedAt time.Time
+	Source     string
+}
+
+func (q *Queries) CreateOdometerReading(ctx context.Context, arg CreateOdometerReadingParams) (OdometerReading, error) {
+	row := q.db.QueryRowContext(ctx, createOdometerReading,
+		arg.VehicleID,
+		arg.Odometer,
+		arg.RecordedAt,
+		arg.Source,
+	)
+	var i OdometerReading
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Odometer,
+		&i.RecordedAt,
+		&i.Source,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPart = `-- name: CreatePart :one
+INSERT INTO parts (
+  service_record_id, name, part_number, cost, link
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, service_record_id, name, part_number, cost, link, created_at
+`
+
+type CreatePartParams struct {
+	ServiceRecordID sql.NullInt32
+	Name            string
+	PartNumber      sql.NullString
+	Cost            sql.NullString
+	Link            sql.NullString
+}
+
+func (q *Queries) CreatePart(ctx context.Context, arg CreatePartParams) (Part, error) {
+	row := q.db.QueryRowContext(ctx, createPart,
+		arg.ServiceRecordID,
+		arg.Name,
+		arg.PartNumber,
+		arg.Cost,
+		arg.Link,
+	)
+	var i Part
+	err := row.Scan(
+		&i.ID,
+		&i.ServiceRecordID,
+		&i.Name,
+		&i.PartNumber,
+		&i.Cost,
+		&i.Link,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRecurringExpense = `-- name: CreateRecurringExpense :execrows
+INSERT INTO expenses (vehicle_id, vendor_id, category, date, amount, description, recurring_expense_id)
+SELECT vehicle_id, vendor_id, category, $1::date, amount, description, id
+FROM expenses
+WHERE id = $2::int
+ON CONFLICT DO NOTHING
+`
+
+type CreateRecurringExpenseParams struct {
+	Date time.Time
+	ID   int32
+}
+
+// Copies a recurring expense to the given date, unless a copy for that date exists.
+func (q *Queries) CreateRecurringExpense(ctx context.Context, arg CreateRecurringExpenseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createRecurringExpense,
+		arg.Date,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createReminder = `-- name: CreateReminder :one
+INSERT INTO reminders (vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, type)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at
+`
+
+type CreateReminderParams struct {
+	VehicleID      sql.NullInt32
+	Title          string
+	DueDate        sql.NullTime
+	DueOdometer    sql.NullInt32
+	IsRecurring    sql.NullBool
+	IntervalKm     sql.NullInt32
 	IntervalMonths sql.NullInt32
 	Notes          sql.NullString
 	Type           sql.NullString
@@ -184,27 +1085,29 @@ func (q *Queries) CreateReminder(ctx context.Context, arg CreateReminderParams)
 		&i.IsCompleted,
 		&i.CreatedAt,
 		&i.Type,
+		&i.CompletedAt,
 	)
 	return i, err
 }
 
 const createServiceRecord = `-- name: CreateServiceRecord :one
 INSERT INTO service_records (
-  vehicle_id, date, odometer, cost, notes, service_type, document_url
+  vehicle_id, date, odometer, cost, notes, service_type, document_url, completed_date
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8
 )
-RETURNING id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url
+RETURNING id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date
 `
 
 type CreateServiceRecordParams struct {
-	VehicleID   sql.NullInt32
-	Date        time.Time
-	Odometer    int32
-	Cost        string
-	Notes       sql.NullString
-	ServiceType sql.NullString
-	DocumentUrl sql.NullString
+	VehicleID     sql.NullInt32
+	Date          time.Time
+	Odometer      int32
+	Cost          string
+	Notes         sql.NullString
+	ServiceType   sql.NullString
+	DocumentUrl   sql.NullString
+	CompletedDate sql.NullTime
 }
 
 func (q *Queries) CreateServiceRecord(ctx context.Context, arg CreateServiceRecordParams) (ServiceRecord, error) {
@@ -216,6 +1119,7 @@ func (q *Queries) CreateServiceRecord(ctx context.Context, arg CreateServiceReco
 		arg.Notes,
 		arg.ServiceType,
 		arg.DocumentUrl,
+		arg.CompletedDate,
 	)
 	var i ServiceRecord
 	err := row.Scan(
@@ -228,28 +1132,149 @@ func (q *Queries) CreateServiceRecord(ctx context.Context, arg CreateServiceReco
 		&i.ServiceType,
 		&i.CreatedAt,
 		&i.DocumentUrl,
+		&i.CompletedDate,
+	)
+	return i, err
+}
+
+const createSubscription = `-- name: CreateSubscription :one
+INSERT INTO notification_subscriptions (user_id, vehicle_id, channel, reminder_types, triggers, lead_days, digest, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, user_id, vehicle_id, channel, reminder_types, triggers, lead_days, digest, enabled, created_at
+`
+
+type CreateSubscriptionParams struct {
+	UserID        int32
+	VehicleID     sql.NullInt32
+	Channel       string
+	ReminderTypes []string
+	Triggers      []string
+	LeadDays      int32
+	Digest        string
+	Enabled       bool
+}
+
+func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (NotificationSubscription, error) {
+	row := q.db.QueryRowContext(ctx, createSubscription,
+		arg.UserID,
+		arg.VehicleID,
+		arg.Channel,
+		pq.Array(arg.ReminderTypes),
+		pq.Array(arg.Triggers),
+		arg.LeadDays,
+		arg.Digest,
+		arg.Enabled,
+	)
+	var i NotificationSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.VehicleID,
+		&i.Channel,
+		pq.Array(&i.ReminderTypes),
+		pq.Array(&i.Triggers),
+		&i.LeadDays,
+		&i.Digest,
+		&i.Enabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (name, email, ntfy_topic, locale)
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, email, ntfy_topic, created_at, locale, feed_token
+`
+
+type CreateUserParams struct {
+	Name      string
+	Email     sql.NullString
+	NtfyTopic sql.NullString
+	Locale    string
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser,
+		arg.Name,
+		arg.Email,
+		arg.NtfyTopic,
+		arg.Locale,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.NtfyTopic,
+		&i.CreatedAt,
+		&i.Locale,
+		&i.FeedToken,
+	)
+	return i, err
+}
+
+const createValuation = `-- name: CreateValuation :one
+INSERT INTO vehicle_valuations (vehicle_id, date, value, source, notes)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, vehicle_id, date, value, source, notes, created_at
+`
+
+type CreateValuationParams struct {
+	VehicleID int32
+	Date      time.Time
+	Value     string
+	Source    sql.NullString
+	Notes     sql.NullString
+}
+
+func (q *Queries) CreateValuation(ctx context.Context, arg CreateValuationParams) (VehicleValuation, error) {
+	row := q.db.QueryRowContext(ctx, createValuation,
+		arg.VehicleID,
+		arg.Date,
+		arg.Value,
+		arg.Source,
+		arg.Notes,
+	)
+	var i VehicleValuation
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Date,
+		&i.Value,
+		&i.Source,
+		&i.Notes,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const createVehicle = `-- name: CreateVehicle :one
 INSERT INTO vehicles (
-  name, make, model, year, type, vin, license_plate, image_url
+  name, make, model, year, type, vin, license_plate, image_url, fuel_type, distance_unit,
+  purchase_price, purchase_date, depreciation_model, depreciation_rate, residual_value
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 )
-RETURNING id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at
+RETURNING id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at, fuel_type, distance_unit, purchase_price, purchase_date, depreciation_model, depreciation_rate, residual_value, status, sale_date, sale_price, buyer_notes
 `
 
 type CreateVehicleParams struct {
-	Name         string
-	Make         sql.NullString
-	Model        sql.NullString
-	Year         sql.NullInt32
-	Type         sql.NullString
-	Vin          sql.NullString
-	LicensePlate sql.NullString
-	ImageUrl     sql.NullString
+	Name              string
+	Make              sql.NullString
+	Model             sql.NullString
+	Year              sql.NullInt32
+	Type              sql.NullString
+	Vin               sql.NullString
+	LicensePlate      sql.NullString
+	ImageUrl          sql.NullString
+	FuelType          sql.NullString
+	DistanceUnit      sql.NullString
+	PurchasePrice     sql.NullString
+	PurchaseDate      sql.NullTime
+	DepreciationModel sql.NullString
+	DepreciationRate  sql.NullString
+	ResidualValue     sql.NullString
 }
 
 func (q *Queries) CreateVehicle(ctx context.Context, arg CreateVehicleParams) (Vehicle, error) {
@@ -262,6 +1287,13 @@ func (q *Queries) CreateVehicle(ctx context.Context, arg CreateVehicleParams) (V
 		arg.Vin,
 		arg.LicensePlate,
 		arg.ImageUrl,
+		arg.FuelType,
+		arg.DistanceUnit,
+		arg.PurchasePrice,
+		arg.PurchaseDate,
+		arg.DepreciationModel,
+		arg.DepreciationRate,
+		arg.ResidualValue,
 	)
 	var i Vehicle
 	err := row.Scan(
@@ -276,10 +1308,135 @@ func (q *Queries) CreateVehicle(ctx context.Context, arg CreateVehicleParams) (V
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FuelType,
+		&i.DistanceUnit,
+		&i.PurchasePrice,
+		&i.PurchaseDate,
+		&i.DepreciationModel,
+		&i.DepreciationRate,
+		&i.ResidualValue,
+		&i.Status,
+		&i.SaleDate,
+		&i.SalePrice,
+		&i.BuyerNotes,
+	)
+	return i, err
+}
+
+const createVendor = `-- name: CreateVendor :one
+INSERT INTO vendors (name, website, phone, address, notes)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, name, website, phone, address, notes, created_at
+`
+
+type CreateVendorParams struct {
+	Name    string
+	Website sql.NullString
+	Phone   sql.NullString
+	Address sql.NullString
+	Notes   sql.NullString
+}
+
+func (q *Queries) CreateVendor(ctx context.Context, arg CreateVendorParams) (Vendor, error) {
+	row := q.db.QueryRowContext(ctx, createVendor,
+		arg.Name,
+		arg.Website,
+		arg.Phone,
+		arg.Address,
+		arg.Notes,
+	)
+	var i Vendor
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Website,
+		&i.Phone,
+		&i.Address,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks (url, secret, event_types, description, enabled)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, url, secret, event_types, description, enabled, created_at
+`
+
+type CreateWebhookParams struct {
+	Url         string
+	Secret      string
+	EventTypes  []string
+	Description sql.NullString
+	Enabled     bool
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, createWebhook,
+		arg.Url,
+		arg.Secret,
+		pq.Array(arg.EventTypes),
+		arg.Description,
+		arg.Enabled,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.EventTypes),
+		&i.Description,
+		&i.Enabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+VALUES ($1, $2, $3)
+RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, response_status, error, created_at, delivered_at
+`
+
+type CreateWebhookDeliveryParams struct {
+	WebhookID int32
+	EventType string
+	Payload   json.RawMessage
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookDelivery,
+		arg.WebhookID,
+		arg.EventType,
+		arg.Payload,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.ResponseStatus,
+		&i.Error,
+		&i.CreatedAt,
+		&i.DeliveredAt,
 	)
 	return i, err
 }
 
+const deleteBudget = `-- name: DeleteBudget :exec
+DELETE FROM budgets WHERE id = $1
+`
+
+func (q *Queries) DeleteBudget(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteBudget, id)
+	return err
+}
+
 const deleteDocument = `-- name: DeleteDocument :exec
 DELETE FROM documents WHERE id = $1
 `
@@ -289,6 +1446,51 @@ func (q *Queries) DeleteDocument(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteDocumentCategory = `-- name: DeleteDocumentCategory :exec
+DELETE FROM document_categories WHERE slug = $1
+`
+
+func (q *Queries) DeleteDocumentCategory(ctx context.Context, slug string) error {
+	_, err := q.db.ExecContext(ctx, deleteDocumentCategory, slug)
+	return err
+}
+
+const deleteDocumentFile = `-- name: DeleteDocumentFile :exec
+DELETE FROM document_files WHERE id = $1
+`
+
+func (q *Queries) DeleteDocumentFile(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteDocumentFile, id)
+	return err
+}
+
+const deleteExpense = `-- name: DeleteExpense :exec
+DELETE FROM expenses WHERE id = $1
+`
+
+func (q *Queries) DeleteExpense(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteExpense, id)
+	return err
+}
+
+const deleteExpenseFile = `-- name: DeleteExpenseFile :exec
+DELETE FROM expense_files WHERE id = $1
+`
+
+func (q *Queries) DeleteExpenseFile(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteExpenseFile, id)
+	return err
+}
+
+const deleteFixedCost = `-- name: DeleteFixedCost :exec
+DELETE FROM fixed_costs WHERE id = $1
+`
+
+func (q *Queries) DeleteFixedCost(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteFixedCost, id)
+	return err
+}
+
 const deleteFuelLog = `-- name: DeleteFuelLog :exec
 DELETE FROM fuel_logs WHERE id = $1
 `
@@ -298,87 +1500,5823 @@ func (q *Queries) DeleteFuelLog(ctx context.Context, id int32) error {
 	return err
 }
 
-const deleteServiceRecord = `-- name: DeleteServiceRecord :exec
-DELETE FROM service_records WHERE id = $1
+const deleteJobRunsBefore = `-- name: DeleteJobRunsBefore :execrows
+DELETE FROM job_runs
+WHERE started_at < $1
 `
 
-func (q *Queries) DeleteServiceRecord(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteServiceRecord, id)
+func (q *Queries) DeleteJobRunsBefore(ctx context.Context, startedAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteJobRunsBefore, startedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteLoan = `-- name: DeleteLoan :exec
+DELETE FROM loans WHERE id = $1
+`
+
+func (q *Queries) DeleteLoan(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteLoan, id)
 	return err
 }
 
-const deleteVehicle = `-- name: DeleteVehicle :exec
-DELETE FROM vehicles
-WHERE id = $1
+const deleteLoanPayment = `-- name: DeleteLoanPayment :exec
+DELETE FROM loan_payments WHERE id = $1
+`
+
+func (q *Queries) DeleteLoanPayment(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteLoanPayment, id)
+	return err
+}
+
+const deleteNotificationTemplate = `-- name: DeleteNotificationTemplate :exec
+DELETE FROM notification_templates
+WHERE user_id = $1 AND kind = $2
 `
 
-func (q *Queries) DeleteVehicle(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteVehicle, id)
-	return err
+type DeleteNotificationTemplateParams struct {
+	UserID int32
+	Kind   string
+}
+
+func (q *Queries) DeleteNotificationTemplate(ctx context.Context, arg DeleteNotificationTemplateParams) error {
+	_, err := q.db.ExecContext(ctx, deleteNotificationTemplate,
+		arg.UserID,
+		arg.Kind,
+	)
+	return err
+}
+
+const deleteReminder = `-- name: DeleteReminder :exec
+DELETE FROM reminders WHERE id = $1
+`
+
+func (q *Queries) DeleteReminder(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteReminder, id)
+	return err
+}
+
+const deleteServiceRecord = `-- name: DeleteServiceRecord :exec
+DELETE FROM service_records WHERE id = $1
+`
+
+func (q *Queries) DeleteServiceRecord(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteServiceRecord, id)
+	return err
+}
+
+const deleteSubscription = `-- name: DeleteSubscription :exec
+DELETE FROM notification_subscriptions WHERE id = $1
+`
+
+func (q *Queries) DeleteSubscription(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteSubscription, id)
+	return err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const deleteValuation = `-- name: DeleteValuation :exec
+DELETE FROM vehicle_valuations WHERE id = $1
+`
+
+func (q *Queries) DeleteValuation(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteValuation, id)
+	return err
+}
+
+const deleteVehicle = `-- name: DeleteVehicle :exec
+DELETE FROM vehicles
+WHERE id = $1
+`
+
+func (q *Queries) DeleteVehicle(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteVehicle, id)
+	return err
+}
+
+const deleteVendor = `-- name: DeleteVendor :exec
+DELETE FROM vendors WHERE id = $1
+`
+
+func (q *Queries) DeleteVendor(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteVendor, id)
+	return err
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :exec
+DELETE FROM webhooks WHERE id = $1
+`
+
+func (q *Queries) DeleteWebhook(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhook, id)
+	return err
+}
+
+const deleteWebhookDeliveriesBefore = `-- name: DeleteWebhookDeliveriesBefore :execrows
+DELETE FROM webhook_deliveries
+WHERE status <> 'pending' AND created_at < $1
+`
+
+func (q *Queries) DeleteWebhookDeliveriesBefore(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWebhookDeliveriesBefore, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const dismissInsight = `-- name: DismissInsight :one
+UPDATE vehicle_insights
+SET resolved_at = NOW()
+WHERE id = $1 AND resolved_at IS NULL
+RETURNING id, vehicle_id, kind, fuel_log_id, baseline, recent, change_percent, z_score, created_at, resolved_at
+`
+
+func (q *Queries) DismissInsight(ctx context.Context, id int32) (VehicleInsight, error) {
+	row := q.db.QueryRowContext(ctx, dismissInsight, id)
+	var i VehicleInsight
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Kind,
+		&i.FuelLogID,
+		&i.Baseline,
+		&i.Recent,
+		&i.ChangePercent,
+		&i.ZScore,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+SELECT id, $1::text, $2::jsonb
+FROM webhooks
+WHERE enabled = TRUE
+  AND (cardinality(event_types) = 0 OR $1::text = ANY(event_types))
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	EventType string
+	Payload   json.RawMessage
+}
+
+// Queues the event for every enabled webhook that listens to it.
+func (q *Queries) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enqueueWebhookDeliveries,
+		arg.EventType,
+		arg.Payload,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const finishJobRun = `-- name: FinishJobRun :one
+UPDATE job_runs
+SET status = $2, error = $3, finished_at = NOW()
+WHERE id = $1
+RETURNING id, job_name, trigger, status, error, started_at, finished_at
+`
+
+type FinishJobRunParams struct {
+	ID     int32
+	Status string
+	Error  sql.NullString
+}
+
+func (q *Queries) FinishJobRun(ctx context.Context, arg FinishJobRunParams) (JobRun, error) {
+	row := q.db.QueryRowContext(ctx, finishJobRun,
+		arg.ID,
+		arg.Status,
+		arg.Error,
+	)
+	var i JobRun
+	err := row.Scan(
+		&i.ID,
+		&i.JobName,
+		&i.Trigger,
+		&i.Status,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const getBudget = `-- name: GetBudget :one
+SELECT id, vehicle_id, category, period, amount, thresholds, created_at FROM budgets
+WHERE id = $1
+`
+
+func (q *Queries) GetBudget(ctx context.Context, id int32) (Budget, error) {
+	row := q.db.QueryRowContext(ctx, getBudget, id)
+	var i Budget
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Category,
+		&i.Period,
+		&i.Amount,
+		pq.Array(&i.Thresholds),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDocument = `-- name: GetDocument :one
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetDocument(ctx context.Context, id int32) (Document, error) {
+	row := q.db.QueryRowContext(ctx, getDocument, id)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Name,
+		&i.Type,
+		&i.FileUrl,
+		&i.ExpiryDate,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.PreviousVersionID,
+		&i.Category,
+		&i.IssueDate,
+		&i.Issuer,
+		&i.PolicyNumber,
+		&i.RegistrationNumber,
+		pq.Array(&i.Tags),
+	)
+	return i, err
+}
+
+const getDocumentFile = `-- name: GetDocumentFile :one
+SELECT id, document_id, label, file_name, content_type, size_bytes, storage_key, position, created_at, thumbnail_status, thumbnail_error FROM document_files
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetDocumentFile(ctx context.Context, id int32) (DocumentFile, error) {
+	row := q.db.QueryRowContext(ctx, getDocumentFile, id)
+	var i DocumentFile
+	err := row.Scan(
+		&i.ID,
+		&i.DocumentID,
+		&i.Label,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.Position,
+		&i.CreatedAt,
+		&i.ThumbnailStatus,
+		&i.ThumbnailError,
+	)
+	return i, err
+}
+
+const getExpense = `-- name: GetExpense :one
+SELECT id, vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date, recurring_expense_id, created_at FROM expenses
+WHERE id = $1
+`
+
+func (q *Queries) GetExpense(ctx context.Context, id int32) (Expense, error) {
+	row := q.db.QueryRowContext(ctx, getExpense, id)
+	var i Expense
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.VendorID,
+		&i.Category,
+		&i.Date,
+		&i.Amount,
+		&i.Odometer,
+		&i.Description,
+		&i.Recurrence,
+		&i.RecurrenceEndDate,
+		&i.NextDueDate,
+		&i.RecurringExpenseID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getExpenseFile = `-- name: GetExpenseFile :one
+SELECT id, expense_id, file_name, content_type, size_bytes, storage_key, created_at FROM expense_files
+WHERE id = $1
+`
+
+func (q *Queries) GetExpenseFile(ctx context.Context, id int32) (ExpenseFile, error) {
+	row := q.db.QueryRowContext(ctx, getExpenseFile, id)
+	var i ExpenseFile
+	err := row.Scan(
+		&i.ID,
+		&i.ExpenseID,
+		&i.FileName,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getExpenseTimeseries = `-- name: GetExpenseTimeseries :many
+SELECT date_trunc($1::text, date::timestamp)::date AS period_start,
+       SUM(amount)::float8 AS expense_cost
+FROM expenses
+WHERE vehicle_id = $2::int
+  AND date >= $3::date AND date <= $4::date
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetExpenseTimeseriesParams struct {
+	Period    string
+	VehicleID int32
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+type GetExpenseTimeseriesRow struct {
+	PeriodStart time.Time
+	ExpenseCost float64
+}
+
+func (q *Queries) GetExpenseTimeseries(ctx context.Context, arg GetExpenseTimeseriesParams) ([]GetExpenseTimeseriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExpenseTimeseries,
+		arg.Period,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExpenseTimeseriesRow
+	for rows.Next() {
+		var i GetExpenseTimeseriesRow
+		if err := rows.Scan(
+			&i.PeriodStart,
+			&i.ExpenseCost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFuelEconomy = `-- name: GetFuelEconomy :one
+WITH fills AS (
+    SELECT date, liters, full_tank,
+           odometer - LAG(odometer) OVER (ORDER BY odometer, id) AS distance
+    FROM fuel_logs
+    WHERE vehicle_id = $1::int
+)
+SELECT COALESCE(SUM(liters), 0)::float8 AS liters,
+       COALESCE(SUM(distance) FILTER (WHERE full_tank AND distance > 0), 0)::float8 AS economy_distance,
+       COALESCE(SUM(liters) FILTER (WHERE full_tank AND distance > 0), 0)::float8 AS economy_liters
+FROM fills
+WHERE date >= $2::date AND date <= $3::date
+`
+
+type GetFuelEconomyParams struct {
+	VehicleID int32
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+type GetFuelEconomyRow struct {
+	Liters          float64
+	EconomyDistance float64
+	EconomyLiters   float64
+}
+
+// Fuel bought between two dates and economy over the full-tank fills among them, measured
+// like GetFuelTimeseries.
+func (q *Queries) GetFuelEconomy(ctx context.Context, arg GetFuelEconomyParams) (GetFuelEconomyRow, error) {
+	row := q.db.QueryRowContext(ctx, getFuelEconomy,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	var i GetFuelEconomyRow
+	err := row.Scan(
+		&i.Liters,
+		&i.EconomyDistance,
+		&i.EconomyLiters,
+	)
+	return i, err
+}
+
+const getFuelTimeseries = `-- name: GetFuelTimeseries :many
+WITH fills AS (
+    SELECT date, total_cost, liters, full_tank,
+           odometer - LAG(odometer) OVER (ORDER BY odometer, id) AS distance
+    FROM fuel_logs
+    WHERE vehicle_id = $1::int
+)
+SELECT date_trunc($2::text, date::timestamp)::date AS period_start,
+       SUM(total_cost)::float8 AS fuel_cost,
+       SUM(liters)::float8 AS liters,
+       COALESCE(SUM(distance) FILTER (WHERE full_tank AND distance > 0), 0)::float8 AS economy_distance,
+       COALESCE(SUM(liters) FILTER (WHERE full_tank AND distance > 0), 0)::float8 AS economy_liters
+FROM fills
+WHERE date >= $3::date AND date <= $4::date
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetFuelTimeseriesParams struct {
+	VehicleID int32
+	Period    string
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+type GetFuelTimeseriesRow struct {
+	PeriodStart     time.Time
+	FuelCost        float64
+	Liters          float64
+	EconomyDistance float64
+	EconomyLiters   float64
+}
+
+// Fuel spend per period. Economy uses full-tank fills only: the distance since the
+// previous fill over the litres filled, summed per period.
+func (q *Queries) GetFuelTimeseries(ctx context.Context, arg GetFuelTimeseriesParams) ([]GetFuelTimeseriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFuelTimeseries,
+		arg.VehicleID,
+		arg.Period,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFuelTimeseriesRow
+	for rows.Next() {
+		var i GetFuelTimeseriesRow
+		if err := rows.Scan(
+			&i.PeriodStart,
+			&i.FuelCost,
+			&i.Liters,
+			&i.EconomyDistance,
+			&i.EconomyLiters,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLoan = `-- name: GetLoan :one
+SELECT id, vehicle_id, lender, principal, annual_rate, tenure_months, emi, start_date, first_emi_date, notes, created_at FROM loans
+WHERE id = $1
+`
+
+func (q *Queries) GetLoan(ctx context.Context, id int32) (Loan, error) {
+	row := q.db.QueryRowContext(ctx, getLoan, id)
+	var i Loan
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Lender,
+		&i.Principal,
+		&i.AnnualRate,
+		&i.TenureMonths,
+		&i.Emi,
+		&i.StartDate,
+		&i.FirstEmiDate,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLoanPayment = `-- name: GetLoanPayment :one
+SELECT id, loan_id, date, amount, prepayment, notes, created_at FROM loan_payments
+WHERE id = $1
+`
+
+func (q *Queries) GetLoanPayment(ctx context.Context, id int32) (LoanPayment, error) {
+	row := q.db.QueryRowContext(ctx, getLoanPayment, id)
+	var i LoanPayment
+	err := row.Scan(
+		&i.ID,
+		&i.LoanID,
+		&i.Date,
+		&i.Amount,
+		&i.Prepayment,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getOdometerRange = `-- name: GetOdometerRange :one
+WITH readings AS (
+    SELECT date, odometer FROM fuel_logs WHERE vehicle_id = $1::int
+    UNION ALL
+    SELECT date, odometer FROM service_records WHERE vehicle_id = $1::int
+    UNION ALL
+    SELECT recorded_at::date, odometer FROM odometer_readings WHERE vehicle_id = $1::int
+)
+SELECT
+    COALESCE(
+        (SELECT MAX(odometer) FROM readings WHERE date < $2::date),
+        (SELECT MIN(odometer) FROM readings WHERE date >= $2::date AND date <= $3::date),
+        0
+    )::int AS start_odometer,
+    COALESCE((SELECT MAX(odometer) FROM readings WHERE date <= $3::date), 0)::int AS end_odometer
+`
+
+type GetOdometerRangeParams struct {
+	VehicleID int32
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+type GetOdometerRangeRow struct {
+	StartOdometer int32
+	EndOdometer   int32
+}
+
+// Odometer at the start and end of a date range, from any source. The start is the last
+// reading before the range, or the first one in it for vehicles added during the range.
+func (q *Queries) GetOdometerRange(ctx context.Context, arg GetOdometerRangeParams) (GetOdometerRangeRow, error) {
+	row := q.db.QueryRowContext(ctx, getOdometerRange,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	var i GetOdometerRangeRow
+	err := row.Scan(
+		&i.StartOdometer,
+		&i.EndOdometer,
+	)
+	return i, err
+}
+
+const getOdometerTimeseries = `-- name: GetOdometerTimeseries :many
+WITH readings AS (
+    SELECT date, odometer FROM fuel_logs WHERE vehicle_id = $1::int
+    UNION ALL
+    SELECT date, odometer FROM service_records WHERE vehicle_id = $1::int
+    UNION ALL
+    SELECT recorded_at::date, odometer FROM odometer_readings WHERE vehicle_id = $1::int
+)
+SELECT date_trunc($2::text, date::timestamp)::date AS period_start,
+       MAX(odometer)::int AS odometer
+FROM readings
+WHERE date <= $3::date
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetOdometerTimeseriesParams struct {
+	VehicleID int32
+	Period    string
+	DateTo    time.Time
+}
+
+type GetOdometerTimeseriesRow struct {
+	PeriodStart time.Time
+	Odometer    int32
+}
+
+// Highest odometer reading from any source per period, up to date_to. Earlier periods are
+// included so the first period in range has a reading to measure distance from.
+func (q *Queries) GetOdometerTimeseries(ctx context.Context, arg GetOdometerTimeseriesParams) ([]GetOdometerTimeseriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOdometerTimeseries,
+		arg.VehicleID,
+		arg.Period,
+		arg.DateTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOdometerTimeseriesRow
+	for rows.Next() {
+		var i GetOdometerTimeseriesRow
+		if err := rows.Scan(
+			&i.PeriodStart,
+			&i.Odometer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReminder = `-- name: GetReminder :one
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetReminder(ctx context.Context, id int32) (Reminder, error) {
+	row := q.db.QueryRowContext(ctx, getReminder, id)
+	var i Reminder
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Title,
+		&i.DueDate,
+		&i.DueOdometer,
+		&i.IsRecurring,
+		&i.IntervalKm,
+		&i.IntervalMonths,
+		&i.Notes,
+		&i.IsCompleted,
+		&i.CreatedAt,
+		&i.Type,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getServiceTimeseries = `-- name: GetServiceTimeseries :many
+SELECT date_trunc($1::text, date::timestamp)::date AS period_start,
+       SUM(cost)::float8 AS service_cost
+FROM service_records
+WHERE vehicle_id = $2::int
+  AND date >= $3::date AND date <= $4::date
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetServiceTimeseriesParams struct {
+	Period    string
+	VehicleID int32
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+type GetServiceTimeseriesRow struct {
+	PeriodStart time.Time
+	ServiceCost float64
+}
+
+func (q *Queries) GetServiceTimeseries(ctx context.Context, arg GetServiceTimeseriesParams) ([]GetServiceTimeseriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getServiceTimeseries,
+		arg.Period,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetServiceTimeseriesRow
+	for rows.Next() {
+		var i GetServiceTimeseriesRow
+		if err := rows.Scan(
+			&i.PeriodStart,
+			&i.ServiceCost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSpendBetween = `-- name: GetSpendBetween :one
+SELECT
+    (SELECT COALESCE(SUM(total_cost), 0) FROM fuel_logs
+     WHERE ($1::int IS NULL OR fuel_logs.vehicle_id = $1::int)
+       AND date >= $2::date AND date <= $3::date)::float8 AS fuel_cost,
+    (SELECT COALESCE(SUM(cost), 0) FROM service_records
+     WHERE ($1::int IS NULL OR service_records.vehicle_id = $1::int)
+       AND date >= $2::date AND date <= $3::date)::float8 AS service_cost
+`
+
+type GetSpendBetweenParams struct {
+	VehicleID sql.NullInt32
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+type GetSpendBetweenRow struct {
+	FuelCost    float64
+	ServiceCost float64
+}
+
+// Fuel and service spend between two dates, inclusive, of one vehicle or all of them.
+func (q *Queries) GetSpendBetween(ctx context.Context, arg GetSpendBetweenParams) (GetSpendBetweenRow, error) {
+	row := q.db.QueryRowContext(ctx, getSpendBetween,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	var i GetSpendBetweenRow
+	err := row.Scan(
+		&i.FuelCost,
+		&i.ServiceCost,
+	)
+	return i, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT id, name, email, ntfy_topic, created_at, locale, feed_token FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.NtfyTopic,
+		&i.CreatedAt,
+		&i.Locale,
+		&i.FeedToken,
+	)
+	return i, err
+}
+
+const getUserByFeedToken = `-- name: GetUserByFeedToken :one
+SELECT id, name, email, ntfy_topic, created_at, locale, feed_token FROM users
+WHERE feed_token = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByFeedToken(ctx context.Context, feedToken sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByFeedToken, feedToken)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.NtfyTopic,
+		&i.CreatedAt,
+		&i.Locale,
+		&i.FeedToken,
+	)
+	return i, err
+}
+
+const getVehicle = `-- name: GetVehicle :one
+SELECT id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at, fuel_type, distance_unit, purchase_price, purchase_date, depreciation_model, depreciation_rate, residual_value, status, sale_date, sale_price, buyer_notes FROM vehicles
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetVehicle(ctx context.Context, id int32) (Vehicle, error) {
+	row := q.db.QueryRowContext(ctx, getVehicle, id)
+	var i Vehicle
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Make,
+		&i.Model,
+		&i.Year,
+		&i.Type,
+		&i.Vin,
+		&i.LicensePlate,
+		&i.ImageUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FuelType,
+		&i.DistanceUnit,
+		&i.PurchasePrice,
+		&i.PurchaseDate,
+		&i.DepreciationModel,
+		&i.DepreciationRate,
+		&i.ResidualValue,
+		&i.Status,
+		&i.SaleDate,
+		&i.SalePrice,
+		&i.BuyerNotes,
+	)
+	return i, err
+}
+
+const getVehicleOdometer = `-- name: GetVehicleOdometer :one
+SELECT GREATEST(
+    (SELECT COALESCE(MAX(odometer), 0) FROM fuel_logs WHERE fuel_logs.vehicle_id = $1::int),
+    (SELECT COALESCE(MAX(odometer), 0) FROM service_records WHERE service_records.vehicle_id = $1::int),
+    (SELECT COALESCE(MAX(odometer), 0) FROM odometer_readings WHERE odometer_readings.vehicle_id = $1::int)
+)::int AS odometer
+`
+
+// Highest odometer from any source, including readings older than the driving rate window.
+func (q *Queries) GetVehicleOdometer(ctx context.Context, vehicleID int32) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getVehicleOdometer, vehicleID)
+	var odometer int32
+	err := row.Scan(&odometer)
+	return odometer, err
+}
+
+const getVehicleSpend = `-- name: GetVehicleSpend :one
+SELECT
+    (SELECT COALESCE(SUM(total_cost), 0) FROM fuel_logs
+     WHERE fuel_logs.vehicle_id = $1::int AND date >= $2::date AND date <= $3::date)::float8 AS fuel_cost,
+    (SELECT COALESCE(SUM(cost), 0) FROM service_records
+     WHERE service_records.vehicle_id = $1::int AND date >= $2::date AND date <= $3::date)::float8 AS service_cost
+`
+
+type GetVehicleSpendParams struct {
+	VehicleID int32
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+type GetVehicleSpendRow struct {
+	FuelCost    float64
+	ServiceCost float64
+}
+
+// Fuel and service spend of a vehicle between two dates, inclusive.
+func (q *Queries) GetVehicleSpend(ctx context.Context, arg GetVehicleSpendParams) (GetVehicleSpendRow, error) {
+	row := q.db.QueryRowContext(ctx, getVehicleSpend,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	var i GetVehicleSpendRow
+	err := row.Scan(
+		&i.FuelCost,
+		&i.ServiceCost,
+	)
+	return i, err
+}
+
+const getVehicleStats = `-- name: GetVehicleStats :one
+SELECT
+    (SELECT COALESCE(SUM(total_cost), 0.0)::float8 FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_fuel_cost,
+    (SELECT COALESCE(SUM(cost), 0.0)::float8 FROM service_records WHERE service_records.vehicle_id = $1) AS total_service_cost,
+    (SELECT COALESCE(SUM(liters), 0.0)::float8 FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_liters,
+    (SELECT COUNT(*) FROM service_records WHERE service_records.vehicle_id = $1) AS total_services,
+    (SELECT COUNT(*) FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_fuel_logs,
+    (SELECT COALESCE(SUM(amount), 0.0)::float8 FROM expenses WHERE expenses.vehicle_id = $1) AS total_expense_cost,
+    (SELECT COUNT(*) FROM expenses WHERE expenses.vehicle_id = $1) AS total_expenses
+`
+
+type GetVehicleStatsRow struct {
+	TotalFuelCost    float64
+	TotalServiceCost float64
+	TotalLiters      float64
+	TotalServices    int64
+	TotalFuelLogs    int64
+	TotalExpenseCost float64
+	TotalExpenses    int64
+}
+
+func (q *Queries) GetVehicleStats(ctx context.Context, vehicleID sql.NullInt32) (GetVehicleStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getVehicleStats, vehicleID)
+	var i GetVehicleStatsRow
+	err := row.Scan(
+		&i.TotalFuelCost,
+		&i.TotalServiceCost,
+		&i.TotalLiters,
+		&i.TotalServices,
+		&i.TotalFuelLogs,
+		&i.TotalExpenseCost,
+		&i.TotalExpenses,
+	)
+	return i, err
+}
+
+const getVehicleTelemetry = `-- name: GetVehicleTelemetry :one
+SELECT
+    GREATEST(
+        (SELECT COALESCE(MAX(odometer), 0) FROM fuel_logs WHERE fuel_logs.vehicle_id = $1::int),
+        (SELECT COALESCE(MAX(odometer), 0) FROM service_records WHERE service_records.vehicle_id = $1::int),
+        (SELECT COALESCE(MAX(odometer), 0) FROM odometer_readings WHERE odometer_readings.vehicle_id = $1::int)
+    )::int AS odometer,
+    (
+        (SELECT COALESCE(SUM(total_cost), 0) FROM fuel_logs WHERE fuel_logs.vehicle_id = $1::int AND date >= $2::date) +
+        (SELECT COALESCE(SUM(cost), 0) FROM service_records WHERE service_records.vehicle_id = $1::int AND date >= $2::date) +
+        (SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE expenses.vehicle_id = $1::int AND date >= $2::date)
+    )::float8 AS period_cost
+`
+
+type GetVehicleTelemetryParams struct {
+	VehicleID int32
+	Since     time.Time
+}
+
+type GetVehicleTelemetryRow struct {
+	Odometer   int32
+	PeriodCost float64
+}
+
+// Latest odometer from any source and spend since a date, for Home Assistant sensors.
+func (q *Queries) GetVehicleTelemetry(ctx context.Context, arg GetVehicleTelemetryParams) (GetVehicleTelemetryRow, error) {
+	row := q.db.QueryRowContext(ctx, getVehicleTelemetry,
+		arg.VehicleID,
+		arg.Since,
+	)
+	var i GetVehicleTelemetryRow
+	err := row.Scan(
+		&i.Odometer,
+		&i.PeriodCost,
+	)
+	return i, err
+}
+
+const getVendor = `-- name: GetVendor :one
+SELECT id, name, website, phone, address, notes, created_at FROM vendors
+WHERE id = $1
+`
+
+func (q *Queries) GetVendor(ctx context.Context, id int32) (Vendor, error) {
+	row := q.db.QueryRowContext(ctx, getVendor, id)
+	var i Vendor
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Website,
+		&i.Phone,
+		&i.Address,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWebhook = `-- name: GetWebhook :one
+SELECT id, url, secret, event_types, description, enabled, created_at FROM webhooks
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetWebhook(ctx context.Context, id int32) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhook, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.EventTypes),
+		&i.Description,
+		&i.Enabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listActiveSubscriptions = `-- name: ListActiveSubscriptions :many
+SELECT s.id, s.user_id, s.vehicle_id, s.channel, s.reminder_types, s.triggers, s.lead_days, s.digest,
+       u.name AS user_name, u.email AS user_email, u.ntfy_topic AS user_ntfy_topic, u.locale AS user_locale
+FROM notification_subscriptions s
+JOIN users u ON u.id = s.user_id
+WHERE s.enabled = TRUE
+ORDER BY s.user_id, s.id
+`
+
+type ListActiveSubscriptionsRow struct {
+	ID            int32
+	UserID        int32
+	VehicleID     sql.NullInt32
+	Channel       string
+	ReminderTypes []string
+	Triggers      []string
+	LeadDays      int32
+	Digest        string
+	UserName      string
+	UserEmail     sql.NullString
+	UserNtfyTopic sql.NullString
+	UserLocale    string
+}
+
+func (q *Queries) ListActiveSubscriptions(ctx context.Context) ([]ListActiveSubscriptionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSubscriptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveSubscriptionsRow
+	for rows.Next() {
+		var i ListActiveSubscriptionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.VehicleID,
+			&i.Channel,
+			pq.Array(&i.ReminderTypes),
+			pq.Array(&i.Triggers),
+			&i.LeadDays,
+			&i.Digest,
+			&i.UserName,
+			&i.UserEmail,
+			&i.UserNtfyTopic,
+			&i.UserLocale,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivityAsc = `-- name: ListActivityAsc :many
+WITH activity AS (
+    SELECT 'fuel_log' AS type, 1 AS type_rank, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC' AS occurred_at,
+           'Refuelled ' || liters::text || ' L' AS title, total_cost::float8 AS amount, odometer
+    FROM fuel_logs
+    UNION ALL
+    SELECT 'service_record', 2, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(COALESCE(NULLIF(service_type, ''), 'service')), cost::float8, odometer
+    FROM service_records
+    UNION ALL
+    SELECT 'reminder_completed', 3, id, vehicle_id, completed_at, title, 0, due_odometer
+    FROM reminders
+    WHERE completed_at IS NOT NULL
+    UNION ALL
+    SELECT 'document_upload', 4, f.id, d.vehicle_id, f.created_at,
+           d.name || ': ' || f.file_name, 0, NULL
+    FROM document_files f
+    JOIN documents d ON d.id = f.document_id
+    UNION ALL
+    SELECT 'expense', 5, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(category) || COALESCE(': ' || NULLIF(description, ''), ''), amount::float8, odometer
+    FROM expenses
+), keyed AS (
+
+
+    SELECT *, (FLOOR(EXTRACT(EPOCH FROM occurred_at) * 1000) * 8 + type_rank)::float8 AS sort_key
+    FROM activity
+    WHERE (COALESCE(cardinality($1::int[]), 0) = 0 OR vehicle_id = ANY($1::int[]))
+      AND (COALESCE(cardinality($2::text[]), 0) = 0 OR type = ANY($2::text[]))
+      AND ($3::date IS NULL OR occurred_at >= $3::date)
+      AND ($4::date IS NULL OR occurred_at < $4::date + 1)
+)
+SELECT type::text AS type, id::int AS id, COALESCE(vehicle_id, 0)::int AS vehicle_id,
+       occurred_at::timestamptz AS occurred_at, title::text AS title, amount::float8 AS amount,
+       COALESCE(odometer, 0)::int AS odometer, sort_key::float8 AS sort_key
+FROM keyed
+WHERE $5::float8 IS NULL
+   OR (sort_key, id) > ($5::float8, $6::int)
+ORDER BY sort_key, id
+LIMIT $7
+`
+
+type ListActivityAscParams struct {
+	VehicleIds []int32
+	Types      []string
+	DateFrom   sql.NullTime
+	DateTo     sql.NullTime
+	AfterKey   sql.NullFloat64
+	AfterID    sql.NullInt32
+	Limit      int32
+}
+
+type ListActivityAscRow struct {
+	Type       string
+	ID         int32
+	VehicleID  int32
+	OccurredAt time.Time
+	Title      string
+	Amount     float64
+	Odometer   int32
+	SortKey    float64
+}
+
+// Fuel logs, service records, expenses, reminder completions and document uploads across
+// vehicles, as one timeline. Fuel logs, service records and expenses only have a date, so
+// they sit at midnight UTC. No index can serve the union's order, so it is sorted in
+// full, but each direction has its own query for a plain ORDER BY.
+func (q *Queries) ListActivityAsc(ctx context.Context, arg ListActivityAscParams) ([]ListActivityAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActivityAsc,
+		pq.Array(arg.VehicleIds),
+		pq.Array(arg.Types),
+		arg.DateFrom,
+		arg.DateTo,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActivityAscRow
+	for rows.Next() {
+		var i ListActivityAscRow
+		if err := rows.Scan(
+			&i.Type,
+			&i.ID,
+			&i.VehicleID,
+			&i.OccurredAt,
+			&i.Title,
+			&i.Amount,
+			&i.Odometer,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActivityDesc = `-- name: ListActivityDesc :many
+WITH activity AS (
+    SELECT 'fuel_log' AS type, 1 AS type_rank, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC' AS occurred_at,
+           'Refuelled ' || liters::text || ' L' AS title, total_cost::float8 AS amount, odometer
+    FROM fuel_logs
+    UNION ALL
+    SELECT 'service_record', 2, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(COALESCE(NULLIF(service_type, ''), 'service')), cost::float8, odometer
+    FROM service_records
+    UNION ALL
+    SELECT 'reminder_completed', 3, id, vehicle_id, completed_at, title, 0, due_odometer
+    FROM reminders
+    WHERE completed_at IS NOT NULL
+    UNION ALL
+    SELECT 'document_upload', 4, f.id, d.vehicle_id, f.created_at,
+           d.name || ': ' || f.file_name, 0, NULL
+    FROM document_files f
+    JOIN documents d ON d.id = f.document_id
+    UNION ALL
+    SELECT 'expense', 5, id, vehicle_id,
+           date::timestamp AT TIME ZONE 'UTC',
+           INITCAP(category) || COALESCE(': ' || NULLIF(description, ''), ''), amount::float8, odometer
+    FROM expenses
+), keyed AS (
+
+
+    SELECT *, (FLOOR(EXTRACT(EPOCH FROM occurred_at) * 1000) * 8 + type_rank)::float8 AS sort_key
+    FROM activity
+    WHERE (COALESCE(cardinality($1::int[]), 0) = 0 OR vehicle_id = ANY($1::int[]))
+      AND (COALESCE(cardinality($2::text[]), 0) = 0 OR type = ANY($2::text[]))
+      AND ($3::date IS NULL OR occurred_at >= $3::date)
+      AND ($4::date IS NULL OR occurred_at < $4::date + 1)
+)
+SELECT type::text AS type, id::int AS id, COALESCE(vehicle_id, 0)::int AS vehicle_id,
+       occurred_at::timestamptz AS occurred_at, title::text AS title, amount::float8 AS amount,
+       COALESCE(odometer, 0)::int AS odometer, sort_key::float8 AS sort_key
+FROM keyed
+WHERE $5::float8 IS NULL
+   OR (sort_key, id) < ($5::float8, $6::int)
+ORDER BY sort_key DESC, id DESC
+LIMIT $7
+`
+
+type ListActivityDescParams struct {
+	VehicleIds []int32
+	Types      []string
+	DateFrom   sql.NullTime
+	DateTo     sql.NullTime
+	AfterKey   sql.NullFloat64
+	AfterID    sql.NullInt32
+	Limit      int32
+}
+
+type ListActivityDescRow struct {
+	Type       string
+	ID         int32
+	VehicleID  int32
+	OccurredAt time.Time
+	Title      string
+	Amount     float64
+	Odometer   int32
+	SortKey    float64
+}
+
+// Like ListActivityAsc, newest first.
+func (q *Queries) ListActivityDesc(ctx context.Context, arg ListActivityDescParams) ([]ListActivityDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActivityDesc,
+		pq.Array(arg.VehicleIds),
+		pq.Array(arg.Types),
+		arg.DateFrom,
+		arg.DateTo,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActivityDescRow
+	for rows.Next() {
+		var i ListActivityDescRow
+		if err := rows.Scan(
+			&i.Type,
+			&i.ID,
+			&i.VehicleID,
+			&i.OccurredAt,
+			&i.Title,
+			&i.Amount,
+			&i.Odometer,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllLoanPayments = `-- name: ListAllLoanPayments :many
+SELECT id, loan_id, date, amount, prepayment, notes, created_at FROM loan_payments
+ORDER BY loan_id, date, id
+`
+
+func (q *Queries) ListAllLoanPayments(ctx context.Context) ([]LoanPayment, error) {
+	rows, err := q.db.QueryContext(ctx, listAllLoanPayments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoanPayment
+	for rows.Next() {
+		var i LoanPayment
+		if err := rows.Scan(
+			&i.ID,
+			&i.LoanID,
+			&i.Date,
+			&i.Amount,
+			&i.Prepayment,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBudgets = `-- name: ListBudgets :many
+SELECT id, vehicle_id, category, period, amount, thresholds, created_at FROM budgets
+ORDER BY vehicle_id NULLS FIRST, category, id
+`
+
+func (q *Queries) ListBudgets(ctx context.Context) ([]Budget, error) {
+	rows, err := q.db.QueryContext(ctx, listBudgets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Budget
+	for rows.Next() {
+		var i Budget
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Category,
+			&i.Period,
+			&i.Amount,
+			pq.Array(&i.Thresholds),
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDashboardStats = `-- name: ListDashboardStats :many
+WITH fills AS (
+    SELECT id, vehicle_id, odometer, liters, full_tank,
+           odometer - LAG(odometer) OVER (PARTITION BY vehicle_id ORDER BY odometer, id) AS distance
+    FROM fuel_logs
+),
+latest_fills AS (
+    SELECT DISTINCT ON (vehicle_id) vehicle_id, distance, liters
+    FROM fills
+    WHERE full_tank AND distance > 0 AND liters > 0
+    ORDER BY vehicle_id, odometer DESC, id DESC
+)
+SELECT v.id::int AS vehicle_id,
+       GREATEST(
+           (SELECT COALESCE(MAX(odometer), 0) FROM fuel_logs f WHERE f.vehicle_id = v.id),
+           (SELECT COALESCE(MAX(odometer), 0) FROM service_records s WHERE s.vehicle_id = v.id),
+           (SELECT COALESCE(MAX(odometer), 0) FROM odometer_readings o WHERE o.vehicle_id = v.id)
+       )::int AS odometer,
+       COALESCE(lf.distance, 0)::float8 AS economy_distance,
+       COALESCE(lf.liters, 0)::float8 AS economy_liters,
+       (SELECT COALESCE(SUM(total_cost), 0) FROM fuel_logs f
+        WHERE f.vehicle_id = v.id AND f.date >= $1::date)::float8 AS fuel_cost,
+       (SELECT COALESCE(SUM(cost), 0) FROM service_records s
+        WHERE s.vehicle_id = v.id AND s.date >= $1::date)::float8 AS service_cost,
+       (SELECT COALESCE(SUM(amount), 0) FROM expenses e
+        WHERE e.vehicle_id = v.id AND e.date >= $1::date)::float8 AS expense_cost
+FROM vehicles v
+LEFT JOIN latest_fills lf ON lf.vehicle_id = v.id
+ORDER BY v.id
+`
+
+type ListDashboardStatsRow struct {
+	VehicleID       int32
+	Odometer        int32
+	EconomyDistance float64
+	EconomyLiters   float64
+	FuelCost        float64
+	ServiceCost     float64
+	ExpenseCost     float64
+}
+
+// Current odometer from any source, economy of the latest full-tank fill and spend since a
+// date, for every vehicle at once. Economy is 0/0 for vehicles without a measurable fill.
+func (q *Queries) ListDashboardStats(ctx context.Context, since time.Time) ([]ListDashboardStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDashboardStats, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDashboardStatsRow
+	for rows.Next() {
+		var i ListDashboardStatsRow
+		if err := rows.Scan(
+			&i.VehicleID,
+			&i.Odometer,
+			&i.EconomyDistance,
+			&i.EconomyLiters,
+			&i.FuelCost,
+			&i.ServiceCost,
+			&i.ExpenseCost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDocumentCategories = `-- name: ListDocumentCategories :many
+SELECT slug, name, tracks_expiry, created_at FROM document_categories
+ORDER BY name
+`
+
+func (q *Queries) ListDocumentCategories(ctx context.Context) ([]DocumentCategory, error) {
+	rows, err := q.db.QueryContext(ctx, listDocumentCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DocumentCategory
+	for rows.Next() {
+		var i DocumentCategory
+		if err := rows.Scan(
+			&i.Slug,
+			&i.Name,
+			&i.TracksExpiry,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDocumentFiles = `-- name: ListDocumentFiles :many
+SELECT id, document_id, label, file_name, content_type, size_bytes, storage_key, position, created_at, thumbnail_status, thumbnail_error FROM document_files
+WHERE document_id = $1
+ORDER BY position, id
+`
+
+func (q *Queries) ListDocumentFiles(ctx context.Context, documentID int32) ([]DocumentFile, error) {
+	rows, err := q.db.QueryContext(ctx, listDocumentFiles, documentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DocumentFile
+	for rows.Next() {
+		var i DocumentFile
+		if err := rows.Scan(
+			&i.ID,
+			&i.DocumentID,
+			&i.Label,
+			&i.FileName,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.Position,
+			&i.CreatedAt,
+			&i.ThumbnailStatus,
+			&i.ThumbnailError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDocumentFilesByDocuments = `-- name: ListDocumentFilesByDocuments :many
+SELECT id, document_id, label, file_name, content_type, size_bytes, storage_key, position, created_at, thumbnail_status, thumbnail_error FROM document_files
+WHERE document_id = ANY($1::int[])
+ORDER BY document_id, position, id
+`
+
+func (q *Queries) ListDocumentFilesByDocuments(ctx context.Context, documentIds []int32) ([]DocumentFile, error) {
+	rows, err := q.db.QueryContext(ctx, listDocumentFilesByDocuments, pq.Array(documentIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DocumentFile
+	for rows.Next() {
+		var i DocumentFile
+		if err := rows.Scan(
+			&i.ID,
+			&i.DocumentID,
+			&i.Label,
+			&i.FileName,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.Position,
+			&i.CreatedAt,
+			&i.ThumbnailStatus,
+			&i.ThumbnailError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDocumentsByVehicle = `-- name: ListDocumentsByVehicle :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE vehicle_id = $1 AND archived_at IS NULL
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListDocumentsByVehicle(ctx context.Context, vehicleID sql.NullInt32) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, listDocumentsByVehicle, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Document
+	for rows.Next() {
+		var i Document
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Name,
+			&i.Type,
+			&i.FileUrl,
+			&i.ExpiryDate,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDocumentsExpiringBetween = `-- name: ListDocumentsExpiringBetween :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE archived_at IS NULL AND expiry_date BETWEEN $1::date AND $2::date
+ORDER BY expiry_date ASC
+`
+
+type ListDocumentsExpiringBetweenParams struct {
+	FromDate time.Time
+	ToDate   time.Time
+}
+
+func (q *Queries) ListDocumentsExpiringBetween(ctx context.Context, arg ListDocumentsExpiringBetweenParams) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, listDocumentsExpiringBetween,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Document
+	for rows.Next() {
+		var i Document
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Name,
+			&i.Type,
+			&i.FileUrl,
+			&i.ExpiryDate,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueRecurringExpenses = `-- name: ListDueRecurringExpenses :many
+SELECT id, vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date, recurring_expense_id, created_at FROM expenses
+WHERE recurrence <> 'none' AND next_due_date <= $1::date
+ORDER BY next_due_date, id
+`
+
+// Recurring expenses with a copy due on or before today.
+func (q *Queries) ListDueRecurringExpenses(ctx context.Context, today time.Time) ([]Expense, error) {
+	rows, err := q.db.QueryContext(ctx, listDueRecurringExpenses, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Expense
+	for rows.Next() {
+		var i Expense
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.VendorID,
+			&i.Category,
+			&i.Date,
+			&i.Amount,
+			&i.Odometer,
+			&i.Description,
+			&i.Recurrence,
+			&i.RecurrenceEndDate,
+			&i.NextDueDate,
+			&i.RecurringExpenseID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpenseFiles = `-- name: ListExpenseFiles :many
+SELECT id, expense_id, file_name, content_type, size_bytes, storage_key, created_at FROM expense_files
+WHERE expense_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListExpenseFiles(ctx context.Context, expenseID int32) ([]ExpenseFile, error) {
+	rows, err := q.db.QueryContext(ctx, listExpenseFiles, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExpenseFile
+	for rows.Next() {
+		var i ExpenseFile
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpenseID,
+			&i.FileName,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpenseFilesByExpenses = `-- name: ListExpenseFilesByExpenses :many
+SELECT id, expense_id, file_name, content_type, size_bytes, storage_key, created_at FROM expense_files
+WHERE expense_id = ANY($1::int[])
+ORDER BY expense_id, id
+`
+
+func (q *Queries) ListExpenseFilesByExpenses(ctx context.Context, expenseIds []int32) ([]ExpenseFile, error) {
+	rows, err := q.db.QueryContext(ctx, listExpenseFilesByExpenses, pq.Array(expenseIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExpenseFile
+	for rows.Next() {
+		var i ExpenseFile
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExpenseID,
+			&i.FileName,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpenseSpend = `-- name: ListExpenseSpend :many
+SELECT category::text AS category, SUM(amount)::float8 AS amount
+FROM expenses
+WHERE ($1::int IS NULL OR vehicle_id = $1::int)
+  AND date >= $2::date AND date <= $3::date
+GROUP BY category
+ORDER BY category
+`
+
+type ListExpenseSpendParams struct {
+	VehicleID sql.NullInt32
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+type ListExpenseSpendRow struct {
+	Category string
+	Amount   float64
+}
+
+// Expense spend per category between two dates, inclusive, of one vehicle or all of them.
+func (q *Queries) ListExpenseSpend(ctx context.Context, arg ListExpenseSpendParams) ([]ListExpenseSpendRow, error) {
+	rows, err := q.db.QueryContext(ctx, listExpenseSpend,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListExpenseSpendRow
+	for rows.Next() {
+		var i ListExpenseSpendRow
+		if err := rows.Scan(
+			&i.Category,
+			&i.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpensesByAmountAsc = `-- name: ListExpensesByAmountAsc :many
+SELECT id, vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date, recurring_expense_id, created_at FROM expenses
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR amount >= $6::float8)
+  AND ($7::float8 IS NULL OR amount <= $7::float8)
+  AND ($8::text IS NULL OR category = $8::text)
+  AND ($9::int IS NULL OR vendor_id = $9::int)
+  AND ($10::float8 IS NULL
+       OR (amount, id) > ($10::float8::numeric, $11::int))
+ORDER BY amount, id
+LIMIT $12
+`
+
+type ListExpensesByAmountAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	Category    sql.NullString
+	VendorID    sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListExpensesByDateAsc, ordered by amount.
+func (q *Queries) ListExpensesByAmountAsc(ctx context.Context, arg ListExpensesByAmountAscParams) ([]Expense, error) {
+	rows, err := q.db.QueryContext(ctx, listExpensesByAmountAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.Category,
+		arg.VendorID,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Expense
+	for rows.Next() {
+		var i Expense
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.VendorID,
+			&i.Category,
+			&i.Date,
+			&i.Amount,
+			&i.Odometer,
+			&i.Description,
+			&i.Recurrence,
+			&i.RecurrenceEndDate,
+			&i.NextDueDate,
+			&i.RecurringExpenseID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpensesByAmountDesc = `-- name: ListExpensesByAmountDesc :many
+SELECT id, vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date, recurring_expense_id, created_at FROM expenses
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR amount >= $6::float8)
+  AND ($7::float8 IS NULL OR amount <= $7::float8)
+  AND ($8::text IS NULL OR category = $8::text)
+  AND ($9::int IS NULL OR vendor_id = $9::int)
+  AND ($10::float8 IS NULL
+       OR (amount, id) < ($10::float8::numeric, $11::int))
+ORDER BY amount DESC, id DESC
+LIMIT $12
+`
+
+type ListExpensesByAmountDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	Category    sql.NullString
+	VendorID    sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListExpensesByDateAsc, ordered by amount descending.
+func (q *Queries) ListExpensesByAmountDesc(ctx context.Context, arg ListExpensesByAmountDescParams) ([]Expense, error) {
+	rows, err := q.db.QueryContext(ctx, listExpensesByAmountDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.Category,
+		arg.VendorID,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Expense
+	for rows.Next() {
+		var i Expense
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.VendorID,
+			&i.Category,
+			&i.Date,
+			&i.Amount,
+			&i.Odometer,
+			&i.Description,
+			&i.Recurrence,
+			&i.RecurrenceEndDate,
+			&i.NextDueDate,
+			&i.RecurringExpenseID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpensesByDateAsc = `-- name: ListExpensesByDateAsc :many
+SELECT id, vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date, recurring_expense_id, created_at FROM expenses
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR amount >= $6::float8)
+  AND ($7::float8 IS NULL OR amount <= $7::float8)
+  AND ($8::text IS NULL OR category = $8::text)
+  AND ($9::int IS NULL OR vendor_id = $9::int)
+  AND ($10::float8 IS NULL
+       OR (date, id) > ((TIMESTAMP 'epoch' + $10::float8 * INTERVAL '1 second')::date, $11::int))
+ORDER BY date, id
+LIMIT $12
+`
+
+type ListExpensesByDateAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	Category    sql.NullString
+	VendorID    sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// One page of a vehicle's expenses, like ListFuelLogsByDateAsc.
+func (q *Queries) ListExpensesByDateAsc(ctx context.Context, arg ListExpensesByDateAscParams) ([]Expense, error) {
+	rows, err := q.db.QueryContext(ctx, listExpensesByDateAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.Category,
+		arg.VendorID,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Expense
+	for rows.Next() {
+		var i Expense
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.VendorID,
+			&i.Category,
+			&i.Date,
+			&i.Amount,
+			&i.Odometer,
+			&i.Description,
+			&i.Recurrence,
+			&i.RecurrenceEndDate,
+			&i.NextDueDate,
+			&i.RecurringExpenseID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpensesByDateDesc = `-- name: ListExpensesByDateDesc :many
+SELECT id, vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date, recurring_expense_id, created_at FROM expenses
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR amount >= $6::float8)
+  AND ($7::float8 IS NULL OR amount <= $7::float8)
+  AND ($8::text IS NULL OR category = $8::text)
+  AND ($9::int IS NULL OR vendor_id = $9::int)
+  AND ($10::float8 IS NULL
+       OR (date, id) < ((TIMESTAMP 'epoch' + $10::float8 * INTERVAL '1 second')::date, $11::int))
+ORDER BY date DESC, id DESC
+LIMIT $12
+`
+
+type ListExpensesByDateDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	Category    sql.NullString
+	VendorID    sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListExpensesByDateAsc, in descending order.
+func (q *Queries) ListExpensesByDateDesc(ctx context.Context, arg ListExpensesByDateDescParams) ([]Expense, error) {
+	rows, err := q.db.QueryContext(ctx, listExpensesByDateDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.Category,
+		arg.VendorID,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Expense
+	for rows.Next() {
+		var i Expense
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.VendorID,
+			&i.Category,
+			&i.Date,
+			&i.Amount,
+			&i.Odometer,
+			&i.Description,
+			&i.Recurrence,
+			&i.RecurrenceEndDate,
+			&i.NextDueDate,
+			&i.RecurringExpenseID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpiringDocuments = `-- name: ListExpiringDocuments :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE archived_at IS NULL AND expiry_date <= $1::date
+ORDER BY expiry_date ASC
+`
+
+// Current documents that have expired or expire on or before the given date, across all vehicles.
+func (q *Queries) ListExpiringDocuments(ctx context.Context, toDate time.Time) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiringDocuments, toDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Document
+	for rows.Next() {
+		var i Document
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Name,
+			&i.Type,
+			&i.FileUrl,
+			&i.ExpiryDate,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFixedCosts = `-- name: ListFixedCosts :many
+SELECT id, vehicle_id, category, amount, frequency, start_date, end_date, notes, created_at FROM fixed_costs
+WHERE $1::int IS NULL OR vehicle_id = $1::int
+ORDER BY vehicle_id, start_date, id
+`
+
+// Fixed costs of one vehicle, or of all of them when vehicle_id is NULL.
+func (q *Queries) ListFixedCosts(ctx context.Context, vehicleID sql.NullInt32) ([]FixedCost, error) {
+	rows, err := q.db.QueryContext(ctx, listFixedCosts, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FixedCost
+	for rows.Next() {
+		var i FixedCost
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Category,
+			&i.Amount,
+			&i.Frequency,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFixedCostsByVehicle = `-- name: ListFixedCostsByVehicle :many
+SELECT id, vehicle_id, category, amount, frequency, start_date, end_date, notes, created_at FROM fixed_costs
+WHERE vehicle_id = $1
+ORDER BY start_date DESC, id DESC
+`
+
+func (q *Queries) ListFixedCostsByVehicle(ctx context.Context, vehicleID int32) ([]FixedCost, error) {
+	rows, err := q.db.QueryContext(ctx, listFixedCostsByVehicle, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FixedCost
+	for rows.Next() {
+		var i FixedCost
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Category,
+			&i.Amount,
+			&i.Frequency,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByDateAsc = `-- name: ListFuelLogsByDateAsc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (date, id) > ((TIMESTAMP 'epoch' + $9::float8 * INTERVAL '1 second')::date, $10::int))
+ORDER BY date, id
+LIMIT $11
+`
+
+type ListFuelLogsByDateAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// One page of a vehicle's fuel logs, oldest first by date. Filters are optional, and
+// after_key/after_id continue from the last row of the previous page. There is one
+// query per sort key and direction so each reads a (vehicle_id, key, id) index in order.
+func (q *Queries) ListFuelLogsByDateAsc(ctx context.Context, arg ListFuelLogsByDateAscParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByDateAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByDateDesc = `-- name: ListFuelLogsByDateDesc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (date, id) < ((TIMESTAMP 'epoch' + $9::float8 * INTERVAL '1 second')::date, $10::int))
+ORDER BY date DESC, id DESC
+LIMIT $11
+`
+
+type ListFuelLogsByDateDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, in descending order.
+func (q *Queries) ListFuelLogsByDateDesc(ctx context.Context, arg ListFuelLogsByDateDescParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByDateDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByLitersAsc = `-- name: ListFuelLogsByLitersAsc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (liters, id) > ($9::float8::numeric, $10::int))
+ORDER BY liters, id
+LIMIT $11
+`
+
+type ListFuelLogsByLitersAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, ordered by liters.
+func (q *Queries) ListFuelLogsByLitersAsc(ctx context.Context, arg ListFuelLogsByLitersAscParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByLitersAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByLitersDesc = `-- name: ListFuelLogsByLitersDesc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (liters, id) < ($9::float8::numeric, $10::int))
+ORDER BY liters DESC, id DESC
+LIMIT $11
+`
+
+type ListFuelLogsByLitersDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, ordered by liters descending.
+func (q *Queries) ListFuelLogsByLitersDesc(ctx context.Context, arg ListFuelLogsByLitersDescParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByLitersDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByOdometerAsc = `-- name: ListFuelLogsByOdometerAsc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (odometer, id) > ($9::float8::int, $10::int))
+ORDER BY odometer, id
+LIMIT $11
+`
+
+type ListFuelLogsByOdometerAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, ordered by odometer.
+func (q *Queries) ListFuelLogsByOdometerAsc(ctx context.Context, arg ListFuelLogsByOdometerAscParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByOdometerAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByOdometerDesc = `-- name: ListFuelLogsByOdometerDesc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (odometer, id) < ($9::float8::int, $10::int))
+ORDER BY odometer DESC, id DESC
+LIMIT $11
+`
+
+type ListFuelLogsByOdometerDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, ordered by odometer descending.
+func (q *Queries) ListFuelLogsByOdometerDesc(ctx context.Context, arg ListFuelLogsByOdometerDescParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByOdometerDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByPricePerLiterAsc = `-- name: ListFuelLogsByPricePerLiterAsc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (price_per_liter, id) > ($9::float8::numeric, $10::int))
+ORDER BY price_per_liter, id
+LIMIT $11
+`
+
+type ListFuelLogsByPricePerLiterAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, ordered by price_per_liter.
+func (q *Queries) ListFuelLogsByPricePerLiterAsc(ctx context.Context, arg ListFuelLogsByPricePerLiterAscParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByPricePerLiterAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByPricePerLiterDesc = `-- name: ListFuelLogsByPricePerLiterDesc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (price_per_liter, id) < ($9::float8::numeric, $10::int))
+ORDER BY price_per_liter DESC, id DESC
+LIMIT $11
+`
+
+type ListFuelLogsByPricePerLiterDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, ordered by price_per_liter descending.
+func (q *Queries) ListFuelLogsByPricePerLiterDesc(ctx context.Context, arg ListFuelLogsByPricePerLiterDescParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByPricePerLiterDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByTotalCostAsc = `-- name: ListFuelLogsByTotalCostAsc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (total_cost, id) > ($9::float8::numeric, $10::int))
+ORDER BY total_cost, id
+LIMIT $11
+`
+
+type ListFuelLogsByTotalCostAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, ordered by total_cost.
+func (q *Queries) ListFuelLogsByTotalCostAsc(ctx context.Context, arg ListFuelLogsByTotalCostAscParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByTotalCostAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelLogsByTotalCostDesc = `-- name: ListFuelLogsByTotalCostDesc :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR total_cost >= $6::float8)
+  AND ($7::float8 IS NULL OR total_cost <= $7::float8)
+  AND ($8::bool IS NULL OR full_tank = $8::bool)
+  AND ($9::float8 IS NULL
+       OR (total_cost, id) < ($9::float8::numeric, $10::int))
+ORDER BY total_cost DESC, id DESC
+LIMIT $11
+`
+
+type ListFuelLogsByTotalCostDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	FullTank    sql.NullBool
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListFuelLogsByDateAsc, ordered by total_cost descending.
+func (q *Queries) ListFuelLogsByTotalCostDesc(ctx context.Context, arg ListFuelLogsByTotalCostDescParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelLogsByTotalCostDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.FullTank,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFuelSpendSince = `-- name: ListFuelSpendSince :many
+SELECT vehicle_id,
+    COALESCE(SUM(total_cost), 0.0)::float8 AS total_cost,
+    COALESCE(SUM(liters), 0.0)::float8 AS total_liters,
+    COUNT(*) AS fill_ups
+FROM fuel_logs
+WHERE date >= $1::date
+GROUP BY vehicle_id
+`
+
+type ListFuelSpendSinceRow struct {
+	VehicleID   sql.NullInt32
+	TotalCost   float64
+	TotalLiters float64
+	FillUps     int64
+}
+
+func (q *Queries) ListFuelSpendSince(ctx context.Context, since time.Time) ([]ListFuelSpendSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFuelSpendSince, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFuelSpendSinceRow
+	for rows.Next() {
+		var i ListFuelSpendSinceRow
+		if err := rows.Scan(
+			&i.VehicleID,
+			&i.TotalCost,
+			&i.TotalLiters,
+			&i.FillUps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFullTankEconomy = `-- name: ListFullTankEconomy :many
+WITH fills AS (
+    SELECT id, date, odometer, liters, full_tank,
+           odometer - LAG(odometer) OVER (ORDER BY odometer, id) AS distance
+    FROM fuel_logs
+    WHERE vehicle_id = $1::int
+)
+SELECT id::int AS id, date::date AS date, (distance / liters)::float8 AS economy
+FROM fills
+WHERE full_tank AND distance > 0 AND liters > 0
+ORDER BY odometer, id
+`
+
+type ListFullTankEconomyRow struct {
+	ID      int32
+	Date    time.Time
+	Economy float64
+}
+
+// Economy of each full-tank fill of a vehicle, oldest first: the distance since the
+// previous fill over the litres filled.
+func (q *Queries) ListFullTankEconomy(ctx context.Context, vehicleID int32) ([]ListFullTankEconomyRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFullTankEconomy, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFullTankEconomyRow
+	for rows.Next() {
+		var i ListFullTankEconomyRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Date,
+			&i.Economy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobRunsByStartedAtAsc = `-- name: ListJobRunsByStartedAtAsc :many
+SELECT id, job_name, trigger, status, error, started_at, finished_at FROM job_runs
+WHERE ($1::text IS NULL OR job_name = $1::text)
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::date IS NULL OR started_at >= $3::date)
+  AND ($4::date IS NULL OR started_at < $4::date + 1)
+  AND ($5::float8 IS NULL
+       OR (started_at, id) > (TIMESTAMPTZ 'epoch' + $5::float8 * INTERVAL '1 microsecond', $6::int))
+ORDER BY started_at, id
+LIMIT $7
+`
+
+type ListJobRunsByStartedAtAscParams struct {
+	JobName  sql.NullString
+	Status   sql.NullString
+	DateFrom sql.NullTime
+	DateTo   sql.NullTime
+	AfterKey sql.NullFloat64
+	AfterID  sql.NullInt32
+	Limit    int32
+}
+
+func (q *Queries) ListJobRunsByStartedAtAsc(ctx context.Context, arg ListJobRunsByStartedAtAscParams) ([]JobRun, error) {
+	rows, err := q.db.QueryContext(ctx, listJobRunsByStartedAtAsc,
+		arg.JobName,
+		arg.Status,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobRun
+	for rows.Next() {
+		var i JobRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobName,
+			&i.Trigger,
+			&i.Status,
+			&i.Error,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listJobRunsByStartedAtDesc = `-- name: ListJobRunsByStartedAtDesc :many
+SELECT id, job_name, trigger, status, error, started_at, finished_at FROM job_runs
+WHERE ($1::text IS NULL OR job_name = $1::text)
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::date IS NULL OR started_at >= $3::date)
+  AND ($4::date IS NULL OR started_at < $4::date + 1)
+  AND ($5::float8 IS NULL
+       OR (started_at, id) < (TIMESTAMPTZ 'epoch' + $5::float8 * INTERVAL '1 microsecond', $6::int))
+ORDER BY started_at DESC, id DESC
+LIMIT $7
+`
+
+type ListJobRunsByStartedAtDescParams struct {
+	JobName  sql.NullString
+	Status   sql.NullString
+	DateFrom sql.NullTime
+	DateTo   sql.NullTime
+	AfterKey sql.NullFloat64
+	AfterID  sql.NullInt32
+	Limit    int32
+}
+
+// Like ListJobRunsByStartedAtAsc, in descending order.
+func (q *Queries) ListJobRunsByStartedAtDesc(ctx context.Context, arg ListJobRunsByStartedAtDescParams) ([]JobRun, error) {
+	rows, err := q.db.QueryContext(ctx, listJobRunsByStartedAtDesc,
+		arg.JobName,
+		arg.Status,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobRun
+	for rows.Next() {
+		var i JobRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobName,
+			&i.Trigger,
+			&i.Status,
+			&i.Error,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLatestFuelLogs = `-- name: ListLatestFuelLogs :many
+SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
+WHERE vehicle_id = $1
+ORDER BY odometer DESC
+LIMIT $2
+`
+
+type ListLatestFuelLogsParams struct {
+	VehicleID sql.NullInt32
+	Limit     int32
+}
+
+func (q *Queries) ListLatestFuelLogs(ctx context.Context, arg ListLatestFuelLogsParams) ([]FuelLog, error) {
+	rows, err := q.db.QueryContext(ctx, listLatestFuelLogs,
+		arg.VehicleID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FuelLog
+	for rows.Next() {
+		var i FuelLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Liters,
+			&i.PricePerLiter,
+			&i.TotalCost,
+			&i.FullTank,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLatestJobRuns = `-- name: ListLatestJobRuns :many
+SELECT DISTINCT ON (job_name) id, job_name, trigger, status, error, started_at, finished_at FROM job_runs
+ORDER BY job_name, started_at DESC
+`
+
+func (q *Queries) ListLatestJobRuns(ctx context.Context) ([]JobRun, error) {
+	rows, err := q.db.QueryContext(ctx, listLatestJobRuns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobRun
+	for rows.Next() {
+		var i JobRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobName,
+			&i.Trigger,
+			&i.Status,
+			&i.Error,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoanPayments = `-- name: ListLoanPayments :many
+SELECT id, loan_id, date, amount, prepayment, notes, created_at FROM loan_payments
+WHERE loan_id = $1
+ORDER BY date, id
+`
+
+func (q *Queries) ListLoanPayments(ctx context.Context, loanID int32) ([]LoanPayment, error) {
+	rows, err := q.db.QueryContext(ctx, listLoanPayments, loanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoanPayment
+	for rows.Next() {
+		var i LoanPayment
+		if err := rows.Scan(
+			&i.ID,
+			&i.LoanID,
+			&i.Date,
+			&i.Amount,
+			&i.Prepayment,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoanPaymentsByVehicle = `-- name: ListLoanPaymentsByVehicle :many
+SELECT p.id, p.loan_id, p.date, p.amount, p.prepayment, p.notes, p.created_at FROM loan_payments p
+JOIN loans l ON l.id = p.loan_id
+WHERE l.vehicle_id = $1
+ORDER BY p.loan_id, p.date, p.id
+`
+
+func (q *Queries) ListLoanPaymentsByVehicle(ctx context.Context, vehicleID int32) ([]LoanPayment, error) {
+	rows, err := q.db.QueryContext(ctx, listLoanPaymentsByVehicle, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoanPayment
+	for rows.Next() {
+		var i LoanPayment
+		if err := rows.Scan(
+			&i.ID,
+			&i.LoanID,
+			&i.Date,
+			&i.Amount,
+			&i.Prepayment,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoans = `-- name: ListLoans :many
+SELECT id, vehicle_id, lender, principal, annual_rate, tenure_months, emi, start_date, first_emi_date, notes, created_at FROM loans
+ORDER BY vehicle_id, start_date, id
+`
+
+func (q *Queries) ListLoans(ctx context.Context) ([]Loan, error) {
+	rows, err := q.db.QueryContext(ctx, listLoans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Loan
+	for rows.Next() {
+		var i Loan
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Lender,
+			&i.Principal,
+			&i.AnnualRate,
+			&i.TenureMonths,
+			&i.Emi,
+			&i.StartDate,
+			&i.FirstEmiDate,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoansByVehicle = `-- name: ListLoansByVehicle :many
+SELECT id, vehicle_id, lender, principal, annual_rate, tenure_months, emi, start_date, first_emi_date, notes, created_at FROM loans
+WHERE vehicle_id = $1
+ORDER BY start_date DESC, id DESC
+`
+
+func (q *Queries) ListLoansByVehicle(ctx context.Context, vehicleID int32) ([]Loan, error) {
+	rows, err := q.db.QueryContext(ctx, listLoansByVehicle, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Loan
+	for rows.Next() {
+		var i Loan
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Lender,
+			&i.Principal,
+			&i.AnnualRate,
+			&i.TenureMonths,
+			&i.Emi,
+			&i.StartDate,
+			&i.FirstEmiDate,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationTemplates = `-- name: ListNotificationTemplates :many
+SELECT id, user_id, kind, title, body, updated_at FROM notification_templates
+`
+
+func (q *Queries) ListNotificationTemplates(ctx context.Context) ([]NotificationTemplate, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationTemplates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationTemplate
+	for rows.Next() {
+		var i NotificationTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Title,
+			&i.Body,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationTemplatesByUser = `-- name: ListNotificationTemplatesByUser :many
+SELECT id, user_id, kind, title, body, updated_at FROM notification_templates
+WHERE user_id = $1
+ORDER BY kind ASC
+`
+
+func (q *Queries) ListNotificationTemplatesByUser(ctx context.Context, userID int32) ([]NotificationTemplate, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationTemplatesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationTemplate
+	for rows.Next() {
+		var i NotificationTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Title,
+			&i.Body,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOdometerReadings = `-- name: ListOdometerReadings :many
+SELECT date::date AS date, odometer::int AS odometer
+FROM fuel_logs
+WHERE vehicle_id = $1 AND date >= $2
+UNION ALL
+SELECT date::date AS date, odometer::int AS odometer
+FROM service_records
+WHERE vehicle_id = $1 AND date >= $2
+UNION ALL
+SELECT recorded_at::date AS date, odometer::int AS odometer
+FROM odometer_readings
+WHERE vehicle_id = $1 AND recorded_at >= $2
+ORDER BY date, odometer
+`
+
+type ListOdometerReadingsParams struct {
+	VehicleID sql.NullInt32
+	Since     time.Time
+}
+
+type ListOdometerReadingsRow struct {
+	Date     time.Time
+	Odometer int32
+}
+
+// Odometer readings from fuel logs, service records and standalone readings, oldest first,
+// used to estimate driving rate.
+func (q *Queries) ListOdometerReadings(ctx context.Context, arg ListOdometerReadingsParams) ([]ListOdometerReadingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOdometerReadings,
+		arg.VehicleID,
+		arg.Since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOdometerReadingsRow
+	for rows.Next() {
+		var i ListOdometerReadingsRow
+		if err := rows.Scan(
+			&i.Date,
+			&i.Odometer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOdometerReadingsByOdometerAsc = `-- name: ListOdometerReadingsByOdometerAsc :many
+SELECT id, vehicle_id, odometer, recorded_at, source, created_at FROM odometer_readings
+WHERE vehicle_id = $1::int
+  AND ($2::text IS NULL OR source = $2::text)
+  AND ($3::date IS NULL OR recorded_at >= $3::date)
+  AND ($4::date IS NULL OR recorded_at < $4::date + 1)
+  AND ($5::int IS NULL OR odometer >= $5::int)
+  AND ($6::int IS NULL OR odometer <= $6::int)
+  AND ($7::float8 IS NULL
+       OR (odometer, id) > ($7::float8::int, $8::int))
+ORDER BY odometer, id
+LIMIT $9
+`
+
+type ListOdometerReadingsByOdometerAscParams struct {
+	VehicleID   int32
+	Source      sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListOdometerReadingsByRecordedAtAsc, ordered by odometer.
+func (q *Queries) ListOdometerReadingsByOdometerAsc(ctx context.Context, arg ListOdometerReadingsByOdometerAscParams) ([]OdometerReading, error) {
+	rows, err := q.db.QueryContext(ctx, listOdometerReadingsByOdometerAsc,
+		arg.VehicleID,
+		arg.Source,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OdometerReading
+	for rows.Next() {
+		var i OdometerReading
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Odometer,
+			&i.RecordedAt,
+			&i.Source,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOdometerReadingsByOdometerDesc = `-- name: ListOdometerReadingsByOdometerDesc :many
+SELECT id, vehicle_id, odometer, recorded_at, source, created_at FROM odometer_readings
+WHERE vehicle_id = $1::int
+  AND ($2::text IS NULL OR source = $2::text)
+  AND ($3::date IS NULL OR recorded_at >= $3::date)
+  AND ($4::date IS NULL OR recorded_at < $4::date + 1)
+  AND ($5::int IS NULL OR odometer >= $5::int)
+  AND ($6::int IS NULL OR odometer <= $6::int)
+  AND ($7::float8 IS NULL
+       OR (odometer, id) < ($7::float8::int, $8::int))
+ORDER BY odometer DESC, id DESC
+LIMIT $9
+`
+
+type ListOdometerReadingsByOdometerDescParams struct {
+	VehicleID   int32
+	Source      sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListOdometerReadingsByRecordedAtAsc, ordered by odometer descending.
+func (q *Queries) ListOdometerReadingsByOdometerDesc(ctx context.Context, arg ListOdometerReadingsByOdometerDescParams) ([]OdometerReading, error) {
+	rows, err := q.db.QueryContext(ctx, listOdometerReadingsByOdometerDesc,
+		arg.VehicleID,
+		arg.Source,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OdometerReading
+	for rows.Next() {
+		var i OdometerReading
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Odometer,
+			&i.RecordedAt,
+			&i.Source,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOdometerReadingsByRecordedAtAsc = `-- name: ListOdometerReadingsByRecordedAtAsc :many
+SELECT id, vehicle_id, odometer, recorded_at, source, created_at FROM odometer_readings
+WHERE vehicle_id = $1::int
+  AND ($2::text IS NULL OR source = $2::text)
+  AND ($3::date IS NULL OR recorded_at >= $3::date)
+  AND ($4::date IS NULL OR recorded_at < $4::date + 1)
+  AND ($5::int IS NULL OR odometer >= $5::int)
+  AND ($6::int IS NULL OR odometer <= $6::int)
+  AND ($7::float8 IS NULL
+       OR (recorded_at, id) > (TIMESTAMPTZ 'epoch' + $7::float8 * INTERVAL '1 microsecond', $8::int))
+ORDER BY recorded_at, id
+LIMIT $9
+`
+
+type ListOdometerReadingsByRecordedAtAscParams struct {
+	VehicleID   int32
+	Source      sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// One page of a vehicle's standalone odometer readings, like ListFuelLogsByDateAsc.
+func (q *Queries) ListOdometerReadingsByRecordedAtAsc(ctx context.Context, arg ListOdometerReadingsByRecordedAtAscParams) ([]OdometerReading, error) {
+	rows, err := q.db.QueryContext(ctx, listOdometerReadingsByRecordedAtAsc,
+		arg.VehicleID,
+		arg.Source,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OdometerReading
+	for rows.Next() {
+		var i OdometerReading
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Odometer,
+			&i.RecordedAt,
+			&i.Source,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOdometerReadingsByRecordedAtDesc = `-- name: ListOdometerReadingsByRecordedAtDesc :many
+SELECT id, vehicle_id, odometer, recorded_at, source, created_at FROM odometer_readings
+WHERE vehicle_id = $1::int
+  AND ($2::text IS NULL OR source = $2::text)
+  AND ($3::date IS NULL OR recorded_at >= $3::date)
+  AND ($4::date IS NULL OR recorded_at < $4::date + 1)
+  AND ($5::int IS NULL OR odometer >= $5::int)
+  AND ($6::int IS NULL OR odometer <= $6::int)
+  AND ($7::float8 IS NULL
+       OR (recorded_at, id) < (TIMESTAMPTZ 'epoch' + $7::float8 * INTERVAL '1 microsecond', $8::int))
+ORDER BY recorded_at DESC, id DESC
+LIMIT $9
+`
+
+type ListOdometerReadingsByRecordedAtDescParams struct {
+	VehicleID   int32
+	Source      sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListOdometerReadingsByRecordedAtAsc, in descending order.
+func (q *Queries) ListOdometerReadingsByRecordedAtDesc(ctx context.Context, arg ListOdometerReadingsByRecordedAtDescParams) ([]OdometerReading, error) {
+	rows, err := q.db.QueryContext(ctx, listOdometerReadingsByRecordedAtDesc,
+		arg.VehicleID,
+		arg.Source,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OdometerReading
+	for rows.Next() {
+		var i OdometerReading
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Odometer,
+			&i.RecordedAt,
+			&i.Source,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenInsights = `-- name: ListOpenInsights :many
+SELECT id, vehicle_id, kind, fuel_log_id, baseline, recent, change_percent, z_score, created_at, resolved_at FROM vehicle_insights
+WHERE vehicle_id = $1 AND resolved_at IS NULL
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListOpenInsights(ctx context.Context, vehicleID int32) ([]VehicleInsight, error) {
+	rows, err := q.db.QueryContext(ctx, listOpenInsights, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VehicleInsight
+	for rows.Next() {
+		var i VehicleInsight
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Kind,
+			&i.FuelLogID,
+			&i.Baseline,
+			&i.Recent,
+			&i.ChangePercent,
+			&i.ZScore,
+			&i.CreatedAt,
+			&i.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenReminders = `-- name: ListOpenReminders :many
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE is_completed = FALSE
+ORDER BY vehicle_id, due_date ASC
+`
+
+func (q *Queries) ListOpenReminders(ctx context.Context) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listOpenReminders)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Title,
+			&i.DueDate,
+			&i.DueOdometer,
+			&i.IsRecurring,
+			&i.IntervalKm,
+			&i.IntervalMonths,
+			&i.Notes,
+			&i.IsCompleted,
+			&i.CreatedAt,
+			&i.Type,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPartsByServiceRecord = `-- name: ListPartsByServiceRecord :many
+SELECT id, service_record_id, name, part_number, cost, link, created_at FROM parts
+WHERE service_record_id = $1
+`
+
+func (q *Queries) ListPartsByServiceRecord(ctx context.Context, serviceRecordID sql.NullInt32) ([]Part, error) {
+	rows, err := q.db.QueryContext(ctx, listPartsByServiceRecord, serviceRecordID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Part
+	for rows.Next() {
+		var i Part
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServiceRecordID,
+			&i.Name,
+			&i.PartNumber,
+			&i.Cost,
+			&i.Link,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingThumbnails = `-- name: ListPendingThumbnails :many
+SELECT id, document_id, label, file_name, content_type, size_bytes, storage_key, position, created_at, thumbnail_status, thumbnail_error FROM document_files
+WHERE thumbnail_status = 'pending'
+ORDER BY id
+LIMIT $1
+`
+
+func (q *Queries) ListPendingThumbnails(ctx context.Context, limit int32) ([]DocumentFile, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingThumbnails, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DocumentFile
+	for rows.Next() {
+		var i DocumentFile
+		if err := rows.Scan(
+			&i.ID,
+			&i.DocumentID,
+			&i.Label,
+			&i.FileName,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.Position,
+			&i.CreatedAt,
+			&i.ThumbnailStatus,
+			&i.ThumbnailError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPreviousFuelOdometers = `-- name: ListPreviousFuelOdometers :many
+SELECT f.id, COALESCE((
+    SELECT MAX(p.odometer) FROM fuel_logs p
+    WHERE p.vehicle_id = f.vehicle_id AND p.odometer < f.odometer
+), 0)::int AS previous_odometer
+FROM fuel_logs f
+WHERE f.id = ANY($1::int[])
+`
+
+type ListPreviousFuelOdometersRow struct {
+	ID               int32
+	PreviousOdometer int32
+}
+
+// The closest earlier fill-up odometer for each of the given logs, to work out mileage.
+func (q *Queries) ListPreviousFuelOdometers(ctx context.Context, ids []int32) ([]ListPreviousFuelOdometersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPreviousFuelOdometers, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPreviousFuelOdometersRow
+	for rows.Next() {
+		var i ListPreviousFuelOdometersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PreviousOdometer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentOdometerReadings = `-- name: ListRecentOdometerReadings :many
+WITH readings AS (
+    SELECT vehicle_id, date, odometer FROM fuel_logs WHERE date >= $1::date
+    UNION ALL
+    SELECT vehicle_id, date, odometer FROM service_records WHERE date >= $1::date
+    UNION ALL
+    SELECT vehicle_id, recorded_at::date, odometer FROM odometer_readings WHERE recorded_at >= $1::date
+)
+SELECT vehicle_id::int AS vehicle_id, date::date AS date, odometer::int AS odometer
+FROM readings
+WHERE vehicle_id IS NOT NULL
+ORDER BY vehicle_id, date, odometer
+`
+
+type ListRecentOdometerReadingsRow struct {
+	VehicleID int32
+	Date      time.Time
+	Odometer  int32
+}
+
+// ListOdometerReadings for every vehicle at once.
+func (q *Queries) ListRecentOdometerReadings(ctx context.Context, since time.Time) ([]ListRecentOdometerReadingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRecentOdometerReadings, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRecentOdometerReadingsRow
+	for rows.Next() {
+		var i ListRecentOdometerReadingsRow
+		if err := rows.Scan(
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemindersByCreatedAtAsc = `-- name: ListRemindersByCreatedAtAsc :many
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE vehicle_id = $1::int
+  AND ($2::bool IS NULL OR is_completed = $2::bool)
+  AND ($3::text IS NULL OR type = $3::text)
+  AND ($4::date IS NULL OR due_date >= $4::date)
+  AND ($5::date IS NULL OR due_date <= $5::date)
+  AND ($6::int IS NULL OR due_odometer >= $6::int)
+  AND ($7::int IS NULL OR due_odometer <= $7::int)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(created_at, TIMESTAMPTZ 'epoch'), id) > (TIMESTAMPTZ 'epoch' + $8::float8 * INTERVAL '1 microsecond', $9::int))
+ORDER BY COALESCE(created_at, TIMESTAMPTZ 'epoch'), id
+LIMIT $10
+`
+
+type ListRemindersByCreatedAtAscParams struct {
+	VehicleID   int32
+	IsCompleted sql.NullBool
+	Type        sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListRemindersByDueDateAsc, ordered by created_at.
+func (q *Queries) ListRemindersByCreatedAtAsc(ctx context.Context, arg ListRemindersByCreatedAtAscParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listRemindersByCreatedAtAsc,
+		arg.VehicleID,
+		arg.IsCompleted,
+		arg.Type,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Title,
+			&i.DueDate,
+			&i.DueOdometer,
+			&i.IsRecurring,
+			&i.IntervalKm,
+			&i.IntervalMonths,
+			&i.Notes,
+			&i.IsCompleted,
+			&i.CreatedAt,
+			&i.Type,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemindersByCreatedAtDesc = `-- name: ListRemindersByCreatedAtDesc :many
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE vehicle_id = $1::int
+  AND ($2::bool IS NULL OR is_completed = $2::bool)
+  AND ($3::text IS NULL OR type = $3::text)
+  AND ($4::date IS NULL OR due_date >= $4::date)
+  AND ($5::date IS NULL OR due_date <= $5::date)
+  AND ($6::int IS NULL OR due_odometer >= $6::int)
+  AND ($7::int IS NULL OR due_odometer <= $7::int)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(created_at, TIMESTAMPTZ 'epoch'), id) < (TIMESTAMPTZ 'epoch' + $8::float8 * INTERVAL '1 microsecond', $9::int))
+ORDER BY COALESCE(created_at, TIMESTAMPTZ 'epoch') DESC, id DESC
+LIMIT $10
+`
+
+type ListRemindersByCreatedAtDescParams struct {
+	VehicleID   int32
+	IsCompleted sql.NullBool
+	Type        sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListRemindersByDueDateAsc, ordered by created_at descending.
+func (q *Queries) ListRemindersByCreatedAtDesc(ctx context.Context, arg ListRemindersByCreatedAtDescParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listRemindersByCreatedAtDesc,
+		arg.VehicleID,
+		arg.IsCompleted,
+		arg.Type,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Title,
+			&i.DueDate,
+			&i.DueOdometer,
+			&i.IsRecurring,
+			&i.IntervalKm,
+			&i.IntervalMonths,
+			&i.Notes,
+			&i.IsCompleted,
+			&i.CreatedAt,
+			&i.Type,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemindersByDueDateAsc = `-- name: ListRemindersByDueDateAsc :many
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE vehicle_id = $1::int
+  AND ($2::bool IS NULL OR is_completed = $2::bool)
+  AND ($3::text IS NULL OR type = $3::text)
+  AND ($4::date IS NULL OR due_date >= $4::date)
+  AND ($5::date IS NULL OR due_date <= $5::date)
+  AND ($6::int IS NULL OR due_odometer >= $6::int)
+  AND ($7::int IS NULL OR due_odometer <= $7::int)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(due_date, 'infinity'::date), id) > (
+           CASE WHEN $8::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + $8::float8 * INTERVAL '1 second')::date END,
+           $9::int))
+ORDER BY COALESCE(due_date, 'infinity'::date), id
+LIMIT $10
+`
+
+type ListRemindersByDueDateAscParams struct {
+	VehicleID   int32
+	IsCompleted sql.NullBool
+	Type        sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// One page of a vehicle's reminders, like ListFuelLogsByDateAsc. Reminders without a due
+// date or odometer sort after those with one; the expressions match the indexes.
+func (q *Queries) ListRemindersByDueDateAsc(ctx context.Context, arg ListRemindersByDueDateAscParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listRemindersByDueDateAsc,
+		arg.VehicleID,
+		arg.IsCompleted,
+		arg.Type,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Title,
+			&i.DueDate,
+			&i.DueOdometer,
+			&i.IsRecurring,
+			&i.IntervalKm,
+			&i.IntervalMonths,
+			&i.Notes,
+			&i.IsCompleted,
+			&i.CreatedAt,
+			&i.Type,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemindersByDueDateDesc = `-- name: ListRemindersByDueDateDesc :many
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE vehicle_id = $1::int
+  AND ($2::bool IS NULL OR is_completed = $2::bool)
+  AND ($3::text IS NULL OR type = $3::text)
+  AND ($4::date IS NULL OR due_date >= $4::date)
+  AND ($5::date IS NULL OR due_date <= $5::date)
+  AND ($6::int IS NULL OR due_odometer >= $6::int)
+  AND ($7::int IS NULL OR due_odometer <= $7::int)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(due_date, 'infinity'::date), id) < (
+           CASE WHEN $8::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + $8::float8 * INTERVAL '1 second')::date END,
+           $9::int))
+ORDER BY COALESCE(due_date, 'infinity'::date) DESC, id DESC
+LIMIT $10
+`
+
+type ListRemindersByDueDateDescParams struct {
+	VehicleID   int32
+	IsCompleted sql.NullBool
+	Type        sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListRemindersByDueDateAsc, in descending order.
+func (q *Queries) ListRemindersByDueDateDesc(ctx context.Context, arg ListRemindersByDueDateDescParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listRemindersByDueDateDesc,
+		arg.VehicleID,
+		arg.IsCompleted,
+		arg.Type,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Title,
+			&i.DueDate,
+			&i.DueOdometer,
+			&i.IsRecurring,
+			&i.IntervalKm,
+			&i.IntervalMonths,
+			&i.Notes,
+			&i.IsCompleted,
+			&i.CreatedAt,
+			&i.Type,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemindersByDueOdometerAsc = `-- name: ListRemindersByDueOdometerAsc :many
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE vehicle_id = $1::int
+  AND ($2::bool IS NULL OR is_completed = $2::bool)
+  AND ($3::text IS NULL OR type = $3::text)
+  AND ($4::date IS NULL OR due_date >= $4::date)
+  AND ($5::date IS NULL OR due_date <= $5::date)
+  AND ($6::int IS NULL OR due_odometer >= $6::int)
+  AND ($7::int IS NULL OR due_odometer <= $7::int)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(due_odometer, 2147483647), id) > (LEAST($8::float8, 2147483647)::int, $9::int))
+ORDER BY COALESCE(due_odometer, 2147483647), id
+LIMIT $10
+`
+
+type ListRemindersByDueOdometerAscParams struct {
+	VehicleID   int32
+	IsCompleted sql.NullBool
+	Type        sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListRemindersByDueDateAsc, ordered by due_odometer.
+func (q *Queries) ListRemindersByDueOdometerAsc(ctx context.Context, arg ListRemindersByDueOdometerAscParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listRemindersByDueOdometerAsc,
+		arg.VehicleID,
+		arg.IsCompleted,
+		arg.Type,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Title,
+			&i.DueDate,
+			&i.DueOdometer,
+			&i.IsRecurring,
+			&i.IntervalKm,
+			&i.IntervalMonths,
+			&i.Notes,
+			&i.IsCompleted,
+			&i.CreatedAt,
+			&i.Type,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemindersByDueOdometerDesc = `-- name: ListRemindersByDueOdometerDesc :many
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE vehicle_id = $1::int
+  AND ($2::bool IS NULL OR is_completed = $2::bool)
+  AND ($3::text IS NULL OR type = $3::text)
+  AND ($4::date IS NULL OR due_date >= $4::date)
+  AND ($5::date IS NULL OR due_date <= $5::date)
+  AND ($6::int IS NULL OR due_odometer >= $6::int)
+  AND ($7::int IS NULL OR due_odometer <= $7::int)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(due_odometer, 2147483647), id) < (LEAST($8::float8, 2147483647)::int, $9::int))
+ORDER BY COALESCE(due_odometer, 2147483647) DESC, id DESC
+LIMIT $10
+`
+
+type ListRemindersByDueOdometerDescParams struct {
+	VehicleID   int32
+	IsCompleted sql.NullBool
+	Type        sql.NullString
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListRemindersByDueDateAsc, ordered by due_odometer descending.
+func (q *Queries) ListRemindersByDueOdometerDesc(ctx context.Context, arg ListRemindersByDueOdometerDescParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listRemindersByDueOdometerDesc,
+		arg.VehicleID,
+		arg.IsCompleted,
+		arg.Type,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Title,
+			&i.DueDate,
+			&i.DueOdometer,
+			&i.IsRecurring,
+			&i.IntervalKm,
+			&i.IntervalMonths,
+			&i.Notes,
+			&i.IsCompleted,
+			&i.CreatedAt,
+			&i.Type,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRemindersByVehicle = `-- name: ListRemindersByVehicle :many
+SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at FROM reminders
+WHERE vehicle_id = $1 AND is_completed = FALSE
+ORDER BY due_date ASC
+`
+
+func (q *Queries) ListRemindersByVehicle(ctx context.Context, vehicleID sql.NullInt32) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listRemindersByVehicle, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Title,
+			&i.DueDate,
+			&i.DueOdometer,
+			&i.IsRecurring,
+			&i.IntervalKm,
+			&i.IntervalMonths,
+			&i.Notes,
+			&i.IsCompleted,
+			&i.CreatedAt,
+			&i.Type,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceRecordsBetween = `-- name: ListServiceRecordsBetween :many
+SELECT id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date FROM service_records
+WHERE vehicle_id = $1::int
+  AND date >= $2::date AND date <= $3::date
+ORDER BY date, odometer, id
+`
+
+type ListServiceRecordsBetweenParams struct {
+	VehicleID int32
+	DateFrom  time.Time
+	DateTo    time.Time
+}
+
+func (q *Queries) ListServiceRecordsBetween(ctx context.Context, arg ListServiceRecordsBetweenParams) ([]ServiceRecord, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceRecordsBetween,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceRecord
+	for rows.Next() {
+		var i ServiceRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Cost,
+			&i.Notes,
+			&i.ServiceType,
+			&i.CreatedAt,
+			&i.DocumentUrl,
+			&i.CompletedDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceRecordsByCostAsc = `-- name: ListServiceRecordsByCostAsc :many
+SELECT id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date FROM service_records
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR cost >= $6::float8)
+  AND ($7::float8 IS NULL OR cost <= $7::float8)
+  AND ($8::text IS NULL OR service_type = $8::text)
+  AND ($9::float8 IS NULL
+       OR (cost, id) > ($9::float8::numeric, $10::int))
+ORDER BY cost, id
+LIMIT $11
+`
+
+type ListServiceRecordsByCostAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	ServiceType sql.NullString
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListServiceRecordsByDateAsc, ordered by cost.
+func (q *Queries) ListServiceRecordsByCostAsc(ctx context.Context, arg ListServiceRecordsByCostAscParams) ([]ServiceRecord, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceRecordsByCostAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.ServiceType,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceRecord
+	for rows.Next() {
+		var i ServiceRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Cost,
+			&i.Notes,
+			&i.ServiceType,
+			&i.CreatedAt,
+			&i.DocumentUrl,
+			&i.CompletedDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceRecordsByCostDesc = `-- name: ListServiceRecordsByCostDesc :many
+SELECT id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date FROM service_records
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR cost >= $6::float8)
+  AND ($7::float8 IS NULL OR cost <= $7::float8)
+  AND ($8::text IS NULL OR service_type = $8::text)
+  AND ($9::float8 IS NULL
+       OR (cost, id) < ($9::float8::numeric, $10::int))
+ORDER BY cost DESC, id DESC
+LIMIT $11
+`
+
+type ListServiceRecordsByCostDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	ServiceType sql.NullString
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListServiceRecordsByDateAsc, ordered by cost descending.
+func (q *Queries) ListServiceRecordsByCostDesc(ctx context.Context, arg ListServiceRecordsByCostDescParams) ([]ServiceRecord, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceRecordsByCostDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.ServiceType,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceRecord
+	for rows.Next() {
+		var i ServiceRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Cost,
+			&i.Notes,
+			&i.ServiceType,
+			&i.CreatedAt,
+			&i.DocumentUrl,
+			&i.CompletedDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceRecordsByDateAsc = `-- name: ListServiceRecordsByDateAsc :many
+SELECT id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date FROM service_records
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR cost >= $6::float8)
+  AND ($7::float8 IS NULL OR cost <= $7::float8)
+  AND ($8::text IS NULL OR service_type = $8::text)
+  AND ($9::float8 IS NULL
+       OR (date, id) > ((TIMESTAMP 'epoch' + $9::float8 * INTERVAL '1 second')::date, $10::int))
+ORDER BY date, id
+LIMIT $11
+`
+
+type ListServiceRecordsByDateAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	ServiceType sql.NullString
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// One page of a vehicle's service records, like ListFuelLogsByDateAsc.
+func (q *Queries) ListServiceRecordsByDateAsc(ctx context.Context, arg ListServiceRecordsByDateAscParams) ([]ServiceRecord, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceRecordsByDateAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.ServiceType,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceRecord
+	for rows.Next() {
+		var i ServiceRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Cost,
+			&i.Notes,
+			&i.ServiceType,
+			&i.CreatedAt,
+			&i.DocumentUrl,
+			&i.CompletedDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceRecordsByDateDesc = `-- name: ListServiceRecordsByDateDesc :many
+SELECT id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date FROM service_records
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR cost >= $6::float8)
+  AND ($7::float8 IS NULL OR cost <= $7::float8)
+  AND ($8::text IS NULL OR service_type = $8::text)
+  AND ($9::float8 IS NULL
+       OR (date, id) < ((TIMESTAMP 'epoch' + $9::float8 * INTERVAL '1 second')::date, $10::int))
+ORDER BY date DESC, id DESC
+LIMIT $11
+`
+
+type ListServiceRecordsByDateDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	ServiceType sql.NullString
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListServiceRecordsByDateAsc, in descending order.
+func (q *Queries) ListServiceRecordsByDateDesc(ctx context.Context, arg ListServiceRecordsByDateDescParams) ([]ServiceRecord, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceRecordsByDateDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.ServiceType,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceRecord
+	for rows.Next() {
+		var i ServiceRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Cost,
+			&i.Notes,
+			&i.ServiceType,
+			&i.CreatedAt,
+			&i.DocumentUrl,
+			&i.CompletedDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceRecordsByOdometerAsc = `-- name: ListServiceRecordsByOdometerAsc :many
+SELECT id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date FROM service_records
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR cost >= $6::float8)
+  AND ($7::float8 IS NULL OR cost <= $7::float8)
+  AND ($8::text IS NULL OR service_type = $8::text)
+  AND ($9::float8 IS NULL
+       OR (odometer, id) > ($9::float8::int, $10::int))
+ORDER BY odometer, id
+LIMIT $11
+`
+
+type ListServiceRecordsByOdometerAscParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	ServiceType sql.NullString
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListServiceRecordsByDateAsc, ordered by odometer.
+func (q *Queries) ListServiceRecordsByOdometerAsc(ctx context.Context, arg ListServiceRecordsByOdometerAscParams) ([]ServiceRecord, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceRecordsByOdometerAsc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.ServiceType,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceRecord
+	for rows.Next() {
+		var i ServiceRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Cost,
+			&i.Notes,
+			&i.ServiceType,
+			&i.CreatedAt,
+			&i.DocumentUrl,
+			&i.CompletedDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceRecordsByOdometerDesc = `-- name: ListServiceRecordsByOdometerDesc :many
+SELECT id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date FROM service_records
+WHERE vehicle_id = $1::int
+  AND ($2::date IS NULL OR date >= $2::date)
+  AND ($3::date IS NULL OR date <= $3::date)
+  AND ($4::int IS NULL OR odometer >= $4::int)
+  AND ($5::int IS NULL OR odometer <= $5::int)
+  AND ($6::float8 IS NULL OR cost >= $6::float8)
+  AND ($7::float8 IS NULL OR cost <= $7::float8)
+  AND ($8::text IS NULL OR service_type = $8::text)
+  AND ($9::float8 IS NULL
+       OR (odometer, id) < ($9::float8::int, $10::int))
+ORDER BY odometer DESC, id DESC
+LIMIT $11
+`
+
+type ListServiceRecordsByOdometerDescParams struct {
+	VehicleID   int32
+	DateFrom    sql.NullTime
+	DateTo      sql.NullTime
+	OdometerMin sql.NullInt32
+	OdometerMax sql.NullInt32
+	CostMin     sql.NullFloat64
+	CostMax     sql.NullFloat64
+	ServiceType sql.NullString
+	AfterKey    sql.NullFloat64
+	AfterID     sql.NullInt32
+	Limit       int32
+}
+
+// Like ListServiceRecordsByDateAsc, ordered by odometer descending.
+func (q *Queries) ListServiceRecordsByOdometerDesc(ctx context.Context, arg ListServiceRecordsByOdometerDescParams) ([]ServiceRecord, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceRecordsByOdometerDesc,
+		arg.VehicleID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.OdometerMin,
+		arg.OdometerMax,
+		arg.CostMin,
+		arg.CostMax,
+		arg.ServiceType,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceRecord
+	for rows.Next() {
+		var i ServiceRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Odometer,
+			&i.Cost,
+			&i.Notes,
+			&i.ServiceType,
+			&i.CreatedAt,
+			&i.DocumentUrl,
+			&i.CompletedDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscriptionsByUser = `-- name: ListSubscriptionsByUser :many
+SELECT id, user_id, vehicle_id, channel, reminder_types, triggers, lead_days, digest, enabled, created_at FROM notification_subscriptions
+WHERE user_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListSubscriptionsByUser(ctx context.Context, userID int32) ([]NotificationSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listSubscriptionsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationSubscription
+	for rows.Next() {
+		var i NotificationSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.VehicleID,
+			&i.Channel,
+			pq.Array(&i.ReminderTypes),
+			pq.Array(&i.Triggers),
+			&i.LeadDays,
+			&i.Digest,
+			&i.Enabled,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, name, email, ntfy_topic, created_at, locale, feed_token FROM users
+ORDER BY name ASC
+`
+
+func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.NtfyTopic,
+			&i.CreatedAt,
+			&i.Locale,
+			&i.FeedToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listValuationsByVehicle = `-- name: ListValuationsByVehicle :many
+SELECT id, vehicle_id, date, value, source, notes, created_at FROM vehicle_valuations
+WHERE vehicle_id = $1
+ORDER BY date, id
+`
+
+func (q *Queries) ListValuationsByVehicle(ctx context.Context, vehicleID int32) ([]VehicleValuation, error) {
+	rows, err := q.db.QueryContext(ctx, listValuationsByVehicle, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VehicleValuation
+	for rows.Next() {
+		var i VehicleValuation
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Date,
+			&i.Value,
+			&i.Source,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVehicles = `-- name: ListVehicles :many
+SELECT id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at, fuel_type, distance_unit, purchase_price, purchase_date, depreciation_model, depreciation_rate, residual_value, status, sale_date, sale_price, buyer_notes FROM vehicles
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListVehicles(ctx context.Context) ([]Vehicle, error) {
+	rows, err := q.db.QueryContext(ctx, listVehicles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vehicle
+	for rows.Next() {
+		var i Vehicle
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Make,
+			&i.Model,
+			&i.Year,
+			&i.Type,
+			&i.Vin,
+			&i.LicensePlate,
+			&i.ImageUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FuelType,
+			&i.DistanceUnit,
+			&i.PurchasePrice,
+			&i.PurchaseDate,
+			&i.DepreciationModel,
+			&i.DepreciationRate,
+			&i.ResidualValue,
+			&i.Status,
+			&i.SaleDate,
+			&i.SalePrice,
+			&i.BuyerNotes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVendors = `-- name: ListVendors :many
+SELECT id, name, website, phone, address, notes, created_at FROM vendors
+ORDER BY LOWER(name), id
+`
+
+func (q *Queries) ListVendors(ctx context.Context) ([]Vendor, error) {
+	rows, err := q.db.QueryContext(ctx, listVendors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vendor
+	for rows.Next() {
+		var i Vendor
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Website,
+			&i.Phone,
+			&i.Address,
+			&i.Notes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveriesByCreatedAtAsc = `-- name: ListWebhookDeliveriesByCreatedAtAsc :many
+SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, response_status, error, created_at, delivered_at FROM webhook_deliveries
+WHERE webhook_id = $1::int
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::text IS NULL OR event_type = $3::text)
+  AND ($4::date IS NULL OR created_at >= $4::date)
+  AND ($5::date IS NULL OR created_at < $5::date + 1)
+  AND ($6::float8 IS NULL
+       OR (created_at, id) > (TIMESTAMPTZ 'epoch' + $6::float8 * INTERVAL '1 microsecond', $7::int))
+ORDER BY created_at, id
+LIMIT $8
+`
+
+type ListWebhookDeliveriesByCreatedAtAscParams struct {
+	WebhookID int32
+	Status    sql.NullString
+	EventType sql.NullString
+	DateFrom  sql.NullTime
+	DateTo    sql.NullTime
+	AfterKey  sql.NullFloat64
+	AfterID   sql.NullInt32
+	Limit     int32
+}
+
+func (q *Queries) ListWebhookDeliveriesByCreatedAtAsc(ctx context.Context, arg ListWebhookDeliveriesByCreatedAtAscParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveriesByCreatedAtAsc,
+		arg.WebhookID,
+		arg.Status,
+		arg.EventType,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.ResponseStatus,
+			&i.Error,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveriesByCreatedAtDesc = `-- name: ListWebhookDeliveriesByCreatedAtDesc :many
+SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, response_status, error, created_at, delivered_at FROM webhook_deliveries
+WHERE webhook_id = $1::int
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::text IS NULL OR event_type = $3::text)
+  AND ($4::date IS NULL OR created_at >= $4::date)
+  AND ($5::date IS NULL OR created_at < $5::date + 1)
+  AND ($6::float8 IS NULL
+       OR (created_at, id) < (TIMESTAMPTZ 'epoch' + $6::float8 * INTERVAL '1 microsecond', $7::int))
+ORDER BY created_at DESC, id DESC
+LIMIT $8
+`
+
+type ListWebhookDeliveriesByCreatedAtDescParams struct {
+	WebhookID int32
+	Status    sql.NullString
+	EventType sql.NullString
+	DateFrom  sql.NullTime
+	DateTo    sql.NullTime
+	AfterKey  sql.NullFloat64
+	AfterID   sql.NullInt32
+	Limit     int32
+}
+
+// Like ListWebhookDeliveriesByCreatedAtAsc, in descending order.
+func (q *Queries) ListWebhookDeliveriesByCreatedAtDesc(ctx context.Context, arg ListWebhookDeliveriesByCreatedAtDescParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveriesByCreatedAtDesc,
+		arg.WebhookID,
+		arg.Status,
+		arg.EventType,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.ResponseStatus,
+			&i.Error,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhooks = `-- name: ListWebhooks :many
+SELECT id, url, secret, event_types, description, enabled, created_at FROM webhooks
+ORDER BY id ASC
+`
+
+func (q *Queries) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.EventTypes),
+			&i.Description,
+			&i.Enabled,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordBudgetAlert = `-- name: RecordBudgetAlert :execrows
+INSERT INTO budget_alerts (budget_id, period_start, threshold)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+type RecordBudgetAlertParams struct {
+	BudgetID    int32
+	PeriodStart time.Time
+	Threshold   int32
+}
+
+func (q *Queries) RecordBudgetAlert(ctx context.Context, arg RecordBudgetAlertParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordBudgetAlert,
+		arg.BudgetID,
+		arg.PeriodStart,
+		arg.Threshold,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordDocumentAlert = `-- name: RecordDocumentAlert :execrows
+INSERT INTO document_alerts (document_id, expiry_date, level, target)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING
+`
+
+type RecordDocumentAlertParams struct {
+	DocumentID int32
+	ExpiryDate time.Time
+	Level      string
+	Target     string
+}
+
+func (q *Queries) RecordDocumentAlert(ctx context.Context, arg RecordDocumentAlertParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordDocumentAlert,
+		arg.DocumentID,
+		arg.ExpiryDate,
+		arg.Level,
+		arg.Target,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordLoanReminder = `-- name: RecordLoanReminder :execrows
+INSERT INTO loan_reminders (loan_id, due_date)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type RecordLoanReminderParams struct {
+	LoanID  int32
+	DueDate time.Time
+}
+
+func (q *Queries) RecordLoanReminder(ctx context.Context, arg RecordLoanReminderParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordLoanReminder,
+		arg.LoanID,
+		arg.DueDate,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordWebhookAttempt = `-- name: RecordWebhookAttempt :exec
+UPDATE webhook_deliveries
+SET status = $2, attempts = attempts + 1, next_attempt_at = $3, response_status = $4, error = $5,
+    delivered_at = CASE WHEN $2 = 'success' THEN NOW() ELSE delivered_at END
+WHERE id = $1
+`
+
+type RecordWebhookAttemptParams struct {
+	ID             int32
+	Status         string
+	NextAttemptAt  time.Time
+	ResponseStatus sql.NullInt32
+	Error          sql.NullString
+}
+
+func (q *Queries) RecordWebhookAttempt(ctx context.Context, arg RecordWebhookAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, recordWebhookAttempt,
+		arg.ID,
+		arg.Status,
+		arg.NextAttemptAt,
+		arg.ResponseStatus,
+		arg.Error,
+	)
+	return err
+}
+
+const resolveInsights = `-- name: ResolveInsights :execrows
+UPDATE vehicle_insights
+SET resolved_at = NOW()
+WHERE vehicle_id = $1 AND kind = $2 AND resolved_at IS NULL
+`
+
+type ResolveInsightsParams struct {
+	VehicleID int32
+	Kind      string
+}
+
+func (q *Queries) ResolveInsights(ctx context.Context, arg ResolveInsightsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resolveInsights,
+		arg.VehicleID,
+		arg.Kind,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getVehicle = `-- name: GetVehicle :one
-SELECT id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at FROM vehicles
-WHERE id = $1 LIMIT 1
+const retryWebhookDelivery = `-- name: RetryWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending', next_attempt_at = NOW()
+WHERE id = $1
+RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, response_status, error, created_at, delivered_at
 `
 
-func (q *Queries) GetVehicle(ctx context.Context, id int32) (Vehicle, error) {
-	row := q.db.QueryRowContext(ctx, getVehicle, id)
-	var i Vehicle
+func (q *Queries) RetryWebhookDelivery(ctx context.Context, id int32) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, retryWebhookDelivery, id)
+	var i WebhookDelivery
 	err := row.Scan(
 		&i.ID,
-		&i.Name,
-		&i.Make,
-		&i.Model,
-		&i.Year,
-		&i.Type,
-		&i.Vin,
-		&i.LicensePlate,
-		&i.ImageUrl,
+		&i.WebhookID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.ResponseStatus,
+		&i.Error,
 		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.DeliveredAt,
 	)
 	return i, err
 }
 
-const getVehicleStats = `-- name: GetVehicleStats :one
-SELECT
-    (SELECT COALESCE(SUM(total_cost), 0.0)::float8 FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_fuel_cost,
-    (SELECT COALESCE(SUM(cost), 0.0)::float8 FROM service_records WHERE service_records.vehicle_id = $1) AS total_service_cost,
-    (SELECT COALESCE(SUM(liters), 0.0)::float8 FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_liters,
-    (SELECT COUNT(*) FROM service_records WHERE service_records.vehicle_id = $1) AS total_services,
-    (SELECT COUNT(*) FROM fuel_logs WHERE fuel_logs.vehicle_id = $1) AS total_fuel_logs
+const searchDocumentsByCreatedAtAsc = `-- name: SearchDocumentsByCreatedAtAsc :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE ($1::int IS NULL OR vehicle_id = $1::int)
+  AND ($2::text IS NULL OR category = $2::text)
+  AND ($3::text IS NULL OR $3::text = ANY(tags))
+  AND ($4::date IS NULL OR expiry_date >= $4::date)
+  AND ($5::date IS NULL OR expiry_date <= $5::date)
+  AND ($6::text IS NULL
+       OR name ILIKE '%' || $6::text || '%'
+       OR issuer ILIKE '%' || $6::text || '%'
+       OR policy_number ILIKE '%' || $6::text || '%'
+       OR registration_number ILIKE '%' || $6::text || '%'
+       OR notes ILIKE '%' || $6::text || '%')
+  AND ($7::bool OR archived_at IS NULL)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(created_at, TIMESTAMPTZ 'epoch'), id) > (TIMESTAMPTZ 'epoch' + $8::float8 * INTERVAL '1 microsecond', $9::int))
+ORDER BY COALESCE(created_at, TIMESTAMPTZ 'epoch'), id
+LIMIT $10
 `
 
-type GetVehicleStatsRow struct {
-	TotalFuelCost    float64
-	TotalServiceCost float64
-	TotalLiters      float64
-	TotalServices    int64
-	TotalFuelLogs    int64
+type SearchDocumentsByCreatedAtAscParams struct {
+	VehicleID       sql.NullInt32
+	Category        sql.NullString
+	Tag             sql.NullString
+	ExpiresFrom     sql.NullTime
+	ExpiresTo       sql.NullTime
+	Query           sql.NullString
+	IncludeArchived bool
+	AfterKey        sql.NullFloat64
+	AfterID         sql.NullInt32
+	Limit           int32
 }
 
-func (q *Queries) GetVehicleStats(ctx context.Context, vehicleID sql.NullInt32) (GetVehicleStatsRow, error) {
-	row := q.db.QueryRowContext(ctx, getVehicleStats, vehicleID)
-	var i GetVehicleStatsRow
-	err := row.Scan(
-		&i.TotalFuelCost,
-		&i.TotalServiceCost,
-		&i.TotalLiters,
-		&i.TotalServices,
-		&i.TotalFuelLogs,
+// Filters are optional; q matches name, issuer, policy/registration numbers and notes.
+// Documents without an expiry or issue date sort after the rest.
+func (q *Queries) SearchDocumentsByCreatedAtAsc(ctx context.Context, arg SearchDocumentsByCreatedAtAscParams) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, searchDocumentsByCreatedAtAsc,
+		arg.VehicleID,
+		arg.Category,
+		arg.Tag,
+		arg.ExpiresFrom,
+		arg.ExpiresTo,
+		arg.Query,
+		arg.IncludeArchived,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Document
+	for rows.Next() {
+		var i Document
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Name,
+			&i.Type,
+			&i.FileUrl,
+			&i.ExpiryDate,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const listDocumentsByVehicle = `-- name: ListDocumentsByVehicle :many
-SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at FROM documents
-WHERE vehicle_id = $1
-ORDER BY created_at DESC
+const searchDocumentsByCreatedAtDesc = `-- name: SearchDocumentsByCreatedAtDesc :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE ($1::int IS NULL OR vehicle_id = $1::int)
+  AND ($2::text IS NULL OR category = $2::text)
+  AND ($3::text IS NULL OR $3::text = ANY(tags))
+  AND ($4::date IS NULL OR expiry_date >= $4::date)
+  AND ($5::date IS NULL OR expiry_date <= $5::date)
+  AND ($6::text IS NULL
+       OR name ILIKE '%' || $6::text || '%'
+       OR issuer ILIKE '%' || $6::text || '%'
+       OR policy_number ILIKE '%' || $6::text || '%'
+       OR registration_number ILIKE '%' || $6::text || '%'
+       OR notes ILIKE '%' || $6::text || '%')
+  AND ($7::bool OR archived_at IS NULL)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(created_at, TIMESTAMPTZ 'epoch'), id) < (TIMESTAMPTZ 'epoch' + $8::float8 * INTERVAL '1 microsecond', $9::int))
+ORDER BY COALESCE(created_at, TIMESTAMPTZ 'epoch') DESC, id DESC
+LIMIT $10
 `
 
-func (q *Queries) ListDocumentsByVehicle(ctx context.Context, vehicleID sql.NullInt32) ([]Document, error) {
-	rows, err := q.db.QueryContext(ctx, listDocumentsByVehicle, vehicleID)
+type SearchDocumentsByCreatedAtDescParams struct {
+	VehicleID       sql.NullInt32
+	Category        sql.NullString
+	Tag             sql.NullString
+	ExpiresFrom     sql.NullTime
+	ExpiresTo       sql.NullTime
+	Query           sql.NullString
+	IncludeArchived bool
+	AfterKey        sql.NullFloat64
+	AfterID         sql.NullInt32
+	Limit           int32
+}
+
+// Like SearchDocumentsByCreatedAtAsc, in descending order.
+func (q *Queries) SearchDocumentsByCreatedAtDesc(ctx context.Context, arg SearchDocumentsByCreatedAtDescParams) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, searchDocumentsByCreatedAtDesc,
+		arg.VehicleID,
+		arg.Category,
+		arg.Tag,
+		arg.ExpiresFrom,
+		arg.ExpiresTo,
+		arg.Query,
+		arg.IncludeArchived,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -395,6 +7333,14 @@ func (q *Queries) ListDocumentsByVehicle(ctx context.Context, vehicleID sql.Null
 			&i.ExpiryDate,
 			&i.Notes,
 			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
 		); err != nil {
 			return nil, err
 		}
@@ -409,32 +7355,80 @@ func (q *Queries) ListDocumentsByVehicle(ctx context.Context, vehicleID sql.Null
 	return items, nil
 }
 
-const listFuelLogsByVehicle = `-- name: ListFuelLogsByVehicle :many
-SELECT id, vehicle_id, date, odometer, liters, price_per_liter, total_cost, full_tank, notes, created_at FROM fuel_logs
-WHERE vehicle_id = $1
-ORDER BY date DESC
+const searchDocumentsByExpiryDateAsc = `-- name: SearchDocumentsByExpiryDateAsc :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE ($1::int IS NULL OR vehicle_id = $1::int)
+  AND ($2::text IS NULL OR category = $2::text)
+  AND ($3::text IS NULL OR $3::text = ANY(tags))
+  AND ($4::date IS NULL OR expiry_date >= $4::date)
+  AND ($5::date IS NULL OR expiry_date <= $5::date)
+  AND ($6::text IS NULL
+       OR name ILIKE '%' || $6::text || '%'
+       OR issuer ILIKE '%' || $6::text || '%'
+       OR policy_number ILIKE '%' || $6::text || '%'
+       OR registration_number ILIKE '%' || $6::text || '%'
+       OR notes ILIKE '%' || $6::text || '%')
+  AND ($7::bool OR archived_at IS NULL)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(expiry_date, 'infinity'::date), id) > (
+           CASE WHEN $8::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + $8::float8 * INTERVAL '1 second')::date END,
+           $9::int))
+ORDER BY COALESCE(expiry_date, 'infinity'::date), id
+LIMIT $10
 `
 
-func (q *Queries) ListFuelLogsByVehicle(ctx context.Context, vehicleID sql.NullInt32) ([]FuelLog, error) {
-	rows, err := q.db.QueryContext(ctx, listFuelLogsByVehicle, vehicleID)
+type SearchDocumentsByExpiryDateAscParams struct {
+	VehicleID       sql.NullInt32
+	Category        sql.NullString
+	Tag             sql.NullString
+	ExpiresFrom     sql.NullTime
+	ExpiresTo       sql.NullTime
+	Query           sql.NullString
+	IncludeArchived bool
+	AfterKey        sql.NullFloat64
+	AfterID         sql.NullInt32
+	Limit           int32
+}
+
+// Like SearchDocumentsByCreatedAtAsc, ordered by expiry_date.
+func (q *Queries) SearchDocumentsByExpiryDateAsc(ctx context.Context, arg SearchDocumentsByExpiryDateAscParams) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, searchDocumentsByExpiryDateAsc,
+		arg.VehicleID,
+		arg.Category,
+		arg.Tag,
+		arg.ExpiresFrom,
+		arg.ExpiresTo,
+		arg.Query,
+		arg.IncludeArchived,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []FuelLog
+	var items []Document
 	for rows.Next() {
-		var i FuelLog
+		var i Document
 		if err := rows.Scan(
 			&i.ID,
 			&i.VehicleID,
-			&i.Date,
-			&i.Odometer,
-			&i.Liters,
-			&i.PricePerLiter,
-			&i.TotalCost,
-			&i.FullTank,
+			&i.Name,
+			&i.Type,
+			&i.FileUrl,
+			&i.ExpiryDate,
 			&i.Notes,
 			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
 		); err != nil {
 			return nil, err
 		}
@@ -449,28 +7443,80 @@ func (q *Queries) ListFuelLogsByVehicle(ctx context.Context, vehicleID sql.NullI
 	return items, nil
 }
 
-const listPartsByServiceRecord = `-- name: ListPartsByServiceRecord :many
-SELECT id, service_record_id, name, part_number, cost, link, created_at FROM parts
-WHERE service_record_id = $1
+const searchDocumentsByExpiryDateDesc = `-- name: SearchDocumentsByExpiryDateDesc :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE ($1::int IS NULL OR vehicle_id = $1::int)
+  AND ($2::text IS NULL OR category = $2::text)
+  AND ($3::text IS NULL OR $3::text = ANY(tags))
+  AND ($4::date IS NULL OR expiry_date >= $4::date)
+  AND ($5::date IS NULL OR expiry_date <= $5::date)
+  AND ($6::text IS NULL
+       OR name ILIKE '%' || $6::text || '%'
+       OR issuer ILIKE '%' || $6::text || '%'
+       OR policy_number ILIKE '%' || $6::text || '%'
+       OR registration_number ILIKE '%' || $6::text || '%'
+       OR notes ILIKE '%' || $6::text || '%')
+  AND ($7::bool OR archived_at IS NULL)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(expiry_date, 'infinity'::date), id) < (
+           CASE WHEN $8::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + $8::float8 * INTERVAL '1 second')::date END,
+           $9::int))
+ORDER BY COALESCE(expiry_date, 'infinity'::date) DESC, id DESC
+LIMIT $10
 `
 
-func (q *Queries) ListPartsByServiceRecord(ctx context.Context, serviceRecordID sql.NullInt32) ([]Part, error) {
-	rows, err := q.db.QueryContext(ctx, listPartsByServiceRecord, serviceRecordID)
+type SearchDocumentsByExpiryDateDescParams struct {
+	VehicleID       sql.NullInt32
+	Category        sql.NullString
+	Tag             sql.NullString
+	ExpiresFrom     sql.NullTime
+	ExpiresTo       sql.NullTime
+	Query           sql.NullString
+	IncludeArchived bool
+	AfterKey        sql.NullFloat64
+	AfterID         sql.NullInt32
+	Limit           int32
+}
+
+// Like SearchDocumentsByCreatedAtAsc, ordered by expiry_date descending.
+func (q *Queries) SearchDocumentsByExpiryDateDesc(ctx context.Context, arg SearchDocumentsByExpiryDateDescParams) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, searchDocumentsByExpiryDateDesc,
+		arg.VehicleID,
+		arg.Category,
+		arg.Tag,
+		arg.ExpiresFrom,
+		arg.ExpiresTo,
+		arg.Query,
+		arg.IncludeArchived,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Part
+	var items []Document
 	for rows.Next() {
-		var i Part
+		var i Document
 		if err := rows.Scan(
 			&i.ID,
-			&i.ServiceRecordID,
+			&i.VehicleID,
 			&i.Name,
-			&i.PartNumber,
-			&i.Cost,
-			&i.Link,
+			&i.Type,
+			&i.FileUrl,
+			&i.ExpiryDate,
+			&i.Notes,
 			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
 		); err != nil {
 			return nil, err
 		}
@@ -485,34 +7531,168 @@ func (q *Queries) ListPartsByServiceRecord(ctx context.Context, serviceRecordID
 	return items, nil
 }
 
-const listRemindersByVehicle = `-- name: ListRemindersByVehicle :many
-SELECT id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type FROM reminders
-WHERE vehicle_id = $1 AND is_completed = FALSE
-ORDER BY due_date ASC
+const searchDocumentsByIssueDateAsc = `-- name: SearchDocumentsByIssueDateAsc :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE ($1::int IS NULL OR vehicle_id = $1::int)
+  AND ($2::text IS NULL OR category = $2::text)
+  AND ($3::text IS NULL OR $3::text = ANY(tags))
+  AND ($4::date IS NULL OR expiry_date >= $4::date)
+  AND ($5::date IS NULL OR expiry_date <= $5::date)
+  AND ($6::text IS NULL
+       OR name ILIKE '%' || $6::text || '%'
+       OR issuer ILIKE '%' || $6::text || '%'
+       OR policy_number ILIKE '%' || $6::text || '%'
+       OR registration_number ILIKE '%' || $6::text || '%'
+       OR notes ILIKE '%' || $6::text || '%')
+  AND ($7::bool OR archived_at IS NULL)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(issue_date, 'infinity'::date), id) > (
+           CASE WHEN $8::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + $8::float8 * INTERVAL '1 second')::date END,
+           $9::int))
+ORDER BY COALESCE(issue_date, 'infinity'::date), id
+LIMIT $10
 `
 
-func (q *Queries) ListRemindersByVehicle(ctx context.Context, vehicleID sql.NullInt32) ([]Reminder, error) {
-	rows, err := q.db.QueryContext(ctx, listRemindersByVehicle, vehicleID)
+type SearchDocumentsByIssueDateAscParams struct {
+	VehicleID       sql.NullInt32
+	Category        sql.NullString
+	Tag             sql.NullString
+	ExpiresFrom     sql.NullTime
+	ExpiresTo       sql.NullTime
+	Query           sql.NullString
+	IncludeArchived bool
+	AfterKey        sql.NullFloat64
+	AfterID         sql.NullInt32
+	Limit           int32
+}
+
+// Like SearchDocumentsByCreatedAtAsc, ordered by issue_date.
+func (q *Queries) SearchDocumentsByIssueDateAsc(ctx context.Context, arg SearchDocumentsByIssueDateAscParams) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, searchDocumentsByIssueDateAsc,
+		arg.VehicleID,
+		arg.Category,
+		arg.Tag,
+		arg.ExpiresFrom,
+		arg.ExpiresTo,
+		arg.Query,
+		arg.IncludeArchived,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Reminder
+	var items []Document
 	for rows.Next() {
-		var i Reminder
+		var i Document
 		if err := rows.Scan(
 			&i.ID,
 			&i.VehicleID,
-			&i.Title,
-			&i.DueDate,
-			&i.DueOdometer,
-			&i.IsRecurring,
-			&i.IntervalKm,
-			&i.IntervalMonths,
+			&i.Name,
+			&i.Type,
+			&i.FileUrl,
+			&i.ExpiryDate,
 			&i.Notes,
-			&i.IsCompleted,
 			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchDocumentsByIssueDateDesc = `-- name: SearchDocumentsByIssueDateDesc :many
+SELECT id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags FROM documents
+WHERE ($1::int IS NULL OR vehicle_id = $1::int)
+  AND ($2::text IS NULL OR category = $2::text)
+  AND ($3::text IS NULL OR $3::text = ANY(tags))
+  AND ($4::date IS NULL OR expiry_date >= $4::date)
+  AND ($5::date IS NULL OR expiry_date <= $5::date)
+  AND ($6::text IS NULL
+       OR name ILIKE '%' || $6::text || '%'
+       OR issuer ILIKE '%' || $6::text || '%'
+       OR policy_number ILIKE '%' || $6::text || '%'
+       OR registration_number ILIKE '%' || $6::text || '%'
+       OR notes ILIKE '%' || $6::text || '%')
+  AND ($7::bool OR archived_at IS NULL)
+  AND ($8::float8 IS NULL
+       OR (COALESCE(issue_date, 'infinity'::date), id) < (
+           CASE WHEN $8::float8 >= 1e15 THEN 'infinity'::date
+                ELSE (TIMESTAMP 'epoch' + $8::float8 * INTERVAL '1 second')::date END,
+           $9::int))
+ORDER BY COALESCE(issue_date, 'infinity'::date) DESC, id DESC
+LIMIT $10
+`
+
+type SearchDocumentsByIssueDateDescParams struct {
+	VehicleID       sql.NullInt32
+	Category        sql.NullString
+	Tag             sql.NullString
+	ExpiresFrom     sql.NullTime
+	ExpiresTo       sql.NullTime
+	Query           sql.NullString
+	IncludeArchived bool
+	AfterKey        sql.NullFloat64
+	AfterID         sql.NullInt32
+	Limit           int32
+}
+
+// Like SearchDocumentsByCreatedAtAsc, ordered by issue_date descending.
+func (q *Queries) SearchDocumentsByIssueDateDesc(ctx context.Context, arg SearchDocumentsByIssueDateDescParams) ([]Document, error) {
+	rows, err := q.db.QueryContext(ctx, searchDocumentsByIssueDateDesc,
+		arg.VehicleID,
+		arg.Category,
+		arg.Tag,
+		arg.ExpiresFrom,
+		arg.ExpiresTo,
+		arg.Query,
+		arg.IncludeArchived,
+		arg.AfterKey,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Document
+	for rows.Next() {
+		var i Document
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.Name,
 			&i.Type,
+			&i.FileUrl,
+			&i.ExpiryDate,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.PreviousVersionID,
+			&i.Category,
+			&i.IssueDate,
+			&i.Issuer,
+			&i.PolicyNumber,
+			&i.RegistrationNumber,
+			pq.Array(&i.Tags),
 		); err != nil {
 			return nil, err
 		}
@@ -527,83 +7707,267 @@ func (q *Queries) ListRemindersByVehicle(ctx context.Context, vehicleID sql.Null
 	return items, nil
 }
 
-const listServiceRecordsByVehicle = `-- name: ListServiceRecordsByVehicle :many
-SELECT id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url FROM service_records
-WHERE vehicle_id = $1
-ORDER BY date DESC
+const setExpenseNextDue = `-- name: SetExpenseNextDue :exec
+UPDATE expenses
+SET next_due_date = $2
+WHERE id = $1
+`
+
+type SetExpenseNextDueParams struct {
+	ID          int32
+	NextDueDate sql.NullTime
+}
+
+func (q *Queries) SetExpenseNextDue(ctx context.Context, arg SetExpenseNextDueParams) error {
+	_, err := q.db.ExecContext(ctx, setExpenseNextDue,
+		arg.ID,
+		arg.NextDueDate,
+	)
+	return err
+}
+
+const setThumbnailStatus = `-- name: SetThumbnailStatus :exec
+UPDATE document_files
+SET thumbnail_status = $2, thumbnail_error = $3
+WHERE id = $1
+`
+
+type SetThumbnailStatusParams struct {
+	ID              int32
+	ThumbnailStatus string
+	ThumbnailError  sql.NullString
+}
+
+func (q *Queries) SetThumbnailStatus(ctx context.Context, arg SetThumbnailStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setThumbnailStatus,
+		arg.ID,
+		arg.ThumbnailStatus,
+		arg.ThumbnailError,
+	)
+	return err
+}
+
+const setUserFeedToken = `-- name: SetUserFeedToken :one
+UPDATE users
+SET feed_token = $2
+WHERE id = $1
+RETURNING id, name, email, ntfy_topic, created_at, locale, feed_token
+`
+
+type SetUserFeedTokenParams struct {
+	ID        int32
+	FeedToken sql.NullString
+}
+
+func (q *Queries) SetUserFeedToken(ctx context.Context, arg SetUserFeedTokenParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserFeedToken,
+		arg.ID,
+		arg.FeedToken,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.NtfyTopic,
+		&i.CreatedAt,
+		&i.Locale,
+		&i.FeedToken,
+	)
+	return i, err
+}
+
+const updateBudget = `-- name: UpdateBudget :one
+UPDATE budgets
+SET vehicle_id = $2, category = $3, period = $4, amount = $5, thresholds = $6
+WHERE id = $1
+RETURNING id, vehicle_id, category, period, amount, thresholds, created_at
+`
+
+type UpdateBudgetParams struct {
+	ID         int32
+	VehicleID  sql.NullInt32
+	Category   string
+	Period     string
+	Amount     string
+	Thresholds []int32
+}
+
+func (q *Queries) UpdateBudget(ctx context.Context, arg UpdateBudgetParams) (Budget, error) {
+	row := q.db.QueryRowContext(ctx, updateBudget,
+		arg.ID,
+		arg.VehicleID,
+		arg.Category,
+		arg.Period,
+		arg.Amount,
+		pq.Array(arg.Thresholds),
+	)
+	var i Budget
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Category,
+		&i.Period,
+		&i.Amount,
+		pq.Array(&i.Thresholds),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateDocument = `-- name: UpdateDocument :one
+UPDATE documents
+SET name = $2, type = $3, file_url = $4, expiry_date = $5, notes = $6,
+    category = $7, issue_date = $8, issuer = $9, policy_number = $10, registration_number = $11, tags = $12
+WHERE id = $1
+RETURNING id, vehicle_id, name, type, file_url, expiry_date, notes, created_at, archived_at, previous_version_id, category, issue_date, issuer, policy_number, registration_number, tags
+`
+
+type UpdateDocumentParams struct {
+	ID                 int32
+	Name               string
+	Type               sql.NullString
+	FileUrl            string
+	ExpiryDate         sql.NullTime
+	Notes              sql.NullString
+	Category           sql.NullString
+	IssueDate          sql.NullTime
+	Issuer             sql.NullString
+	PolicyNumber       sql.NullString
+	RegistrationNumber sql.NullString
+	Tags               []string
+}
+
+func (q *Queries) UpdateDocument(ctx context.Context, arg UpdateDocumentParams) (Document, error) {
+	row := q.db.QueryRowContext(ctx, updateDocument,
+		arg.ID,
+		arg.Name,
+		arg.Type,
+		arg.FileUrl,
+		arg.ExpiryDate,
+		arg.Notes,
+		arg.Category,
+		arg.IssueDate,
+		arg.Issuer,
+		arg.PolicyNumber,
+		arg.RegistrationNumber,
+		pq.Array(arg.Tags),
+	)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Name,
+		&i.Type,
+		&i.FileUrl,
+		&i.ExpiryDate,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.PreviousVersionID,
+		&i.Category,
+		&i.IssueDate,
+		&i.Issuer,
+		&i.PolicyNumber,
+		&i.RegistrationNumber,
+		pq.Array(&i.Tags),
+	)
+	return i, err
+}
+
+const updateExpense = `-- name: UpdateExpense :one
+UPDATE expenses
+SET vendor_id = $2, category = $3, date = $4, amount = $5, odometer = $6, description = $7,
+    recurrence = $8, recurrence_end_date = $9, next_due_date = $10
+WHERE id = $1
+RETURNING id, vehicle_id, vendor_id, category, date, amount, odometer, description, recurrence, recurrence_end_date, next_due_date, recurring_expense_id, created_at
 `
 
-func (q *Queries) ListServiceRecordsByVehicle(ctx context.Context, vehicleID sql.NullInt32) ([]ServiceRecord, error) {
-	rows, err := q.db.QueryContext(ctx, listServiceRecordsByVehicle, vehicleID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ServiceRecord
-	for rows.Next() {
-		var i ServiceRecord
-		if err := rows.Scan(
-			&i.ID,
-			&i.VehicleID,
-			&i.Date,
-			&i.Odometer,
-			&i.Cost,
-			&i.Notes,
-			&i.ServiceType,
-			&i.CreatedAt,
-			&i.DocumentUrl,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type UpdateExpenseParams struct {
+	ID                int32
+	VendorID          sql.NullInt32
+	Category          string
+	Date              time.Time
+	Amount            string
+	Odometer          sql.NullInt32
+	Description       sql.NullString
+	Recurrence        string
+	RecurrenceEndDate sql.NullTime
+	NextDueDate       sql.NullTime
 }
 
-const listVehicles = `-- name: ListVehicles :many
-SELECT id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at FROM vehicles
-ORDER BY created_at DESC
+func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (Expense, error) {
+	row := q.db.QueryRowContext(ctx, updateExpense,
+		arg.ID,
+		arg.VendorID,
+		arg.Category,
+		arg.Date,
+		arg.Amount,
+		arg.Odometer,
+		arg.Description,
+		arg.Recurrence,
+		arg.RecurrenceEndDate,
+		arg.NextDueDate,
+	)
+	var i Expense
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.VendorID,
+		&i.Category,
+		&i.Date,
+		&i.Amount,
+		&i.Odometer,
+		&i.Description,
+		&i.Recurrence,
+		&i.RecurrenceEndDate,
+		&i.NextDueDate,
+		&i.RecurringExpenseID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateFixedCost = `-- name: UpdateFixedCost :one
+UPDATE fixed_costs
+SET category = $2, amount = $3, frequency = $4, start_date = $5, end_date = $6, notes = $7
+WHERE id = $1
+RETURNING id, vehicle_id, category, amount, frequency, start_date, end_date, notes, created_at
 `
 
-func (q *Queries) ListVehicles(ctx context.Context) ([]Vehicle, error) {
-	rows, err := q.db.QueryContext(ctx, listVehicles)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Vehicle
-	for rows.Next() {
-		var i Vehicle
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Make,
-			&i.Model,
-			&i.Year,
-			&i.Type,
-			&i.Vin,
-			&i.LicensePlate,
-			&i.ImageUrl,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type UpdateFixedCostParams struct {
+	ID        int32
+	Category  string
+	Amount    string
+	Frequency string
+	StartDate time.Time
+	EndDate   sql.NullTime
+	Notes     sql.NullString
+}
+
+func (q *Queries) UpdateFixedCost(ctx context.Context, arg UpdateFixedCostParams) (FixedCost, error) {
+	row := q.db.QueryRowContext(ctx, updateFixedCost,
+		arg.ID,
+		arg.Category,
+		arg.Amount,
+		arg.Frequency,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Notes,
+	)
+	var i FixedCost
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Category,
+		&i.Amount,
+		&i.Frequency,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
 const updateFuelLog = `-- name: UpdateFuelLog :one
@@ -651,11 +8015,60 @@ func (q *Queries) UpdateFuelLog(ctx context.Context, arg UpdateFuelLogParams) (F
 	return i, err
 }
 
+const updateLoan = `-- name: UpdateLoan :one
+UPDATE loans
+SET lender = $2, principal = $3, annual_rate = $4, tenure_months = $5, emi = $6,
+    start_date = $7, first_emi_date = $8, notes = $9
+WHERE id = $1
+RETURNING id, vehicle_id, lender, principal, annual_rate, tenure_months, emi, start_date, first_emi_date, notes, created_at
+`
+
+type UpdateLoanParams struct {
+	ID           int32
+	Lender       sql.NullString
+	Principal    string
+	AnnualRate   string
+	TenureMonths int32
+	Emi          string
+	StartDate    time.Time
+	FirstEmiDate time.Time
+	Notes        sql.NullString
+}
+
+func (q *Queries) UpdateLoan(ctx context.Context, arg UpdateLoanParams) (Loan, error) {
+	row := q.db.QueryRowContext(ctx, updateLoan,
+		arg.ID,
+		arg.Lender,
+		arg.Principal,
+		arg.AnnualRate,
+		arg.TenureMonths,
+		arg.Emi,
+		arg.StartDate,
+		arg.FirstEmiDate,
+		arg.Notes,
+	)
+	var i Loan
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Lender,
+		&i.Principal,
+		&i.AnnualRate,
+		&i.TenureMonths,
+		&i.Emi,
+		&i.StartDate,
+		&i.FirstEmiDate,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const updateReminder = `-- name: UpdateReminder :one
 UPDATE reminders
 SET title = $2, due_date = $3, due_odometer = $4, is_recurring = $5, interval_km = $6, interval_months = $7, notes = $8, type = $9
 WHERE id = $1
-RETURNING id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type
+RETURNING id, vehicle_id, title, due_date, due_odometer, is_recurring, interval_km, interval_months, notes, is_completed, created_at, type, completed_at
 `
 
 type UpdateReminderParams struct {
@@ -696,25 +8109,27 @@ func (q *Queries) UpdateReminder(ctx context.Context, arg UpdateReminderParams)
 		&i.IsCompleted,
 		&i.CreatedAt,
 		&i.Type,
+		&i.CompletedAt,
 	)
 	return i, err
 }
 
 const updateServiceRecord = `-- name: UpdateServiceRecord :one
 UPDATE service_records
-SET date = $2, odometer = $3, cost = $4, notes = $5, service_type = $6, document_url = $7
+SET date = $2, odometer = $3, cost = $4, notes = $5, service_type = $6, document_url = $7, completed_date = $8
 WHERE id = $1
-RETURNING id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url
+RETURNING id, vehicle_id, date, odometer, cost, notes, service_type, created_at, document_url, completed_date
 `
 
 type UpdateServiceRecordParams struct {
-	ID          int32
-	Date        time.Time
-	Odometer    int32
-	Cost        string
-	Notes       sql.NullString
-	ServiceType sql.NullString
-	DocumentUrl sql.NullString
+	ID            int32
+	Date          time.Time
+	Odometer      int32
+	Cost          string
+	Notes         sql.NullString
+	ServiceType   sql.NullString
+	DocumentUrl   sql.NullString
+	CompletedDate sql.NullTime
 }
 
 func (q *Queries) UpdateServiceRecord(ctx context.Context, arg UpdateServiceRecordParams) (ServiceRecord, error) {
@@ -726,6 +8141,7 @@ func (q *Queries) UpdateServiceRecord(ctx context.Context, arg UpdateServiceReco
 		arg.Notes,
 		arg.ServiceType,
 		arg.DocumentUrl,
+		arg.CompletedDate,
 	)
 	var i ServiceRecord
 	err := row.Scan(
@@ -738,27 +8154,154 @@ func (q *Queries) UpdateServiceRecord(ctx context.Context, arg UpdateServiceReco
 		&i.ServiceType,
 		&i.CreatedAt,
 		&i.DocumentUrl,
+		&i.CompletedDate,
+	)
+	return i, err
+}
+
+const updateSubscription = `-- name: UpdateSubscription :one
+UPDATE notification_subscriptions
+SET vehicle_id = $2, channel = $3, reminder_types = $4, triggers = $5, lead_days = $6, digest = $7, enabled = $8
+WHERE id = $1
+RETURNING id, user_id, vehicle_id, channel, reminder_types, triggers, lead_days, digest, enabled, created_at
+`
+
+type UpdateSubscriptionParams struct {
+	ID            int32
+	VehicleID     sql.NullInt32
+	Channel       string
+	ReminderTypes []string
+	Triggers      []string
+	LeadDays      int32
+	Digest        string
+	Enabled       bool
+}
+
+func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (NotificationSubscription, error) {
+	row := q.db.QueryRowContext(ctx, updateSubscription,
+		arg.ID,
+		arg.VehicleID,
+		arg.Channel,
+		pq.Array(arg.ReminderTypes),
+		pq.Array(arg.Triggers),
+		arg.LeadDays,
+		arg.Digest,
+		arg.Enabled,
+	)
+	var i NotificationSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.VehicleID,
+		&i.Channel,
+		pq.Array(&i.ReminderTypes),
+		pq.Array(&i.Triggers),
+		&i.LeadDays,
+		&i.Digest,
+		&i.Enabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET name = $2, email = $3, ntfy_topic = $4, locale = $5
+WHERE id = $1
+RETURNING id, name, email, ntfy_topic, created_at, locale, feed_token
+`
+
+type UpdateUserParams struct {
+	ID        int32
+	Name      string
+	Email     sql.NullString
+	NtfyTopic sql.NullString
+	Locale    string
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.ID,
+		arg.Name,
+		arg.Email,
+		arg.NtfyTopic,
+		arg.Locale,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.NtfyTopic,
+		&i.CreatedAt,
+		&i.Locale,
+		&i.FeedToken,
+	)
+	return i, err
+}
+
+const updateValuation = `-- name: UpdateValuation :one
+UPDATE vehicle_valuations
+SET date = $2, value = $3, source = $4, notes = $5
+WHERE id = $1
+RETURNING id, vehicle_id, date, value, source, notes, created_at
+`
+
+type UpdateValuationParams struct {
+	ID     int32
+	Date   time.Time
+	Value  string
+	Source sql.NullString
+	Notes  sql.NullString
+}
+
+func (q *Queries) UpdateValuation(ctx context.Context, arg UpdateValuationParams) (VehicleValuation, error) {
+	row := q.db.QueryRowContext(ctx, updateValuation,
+		arg.ID,
+		arg.Date,
+		arg.Value,
+		arg.Source,
+		arg.Notes,
+	)
+	var i VehicleValuation
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.Date,
+		&i.Value,
+		&i.Source,
+		&i.Notes,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const updateVehicle = `-- name: UpdateVehicle :one
 UPDATE vehicles
-SET name = $2, make = $3, model = $4, year = $5, type = $6, vin = $7, license_plate = $8, image_url = $9, updated_at = NOW()
+SET name = $2, make = $3, model = $4, year = $5, type = $6, vin = $7, license_plate = $8, image_url = $9,
+    fuel_type = $10, distance_unit = $11, purchase_price = $12, purchase_date = $13,
+    depreciation_model = $14, depreciation_rate = $15, residual_value = $16, updated_at = NOW()
 WHERE id = $1
-RETURNING id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at
+RETURNING id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at, fuel_type, distance_unit, purchase_price, purchase_date, depreciation_model, depreciation_rate, residual_value, status, sale_date, sale_price, buyer_notes
 `
 
 type UpdateVehicleParams struct {
-	ID           int32
-	Name         string
-	Make         sql.NullString
-	Model        sql.NullString
-	Year         sql.NullInt32
-	Type         sql.NullString
-	Vin          sql.NullString
-	LicensePlate sql.NullString
-	ImageUrl     sql.NullString
+	ID                int32
+	Name              string
+	Make              sql.NullString
+	Model             sql.NullString
+	Year              sql.NullInt32
+	Type              sql.NullString
+	Vin               sql.NullString
+	LicensePlate      sql.NullString
+	ImageUrl          sql.NullString
+	FuelType          sql.NullString
+	DistanceUnit      sql.NullString
+	PurchasePrice     sql.NullString
+	PurchaseDate      sql.NullTime
+	DepreciationModel sql.NullString
+	DepreciationRate  sql.NullString
+	ResidualValue     sql.NullString
 }
 
 func (q *Queries) UpdateVehicle(ctx context.Context, arg UpdateVehicleParams) (Vehicle, error) {
@@ -772,6 +8315,64 @@ func (q *Queries) UpdateVehicle(ctx context.Context, arg UpdateVehicleParams) (V
 		arg.Vin,
 		arg.LicensePlate,
 		arg.ImageUrl,
+		arg.FuelType,
+		arg.DistanceUnit,
+		arg.PurchasePrice,
+		arg.PurchaseDate,
+		arg.DepreciationModel,
+		arg.DepreciationRate,
+		arg.ResidualValue,
+	)
+	var i Vehicle
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Make,
+		&i.Model,
+		&i.Year,
+		&i.Type,
+		&i.Vin,
+		&i.LicensePlate,
+		&i.ImageUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FuelType,
+		&i.DistanceUnit,
+		&i.PurchasePrice,
+		&i.PurchaseDate,
+		&i.DepreciationModel,
+		&i.DepreciationRate,
+		&i.ResidualValue,
+		&i.Status,
+		&i.SaleDate,
+		&i.SalePrice,
+		&i.BuyerNotes,
+	)
+	return i, err
+}
+
+const updateVehicleStatus = `-- name: UpdateVehicleStatus :one
+UPDATE vehicles
+SET status = $2, sale_date = $3, sale_price = $4, buyer_notes = $5, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, make, model, year, type, vin, license_plate, image_url, created_at, updated_at, fuel_type, distance_unit, purchase_price, purchase_date, depreciation_model, depreciation_rate, residual_value, status, sale_date, sale_price, buyer_notes
+`
+
+type UpdateVehicleStatusParams struct {
+	ID         int32
+	Status     string
+	SaleDate   sql.NullTime
+	SalePrice  sql.NullString
+	BuyerNotes sql.NullString
+}
+
+func (q *Queries) UpdateVehicleStatus(ctx context.Context, arg UpdateVehicleStatusParams) (Vehicle, error) {
+	row := q.db.QueryRowContext(ctx, updateVehicleStatus,
+		arg.ID,
+		arg.Status,
+		arg.SaleDate,
+		arg.SalePrice,
+		arg.BuyerNotes,
 	)
 	var i Vehicle
 	err := row.Scan(
@@ -786,6 +8387,146 @@ func (q *Queries) UpdateVehicle(ctx context.Context, arg UpdateVehicleParams) (V
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FuelType,
+		&i.DistanceUnit,
+		&i.PurchasePrice,
+		&i.PurchaseDate,
+		&i.DepreciationModel,
+		&i.DepreciationRate,
+		&i.ResidualValue,
+		&i.Status,
+		&i.SaleDate,
+		&i.SalePrice,
+		&i.BuyerNotes,
+	)
+	return i, err
+}
+
+const updateVendor = `-- name: UpdateVendor :one
+UPDATE vendors
+SET name = $2, website = $3, phone = $4, address = $5, notes = $6
+WHERE id = $1
+RETURNING id, name, website, phone, address, notes, created_at
+`
+
+type UpdateVendorParams struct {
+	ID      int32
+	Name    string
+	Website sql.NullString
+	Phone   sql.NullString
+	Address sql.NullString
+	Notes   sql.NullString
+}
+
+func (q *Queries) UpdateVendor(ctx context.Context, arg UpdateVendorParams) (Vendor, error) {
+	row := q.db.QueryRowContext(ctx, updateVendor,
+		arg.ID,
+		arg.Name,
+		arg.Website,
+		arg.Phone,
+		arg.Address,
+		arg.Notes,
+	)
+	var i Vendor
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Website,
+		&i.Phone,
+		&i.Address,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateWebhook = `-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = $2, event_types = $3, description = $4, enabled = $5
+WHERE id = $1
+RETURNING id, url, secret, event_types, description, enabled, created_at
+`
+
+type UpdateWebhookParams struct {
+	ID          int32
+	Url         string
+	EventTypes  []string
+	Description sql.NullString
+	Enabled     bool
+}
+
+func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhook,
+		arg.ID,
+		arg.Url,
+		pq.Array(arg.EventTypes),
+		arg.Description,
+		arg.Enabled,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.EventTypes),
+		&i.Description,
+		&i.Enabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertNotificationTemplate = `-- name: UpsertNotificationTemplate :one
+INSERT INTO notification_templates (user_id, kind, title, body)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, kind) DO UPDATE
+SET title = EXCLUDED.title, body = EXCLUDED.body, updated_at = NOW()
+RETURNING id, user_id, kind, title, body, updated_at
+`
+
+type UpsertNotificationTemplateParams struct {
+	UserID int32
+	Kind   string
+	Title  string
+	Body   string
+}
+
+func (q *Queries) UpsertNotificationTemplate(ctx context.Context, arg UpsertNotificationTemplateParams) (NotificationTemplate, error) {
+	row := q.db.QueryRowContext(ctx, upsertNotificationTemplate,
+		arg.UserID,
+		arg.Kind,
+		arg.Title,
+		arg.Body,
+	)
+	var i NotificationTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Title,
+		&i.Body,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
+
+const vehicleHasHistory = `-- name: VehicleHasHistory :one
+SELECT (
+    EXISTS (SELECT 1 FROM service_records WHERE service_records.vehicle_id = $1::int)
+    OR EXISTS (SELECT 1 FROM fuel_logs WHERE fuel_logs.vehicle_id = $1::int)
+    OR EXISTS (SELECT 1 FROM odometer_readings WHERE odometer_readings.vehicle_id = $1::int)
+    OR EXISTS (SELECT 1 FROM expenses WHERE expenses.vehicle_id = $1::int)
+    OR EXISTS (SELECT 1 FROM documents WHERE documents.vehicle_id = $1::int)
+    OR EXISTS (SELECT 1 FROM fixed_costs WHERE fixed_costs.vehicle_id = $1::int)
+    OR EXISTS (SELECT 1 FROM loans WHERE loans.vehicle_id = $1::int)
+    OR EXISTS (SELECT 1 FROM vehicle_valuations WHERE vehicle_valuations.vehicle_id = $1::int)
+)::bool AS has_history
+`
+
+// Whether a vehicle has any records that deleting it would remove.
+func (q *Queries) VehicleHasHistory(ctx context.Context, vehicleID int32) (bool, error) {
+	row := q.db.QueryRowContext(ctx, vehicleHasHistory, vehicleID)
+	var hasHistory bool
+	err := row.Scan(&hasHistory)
+	return hasHistory, err
+}
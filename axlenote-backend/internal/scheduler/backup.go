@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const backupPrefix = "axlenote-"
+
+type backupConfig struct {
+	Dir       string
+	Retention int
+}
+
+func backupConfigFromEnv() backupConfig {
+	dir := os.Getenv("BACKUP_DIR")
+	if dir == "" {
+		dir = "./backups"
+	}
+	retention, err := strconv.Atoi(os.Getenv("BACKUP_RETENTION"))
+	if err != nil || retention <= 0 {
+		retention = 7
+	}
+	return backupConfig{Dir: dir, Retention: retention}
+}
+
+// runBackup writes every table in the public schema to a gzipped JSON file and
+// prunes old backups beyond the retention count.
+func (s *Scheduler) runBackup(ctx context.Context) error {
+	if err := os.MkdirAll(s.backup.Dir, 0o755); err != nil {
+		return fmt.Errorf("create backup dir: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT table_name FROM information_schema.tables
+		WHERE table_schema = 'public' AND table_type = 'BASE TABLE'
+		ORDER BY table_name`)
+	if err != nil {
+		return fmt.Errorf("list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	dump := make(map[string]json.RawMessage, len(tables))
+	for _, table := range tables {
+		var data []byte
+		// Table names come from the catalog, quote them anyway
+		query := fmt.Sprintf(`SELECT COALESCE(json_agg(t), '[]'::json) FROM %q t`, table)
+		if err := s.db.QueryRowContext(ctx, query).Scan(&data); err != nil {
+			return fmt.Errorf("dump %s: %w", table, err)
+		}
+		dump[table] = data
+	}
+
+	name := fmt.Sprintf("%s%s.json.gz", backupPrefix, time.Now().UTC().Format("20060102-150405"))
+	path := filepath.Join(s.backup.Dir, name)
+	if err := writeBackup(path, dump); err != nil {
+		os.Remove(path)
+		return err
+	}
+	log.Printf("Scheduler: Backup written to %s (%d tables)", path, len(tables))
+
+	return s.pruneBackups()
+}
+
+func writeBackup(path string, dump map[string]json.RawMessage) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create backup: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	if err := json.NewEncoder(gz).Encode(dump); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	return f.Close()
+}
+
+func (s *Scheduler) pruneBackups() error {
+	entries, err := os.ReadDir(s.backup.Dir)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), backupPrefix) && strings.HasSuffix(e.Name(), ".json.gz") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= s.backup.Retention {
+		return nil
+	}
+
+	// Timestamps in the names sort chronologically
+	sort.Strings(names)
+	for _, name := range names[:len(names)-s.backup.Retention] {
+		if err := os.Remove(filepath.Join(s.backup.Dir, name)); err != nil {
+			log.Printf("Scheduler: Failed to remove old backup %s: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/axlenote/axlenote-backend/internal/budget"
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/tco"
+)
+
+// checkBudgets notifies subscribers when spend crosses one of a budget's thresholds. Each
+// threshold is alerted once per budget period; when several are crossed at once, only the
+// highest is sent. Budgets of sold and scrapped vehicles are skipped, while those of
+// stored vehicles, which still cost money, and garage-wide ones are checked.
+func (s *Scheduler) checkBudgets(ctx context.Context) error {
+	budgets, err := s.queries.ListBudgets(ctx)
+	if err != nil {
+		return fmt.Errorf("list budgets: %w", err)
+	}
+	if len(budgets) == 0 {
+		return nil
+	}
+	vehicles, err := s.queries.ListVehicles(ctx)
+	if err != nil {
+		return fmt.Errorf("list vehicles: %w", err)
+	}
+	byID := make(map[int32]repository.Vehicle, len(vehicles))
+	for _, v := range vehicles {
+		byID[v.ID] = v
+	}
+
+	today := forecast.Today()
+	var found []notice
+	for _, b := range budgets {
+		if b.VehicleID.Valid && fleet.IsArchived(byID[b.VehicleID.Int32]) {
+			continue
+		}
+		progress, err := budget.ForBudget(ctx, s.queries, b, today)
+		if err != nil {
+			return fmt.Errorf("budget %d: %w", b.ID, err)
+		}
+		start, _ := budget.Period(b.Period, today)
+
+		var highest int32
+		for _, t := range progress.Crossed {
+			recorded, err := s.queries.RecordBudgetAlert(ctx, repository.RecordBudgetAlertParams{
+				BudgetID:    b.ID,
+				PeriodStart: start,
+				Threshold:   t,
+			})
+			if err != nil {
+				return fmt.Errorf("record alert of budget %d: %w", b.ID, err)
+			}
+			if recorded > 0 && t > highest {
+				highest = t
+			}
+		}
+		if highest == 0 {
+			continue
+		}
+
+		vehicle := "All vehicles"
+		if b.VehicleID.Valid {
+			vehicle = byID[b.VehicleID.Int32].Name
+		}
+		log.Printf("Scheduler: %s %s budget of %s passed %d%%", b.Period, b.Category, vehicle, highest)
+		found = append(found, notice{
+			key:       fmt.Sprintf("budget:%d:%d", b.ID, highest),
+			vehicleID: b.VehicleID.Int32,
+			kind:      messages.KindBudgetThreshold,
+			data: messages.Data{
+				Vehicle:   vehicle,
+				Type:      b.Category,
+				Currency:  tco.Currency(),
+				Budget:    b.Amount,
+				Spent:     strconv.FormatFloat(progress.Spent, 'f', 2, 64),
+				Percent:   int(math.Floor(progress.Percent)),
+				Threshold: int(highest),
+				Period:    progress.PeriodStart + " to " + progress.PeriodEnd,
+			},
+		})
+	}
+	return s.sendNotices(ctx, found)
+}
@@ -0,0 +1,213 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/digest"
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/notification"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// digestDocumentDays is how far ahead the digest looks for expiring documents.
+const digestDocumentDays = 30
+
+// sendDigests renders and delivers one digest per target for every subscription in the given period.
+func (s *Scheduler) sendDigests(ctx context.Context, period string) error {
+	subs, err := s.queries.ListActiveSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("list subscriptions: %w", err)
+	}
+
+	// Group subscriptions by where they are delivered
+	byTarget := make(map[notification.Target][]repository.ListActiveSubscriptionsRow)
+	var order []notification.Target
+	for _, sub := range subs {
+		if sub.Digest != period {
+			continue
+		}
+		target := subscriptionTarget(sub)
+		if _, ok := byTarget[target]; !ok {
+			order = append(order, target)
+		}
+		byTarget[target] = append(byTarget[target], sub)
+	}
+
+	for _, target := range order {
+		d, err := s.BuildDigest(ctx, byTarget[target], period)
+		if err != nil {
+			log.Printf("Scheduler: Failed to build digest: %v", err)
+			continue
+		}
+		if d.Empty() {
+			continue
+		}
+
+		msg, err := RenderDigest(d)
+		if err != nil {
+			log.Printf("Scheduler: Failed to render digest: %v", err)
+			continue
+		}
+
+		log.Printf("Sending %s digest to %s %s", period, target.Channel, target.Address)
+		if err := s.notifier.DeliverMessage(target, msg); err != nil {
+			log.Printf("Failed to send digest: %v", err)
+		}
+	}
+	return nil
+}
+
+// PreviewDigest builds the digest a user would receive, without sending it.
+// A user without subscriptions previews a digest covering every vehicle.
+func (s *Scheduler) PreviewDigest(ctx context.Context, userID int32, period string) (digest.Digest, error) {
+	all, err := s.queries.ListActiveSubscriptions(ctx)
+	if err != nil {
+		return digest.Digest{}, err
+	}
+
+	var subs []repository.ListActiveSubscriptionsRow
+	for _, sub := range all {
+		if sub.UserID == userID {
+			subs = append(subs, sub)
+		}
+	}
+	if len(subs) == 0 {
+		user, err := s.queries.GetUser(ctx, userID)
+		if err != nil {
+			return digest.Digest{}, err
+		}
+		subs = append(subs, repository.ListActiveSubscriptionsRow{UserID: userID, LeadDays: defaultLeadDays, UserLocale: user.Locale})
+	}
+
+	return s.BuildDigest(ctx, subs, period)
+}
+
+// BuildDigest gathers overdue and upcoming reminders, expiring documents and recent
+// fuel spend for the vehicles covered by the given subscriptions.
+func (s *Scheduler) BuildDigest(ctx context.Context, subs []repository.ListActiveSubscriptionsRow, period string) (digest.Digest, error) {
+	now := time.Now()
+	currency := os.Getenv("APP_CURRENCY")
+	if currency == "" {
+		currency = "₹"
+	}
+	d := digest.Digest{Period: period, GeneratedAt: now, Currency: currency, SpendDays: digest.SpendDays(period)}
+
+	locale := messages.DefaultLocale
+	if len(subs) > 0 && subs[0].UserLocale != "" {
+		locale = subs[0].UserLocale
+	}
+
+	leadDays := int32(defaultLeadDays)
+	allVehicles := false
+	covered := make(map[int32]bool)
+	for _, sub := range subs {
+		if sub.LeadDays > leadDays {
+			leadDays = sub.LeadDays
+		}
+		if sub.VehicleID.Valid {
+			covered[sub.VehicleID.Int32] = true
+		} else {
+			allVehicles = true
+		}
+	}
+
+	vehicles, err := s.queries.ListVehicles(ctx)
+	if err != nil {
+		return d, err
+	}
+
+	summaries := make(map[int32]*digest.VehicleSummary)
+	for _, v := range vehicles {
+		if !fleet.IsActive(v) || (!allVehicles && !covered[v.ID]) {
+			continue
+		}
+		d.Vehicles = append(d.Vehicles, digest.VehicleSummary{ID: v.ID, Name: v.Name})
+	}
+	for i := range d.Vehicles {
+		summaries[d.Vehicles[i].ID] = &d.Vehicles[i]
+	}
+
+	alerts, err := s.collectAlerts(ctx, leadDays)
+	if err != nil {
+		return d, err
+	}
+	for _, a := range alerts {
+		summary, ok := summaries[a.vehicle.ID]
+		// Documents have their own section below
+		if !ok || a.isDocument() || !matchesAny(a, subs) {
+			continue
+		}
+		data := a.data(locale, s.unit)
+		item := digest.ReminderItem{Title: a.reminder.Title, Type: a.reminder.Type.String, Trigger: data.Trigger}
+		if a.level == notification.TriggerOverdue {
+			summary.Overdue = append(summary.Overdue, item)
+		} else {
+			summary.Upcoming = append(summary.Upcoming, item)
+		}
+	}
+
+	today := forecast.Today()
+	docs, err := s.queries.ListDocumentsExpiringBetween(ctx, repository.ListDocumentsExpiringBetweenParams{
+		FromDate: today,
+		ToDate:   today.AddDate(0, 0, digestDocumentDays),
+	})
+	if err != nil {
+		return d, err
+	}
+	for _, doc := range docs {
+		summary, ok := summaries[doc.VehicleID.Int32]
+		if !ok {
+			continue
+		}
+		summary.ExpiringDocuments = append(summary.ExpiringDocuments, digest.DocumentItem{
+			Name:       doc.Name,
+			Type:       doc.Type.String,
+			ExpiryDate: doc.ExpiryDate.Time,
+			DaysLeft:   int(doc.ExpiryDate.Time.Sub(today).Hours() / 24),
+		})
+	}
+
+	spend, err := s.queries.ListFuelSpendSince(ctx, today.AddDate(0, 0, -d.SpendDays))
+	if err != nil {
+		return d, err
+	}
+	for _, row := range spend {
+		summary, ok := summaries[row.VehicleID.Int32]
+		if !ok {
+			continue
+		}
+		summary.FuelSpend = row.TotalCost
+		summary.FuelLiters = row.TotalLiters
+		summary.FillUps = row.FillUps
+	}
+
+	return d, nil
+}
+
+// RenderDigest renders both the text and HTML versions of a digest into a notification message.
+func RenderDigest(d digest.Digest) (notification.Message, error) {
+	text, err := digest.RenderText(d)
+	if err != nil {
+		return notification.Message{}, err
+	}
+	html, err := digest.RenderHTML(d)
+	if err != nil {
+		return notification.Message{}, err
+	}
+	return notification.Message{Title: d.Title(), Body: text, HTML: html}, nil
+}
+
+func matchesAny(a alert, subs []repository.ListActiveSubscriptionsRow) bool {
+	for _, sub := range subs {
+		if a.matches(sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/expense"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// GenerateExpenses wakes the expenses job, so copies of a recurring expense entered with a
+// past date show up without waiting for the schedule.
+func (s *Scheduler) GenerateExpenses() {
+	s.wake(jobExpenses)
+}
+
+// generateExpenses copies recurring expenses onto every date they have fallen due since
+// the last run, up to today. Copies are unique per series and date, so a run that fails
+// part way can be repeated safely. A series ends once its vehicle is sold or scrapped,
+// with no copies from the sale date on, and carries on while the vehicle is stored.
+func (s *Scheduler) generateExpenses(ctx context.Context) error {
+	today := forecast.Today()
+	due, err := s.queries.ListDueRecurringExpenses(ctx, today)
+	if err != nil {
+		return fmt.Errorf("list due expenses: %w", err)
+	}
+	vehicles, err := s.queries.ListVehicles(ctx)
+	if err != nil {
+		return fmt.Errorf("list vehicles: %w", err)
+	}
+	byID := make(map[int32]repository.Vehicle, len(vehicles))
+	for _, v := range vehicles {
+		byID[v.ID] = v
+	}
+
+	var created int64
+	for _, e := range due {
+		v, last := byID[e.VehicleID], today
+		if v.SaleDate.Valid {
+			if sold := v.SaleDate.Time.AddDate(0, 0, -1); sold.Before(last) {
+				last = sold
+			}
+		}
+		var end *time.Time
+		if e.RecurrenceEndDate.Valid {
+			end = &e.RecurrenceEndDate.Time
+		}
+
+		next, ok := e.NextDueDate.Time, true
+		for ok && !next.After(last) {
+			n, err := s.queries.CreateRecurringExpense(ctx, repository.CreateRecurringExpenseParams{
+				Date: next,
+				ID:   e.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("copy expense %d to %s: %w", e.ID, next.Format("2006-01-02"), err)
+			}
+			created += n
+			next, ok = expense.NextDue(e.Date, e.Recurrence, end, next)
+		}
+		if v.SaleDate.Valid && !next.Before(v.SaleDate.Time) {
+			ok = false
+		}
+
+		if err := s.queries.SetExpenseNextDue(ctx, repository.SetExpenseNextDueParams{
+			ID:          e.ID,
+			NextDueDate: sql.NullTime{Time: next, Valid: ok},
+		}); err != nil {
+			return fmt.Errorf("set next due date of expense %d: %w", e.ID, err)
+		}
+	}
+
+	if created > 0 {
+		log.Printf("Scheduler: Generated %d recurring expenses", created)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/insights"
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// checkEconomy looks for vehicles whose fuel economy dropped over their last few full-tank
+// fills, records an insight and notifies subscribers. Open insights of vehicles that have
+// recovered are resolved.
+func (s *Scheduler) checkEconomy(ctx context.Context) error {
+	vehicles, err := s.queries.ListVehicles(ctx)
+	if err != nil {
+		return fmt.Errorf("list vehicles: %w", err)
+	}
+
+	var found []notice
+	for _, v := range vehicles {
+		if !fleet.IsActive(v) {
+			continue
+		}
+		fills, err := s.queries.ListFullTankEconomy(ctx, v.ID)
+		if err != nil {
+			return fmt.Errorf("list fuel economy of %s: %w", v.Name, err)
+		}
+		drop, ok := insights.DetectEconomyDrop(fills)
+		if !ok {
+			if _, err := s.queries.ResolveInsights(ctx, repository.ResolveInsightsParams{VehicleID: v.ID, Kind: insights.KindEconomyDrop}); err != nil {
+				return fmt.Errorf("resolve insights of %s: %w", v.Name, err)
+			}
+			continue
+		}
+
+		created, err := s.queries.CreateInsight(ctx, repository.CreateInsightParams{
+			VehicleID:     v.ID,
+			Kind:          insights.KindEconomyDrop,
+			FuelLogID:     drop.FuelLogID,
+			Baseline:      drop.Baseline,
+			Recent:        drop.Recent,
+			ChangePercent: drop.ChangePercent,
+			ZScore:        drop.ZScore,
+		})
+		if err != nil {
+			return fmt.Errorf("record insight of %s: %w", v.Name, err)
+		}
+		if created > 0 {
+			log.Printf("Scheduler: Fuel economy of %s dropped %.0f%%", v.Name, -drop.ChangePercent)
+			found = append(found, economyNotice(v, drop))
+		}
+	}
+	return s.sendNotices(ctx, found)
+}
+
+func economyNotice(v repository.Vehicle, drop insights.EconomyDrop) notice {
+	return notice{
+		key:       fmt.Sprintf("economy:%d", v.ID),
+		vehicleID: v.ID,
+		kind:      messages.KindEconomyDrop,
+		data: messages.Data{
+			Vehicle:      v.Name,
+			Unit:         fleet.UnitOf(v),
+			Economy:      strconv.FormatFloat(drop.Recent, 'f', 1, 64),
+			UsualEconomy: strconv.FormatFloat(drop.Baseline, 'f', 1, 64),
+			DropPercent:  int(math.Round(-drop.ChangePercent)),
+		},
+	}
+}
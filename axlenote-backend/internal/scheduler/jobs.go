@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/axlenote/axlenote-backend/internal/notification"
+)
+
+// Job names, used in schedules, the admin API and run history.
+const (
+	jobReminders  = "reminders"
+	jobDigest     = "digest"
+	jobBackup     = "backup"
+	jobCleanup    = "cleanup"
+	jobThumbnails = "thumbnails"
+	jobWebhooks   = "webhooks"
+	jobMQTT       = "mqtt"
+	jobEconomy    = "economy"
+	jobBudgets    = "budgets"
+	jobExpenses   = "expenses"
+	jobLoans      = "loans"
+)
+
+// How a run was started.
+const (
+	RunTriggerSchedule = "schedule"
+	RunTriggerManual   = "manual"
+)
+
+// Run outcomes recorded in job_runs.
+const (
+	RunStatusRunning = "running"
+	RunStatusSuccess = "success"
+	RunStatusFailed  = "failed"
+)
+
+var (
+	ErrUnknownJob = errors.New("unknown job")
+	ErrJobRunning = errors.New("job is already running")
+)
+
+type job struct {
+	name     string
+	spec     string
+	schedule cron.Schedule // nil when the job is disabled
+	run      func(ctx context.Context) error
+	wake     chan struct{} // buffered, so wakes during a run coalesce into one more run
+}
+
+// JobInfo describes a registered job for the admin API.
+type JobInfo struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Enabled  bool       `json:"enabled"`
+	Running  bool       `json:"running"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+}
+
+func (s *Scheduler) registerJobs() {
+	s.add(jobReminders, scheduleFromEnv("SCHEDULE_REMINDERS", "@hourly"), s.checkReminders)
+	s.add(jobDigest, scheduleFromEnv("SCHEDULE_DIGEST", defaultDigestSpec()), s.runDigests)
+	s.add(jobBackup, scheduleFromEnv("SCHEDULE_BACKUP", "0 3 * * *"), s.runBackup)
+	s.add(jobCleanup, scheduleFromEnv("SCHEDULE_CLEANUP", "30 3 * * *"), s.cleanup)
+	s.add(jobThumbnails, scheduleFromEnv("SCHEDULE_THUMBNAILS", "*/10 * * * *"), s.generateThumbnails)
+	s.add(jobWebhooks, scheduleFromEnv("SCHEDULE_WEBHOOKS", "* * * * *"), s.deliverWebhooks)
+	s.add(jobEconomy, scheduleFromEnv("SCHEDULE_ECONOMY", "0 7 * * *"), s.checkEconomy)
+	s.add(jobBudgets, scheduleFromEnv("SCHEDULE_BUDGETS", "20 * * * *"), s.checkBudgets)
+	s.add(jobExpenses, scheduleFromEnv("SCHEDULE_EXPENSES", "5 0 * * *"), s.generateExpenses)
+	s.add(jobLoans, scheduleFromEnv("SCHEDULE_LOANS", "0 9 * * *"), s.remindEMIs)
+
+	mqttSpec := "off"
+	if s.bridge != nil {
+		mqttSpec = scheduleFromEnv("SCHEDULE_MQTT", "*/15 * * * *")
+	}
+	s.add(jobMQTT, mqttSpec, s.publishMQTT)
+}
+
+func (s *Scheduler) add(name, spec string, run func(ctx context.Context) error) {
+	j := &job{name: name, spec: spec, run: run, wake: make(chan struct{}, 1)}
+	if spec != "off" {
+		schedule, err := cron.ParseStandard(spec)
+		if err != nil {
+			log.Printf("Scheduler: Invalid schedule %q for %s, job disabled: %v", spec, name, err)
+		} else {
+			j.schedule = schedule
+		}
+	}
+	s.jobs = append(s.jobs, j)
+}
+
+func (s *Scheduler) job(name string) *job {
+	for _, j := range s.jobs {
+		if j.name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+// Jobs lists the registered jobs with their schedules.
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	out := make([]JobInfo, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		info := JobInfo{Name: j.name, Schedule: j.spec, Enabled: j.schedule != nil, Running: s.running[j.name]}
+		if j.schedule != nil {
+			next := j.schedule.Next(now)
+			info.NextRun = &next
+		}
+		out = append(out, info)
+	}
+	return out
+}
+
+// scheduleFromEnv reads a cron expression (or "off") from the environment.
+func scheduleFromEnv(key, fallback string) string {
+	spec := strings.TrimSpace(os.Getenv(key))
+	if spec == "" {
+		return fallback
+	}
+	return spec
+}
+
+// defaultDigestSpec keeps NOTIFY_DIGEST_HOUR working for setups that predate SCHEDULE_DIGEST.
+func defaultDigestSpec() string {
+	hour, err := strconv.Atoi(os.Getenv("NOTIFY_DIGEST_HOUR"))
+	if err != nil || hour < 0 || hour > 23 {
+		hour = 8
+	}
+	return fmt.Sprintf("0 %d * * *", hour)
+}
+
+// runDigests sends the daily digest, plus the weekly one on the configured weekday.
+func (s *Scheduler) runDigests(ctx context.Context) error {
+	if err := s.sendDigests(ctx, notification.DigestDaily); err != nil {
+		return err
+	}
+	if time.Now().Weekday() == s.digestWeekday {
+		return s.sendDigests(ctx, notification.DigestWeekly)
+	}
+	return nil
+}
+
+// cleanup prunes job history and finished webhook deliveries older than JOB_HISTORY_DAYS.
+func (s *Scheduler) cleanup(ctx context.Context) error {
+	cutoff := time.Now().AddDate(0, 0, -s.historyDays)
+	removed, err := s.queries.DeleteJobRunsBefore(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("delete job runs: %w", err)
+	}
+	if removed > 0 {
+		log.Printf("Scheduler: Removed %d job runs older than %d days", removed, s.historyDays)
+	}
+
+	removed, err = s.queries.DeleteWebhookDeliveriesBefore(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("delete webhook deliveries: %w", err)
+	}
+	if removed > 0 {
+		log.Printf("Scheduler: Removed %d webhook deliveries older than %d days", removed, s.historyDays)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultLockKey is the Postgres advisory lock shared by every replica of the app.
+const defaultLockKey = 4_153_721_001
+
+// elector keeps at most one replica in charge of scheduled jobs by holding a
+// session-level advisory lock on a dedicated connection. If the leader dies its
+// session ends, Postgres releases the lock and another replica picks it up on
+// its next attempt.
+type elector struct {
+	db       *sql.DB
+	key      int64
+	interval time.Duration
+	enabled  bool
+
+	mu        sync.Mutex
+	conn      *sql.Conn
+	leader    bool
+	onElected func()
+}
+
+func newElector(db *sql.DB) *elector {
+	key, err := strconv.ParseInt(os.Getenv("LEADER_LOCK_KEY"), 10, 64)
+	if err != nil {
+		key = defaultLockKey
+	}
+	interval, err := time.ParseDuration(os.Getenv("LEADER_RETRY_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 15 * time.Second
+	}
+	return &elector{
+		db:       db,
+		key:      key,
+		interval: interval,
+		enabled:  os.Getenv("LEADER_ELECTION") != "false",
+	}
+}
+
+// IsLeader reports whether this instance currently holds the lock.
+func (e *elector) IsLeader() bool {
+	if !e.enabled {
+		return true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// run campaigns for the lock and checks that it is still held until ctx is cancelled.
+func (e *elector) run(ctx context.Context) {
+	if !e.enabled {
+		if e.onElected != nil {
+			e.onElected()
+		}
+		return
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.tick(ctx)
+		select {
+		case <-ctx.Done():
+			e.resign()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *elector) tick(ctx context.Context) {
+	e.mu.Lock()
+	leader := e.leader
+	e.mu.Unlock()
+
+	if leader {
+		// The lock lives as long as the session, so a healthy connection means we still hold it
+		if err := e.conn.PingContext(ctx); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Scheduler: Lost leader connection, stepping down: %v", err)
+			}
+			e.resign()
+		}
+		return
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Scheduler: Leader election failed: %v", err)
+		}
+		return
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", e.key).Scan(&acquired); err != nil {
+		conn.Close()
+		if ctx.Err() == nil {
+			log.Printf("Scheduler: Leader election failed: %v", err)
+		}
+		return
+	}
+	if !acquired {
+		conn.Close()
+		return
+	}
+
+	e.mu.Lock()
+	e.conn = conn
+	e.leader = true
+	e.mu.Unlock()
+
+	log.Println("Scheduler: This instance is now the leader")
+	if e.onElected != nil {
+		e.onElected()
+	}
+}
+
+// resign releases the lock so another replica can take over without waiting for the session to time out.
+func (e *elector) resign() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		e.leader = false
+		return
+	}
+
+	released := false
+	if e.leader {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if _, err := e.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", e.key); err != nil {
+			log.Printf("Scheduler: Failed to release leader lock: %v", err)
+		} else {
+			released = true
+		}
+		cancel()
+		log.Println("Scheduler: Stepped down as leader")
+	}
+
+	if !released {
+		// A session that may still hold the lock must not go back to the pool
+		e.conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	e.conn.Close()
+	e.conn = nil
+	e.leader = false
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/loan"
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/tco"
+)
+
+// emiReminderDays is how many days before an EMI falls due to remind of it, from
+// LOAN_REMINDER_DAYS (default 3).
+func emiReminderDays() int {
+	days, err := strconv.Atoi(os.Getenv("LOAN_REMINDER_DAYS"))
+	if err != nil || days < 0 {
+		return 3
+	}
+	return days
+}
+
+// remindEMIs notifies subscribers of each loan's next EMI as its due date approaches.
+// Every due date is reminded of once; EMIs already overdue when first seen are skipped,
+// so adding an old loan does not send a burst of stale reminders. Loans of sold and
+// scrapped vehicles are left out; a stored vehicle's EMIs are still due.
+func (s *Scheduler) remindEMIs(ctx context.Context) error {
+	loans, err := s.queries.ListLoans(ctx)
+	if err != nil {
+		return fmt.Errorf("list loans: %w", err)
+	}
+	if len(loans) == 0 {
+		return nil
+	}
+	payments, err := s.queries.ListAllLoanPayments(ctx)
+	if err != nil {
+		return fmt.Errorf("list loan payments: %w", err)
+	}
+	byLoan := make(map[int32][]repository.LoanPayment)
+	for _, p := range payments {
+		byLoan[p.LoanID] = append(byLoan[p.LoanID], p)
+	}
+	vehicles, err := s.queries.ListVehicles(ctx)
+	if err != nil {
+		return fmt.Errorf("list vehicles: %w", err)
+	}
+	byID := make(map[int32]repository.Vehicle, len(vehicles))
+	for _, v := range vehicles {
+		byID[v.ID] = v
+	}
+
+	today := forecast.Today()
+	lead := emiReminderDays()
+	var found []notice
+	for _, l := range loans {
+		v := byID[l.VehicleID]
+		if fleet.IsArchived(v) {
+			continue
+		}
+		status := loan.ForLoan(l, byLoan[l.ID], today).Status
+		due, ok := status.NextDue()
+		if !ok {
+			continue
+		}
+		days := int(due.Sub(today).Hours() / 24)
+		if days < 0 || days > lead {
+			continue
+		}
+
+		recorded, err := s.queries.RecordLoanReminder(ctx, repository.RecordLoanReminderParams{
+			LoanID:  l.ID,
+			DueDate: due,
+		})
+		if err != nil {
+			return fmt.Errorf("record reminder of loan %d: %w", l.ID, err)
+		}
+		if recorded == 0 {
+			continue
+		}
+
+		lender := l.Lender.String
+		if lender == "" {
+			lender = "Loan"
+		}
+		log.Printf("Scheduler: EMI of loan %d for %s due %s", l.ID, v.Name, status.NextDueDate)
+		found = append(found, notice{
+			key:       fmt.Sprintf("emi:%d:%s", l.ID, status.NextDueDate),
+			vehicleID: l.VehicleID,
+			kind:      messages.KindEMIDue,
+			data: messages.Data{
+				Vehicle:       v.Name,
+				Lender:        lender,
+				Currency:      tco.Currency(),
+				Amount:        strconv.FormatFloat(status.NextAmount, 'f', 2, 64),
+				Outstanding:   strconv.FormatFloat(status.Outstanding, 'f', 2, 64),
+				DueDate:       status.NextDueDate,
+				DaysRemaining: days,
+			},
+		})
+	}
+	return s.sendNotices(ctx, found)
+}
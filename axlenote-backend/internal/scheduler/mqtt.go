@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
+)
+
+// PublishMQTT wakes the MQTT job so Home Assistant sees a change right away.
+// It does nothing when MQTT is not configured.
+func (s *Scheduler) PublishMQTT() {
+	if s.bridge == nil {
+		return
+	}
+	s.wake(jobMQTT)
+}
+
+// RemoveMQTTVehicle removes a deleted, sold or scrapped vehicle from Home Assistant.
+func (s *Scheduler) RemoveMQTTVehicle(vehicleID int32) {
+	if s.bridge == nil {
+		return
+	}
+	if err := s.bridge.RemoveVehicle(vehicleID); err != nil {
+		log.Printf("MQTT: Failed to remove vehicle %d: %v", vehicleID, err)
+	}
+}
+
+// publishMQTT refreshes discovery and state for every vehicle.
+func (s *Scheduler) publishMQTT(ctx context.Context) error {
+	if s.bridge == nil {
+		return nil
+	}
+	return s.bridge.PublishAll(ctx)
+}
+
+// readingPayload matches the odometer reading representation of the REST API.
+func readingPayload(r repository.OdometerReading) map[string]any {
+	return map[string]any{
+		"id":          r.ID,
+		"vehicle_id":  r.VehicleID,
+		"odometer":    r.Odometer,
+		"recorded_at": r.RecordedAt.Format(time.RFC3339),
+		"source":      r.Source,
+	}
+}
+
+// odometerLogged handles a reading received over MQTT like one posted to the API:
+// webhooks are notified and the vehicle's sensors are refreshed.
+func (s *Scheduler) odometerLogged(reading repository.OdometerReading) {
+	queued, err := webhook.Enqueue(s.context(), s.queries, webhook.OdometerReadingCreated, readingPayload(reading))
+	if err != nil {
+		log.Printf("Scheduler: Failed to queue %s webhooks: %v", webhook.OdometerReadingCreated, err)
+	} else if queued > 0 {
+		s.DeliverWebhooks()
+	}
+
+	if err := s.bridge.PublishVehicle(s.context(), reading.VehicleID); err != nil {
+		log.Printf("MQTT: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/notification"
+)
+
+// notice is a notification about something other than a reminder, like an insight or a
+// budget alert. Subscriptions only filter notices by vehicle.
+type notice struct {
+	key       string // identifies what it is about, so it is delivered once per target
+	vehicleID int32  // 0 for notices about the whole garage
+	kind      string // one of the messages.Kind* kinds
+	data      messages.Data
+}
+
+// sendNotices delivers notices right away to every subscriber that gets immediate pushes,
+// or to NOTIFY_TOPIC when nobody has subscribed.
+func (s *Scheduler) sendNotices(ctx context.Context, notices []notice) error {
+	if len(notices) == 0 {
+		return nil
+	}
+
+	subs, err := s.queries.ListActiveSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("list subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		for _, n := range notices {
+			title, msg := s.renderNotice(n, messages.DefaultLocale, nil)
+			s.deliver(notification.Target{Channel: notification.ChannelNtfy}, title, msg)
+		}
+		return nil
+	}
+
+	templates, err := s.queries.ListNotificationTemplates(ctx)
+	if err != nil {
+		log.Printf("Scheduler: Failed to list notification templates: %v", err)
+	}
+	custom := make(map[string]messages.Template)
+	for _, t := range templates {
+		custom[templateKey(t.UserID, t.Kind)] = messages.Template{Title: t.Title, Body: t.Body}
+	}
+
+	sent := make(map[string]bool)
+	for _, sub := range subs {
+		if sub.Digest != notification.DigestNone {
+			continue
+		}
+		target := subscriptionTarget(sub)
+		for _, n := range notices {
+			if sub.VehicleID.Valid && n.vehicleID != 0 && sub.VehicleID.Int32 != n.vehicleID {
+				continue
+			}
+			key := fmt.Sprintf("%s|%s|%s", target.Channel, target.Address, n.key)
+			if sent[key] {
+				continue
+			}
+			sent[key] = true
+
+			var override *messages.Template
+			if t, ok := custom[templateKey(sub.UserID, n.kind)]; ok {
+				override = &t
+			}
+			title, msg := s.renderNotice(n, sub.UserLocale, override)
+			s.deliver(target, title, msg)
+		}
+	}
+	return nil
+}
+
+// renderNotice builds the notification for a notice like render does for reminders.
+func (s *Scheduler) renderNotice(n notice, locale string, override *messages.Template) (string, string) {
+	if override != nil {
+		title, body, err := messages.Render(*override, n.data)
+		if err == nil {
+			return title, body
+		}
+		log.Printf("Scheduler: Custom template %s failed, using default: %v", n.kind, err)
+	}
+
+	title, body, err := messages.Render(messages.Bundled(locale, n.kind), n.data)
+	if err != nil {
+		log.Printf("Scheduler: Bundled template %s/%s failed: %v", locale, n.kind, err)
+	}
+	return title, body
+}
@@ -0,0 +1,210 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/notification"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// defaultLeadDays is how early an upcoming due date is reported when nobody asked otherwise.
+const defaultLeadDays = 7
+
+// alert is a reminder or document that is overdue or coming up, before it is routed to subscribers.
+type alert struct {
+	vehicle    repository.Vehicle
+	reminder   repository.Reminder
+	document   repository.Document // set instead of reminder for document expiry alerts
+	level      string              // notification.TriggerOverdue or notification.TriggerUpcoming
+	trigger    string              // one of the messages.Trigger* kinds
+	daysLeft   int                 // days until the (projected) due date, -1 when unknown
+	currentOdo int32
+	projected  time.Time // projected date for odometer reminders, zero when not projected
+}
+
+func (a alert) isDocument() bool {
+	return a.document.ID != 0
+}
+
+func (a alert) title() string {
+	if a.isDocument() {
+		return a.document.Name
+	}
+	return a.reminder.Title
+}
+
+// category is the reminder or document type that subscriptions filter on.
+func (a alert) category() string {
+	if a.isDocument() {
+		if a.document.Type.Valid {
+			return a.document.Type.String
+		}
+		return a.document.Category.String
+	}
+	return a.reminder.Type.String
+}
+
+// key identifies what the alert is about, so it is only delivered once per target.
+func (a alert) key() string {
+	if a.isDocument() {
+		return fmt.Sprintf("document:%d", a.document.ID)
+	}
+	return fmt.Sprintf("reminder:%d", a.reminder.ID)
+}
+
+func (a alert) kind() string {
+	if a.level == notification.TriggerOverdue {
+		return messages.KindReminderOverdue
+	}
+	return messages.KindReminderUpcoming
+}
+
+// data fills in the template placeholders, including the localized trigger text.
+func (a alert) data(locale, unit string) messages.Data {
+	d := messages.Data{
+		Vehicle:         a.vehicle.Name,
+		Reminder:        a.title(),
+		Type:            a.category(),
+		Level:           a.level,
+		DueOdometer:     a.reminder.DueOdometer.Int32,
+		CurrentOdometer: a.currentOdo,
+		DaysRemaining:   a.daysLeft,
+		Unit:            unit,
+	}
+	if a.reminder.DueDate.Valid {
+		d.DueDate = a.reminder.DueDate.Time.Format("2006-01-02")
+	}
+	if a.document.ExpiryDate.Valid {
+		d.DueDate = a.document.ExpiryDate.Time.Format("2006-01-02")
+	}
+	if !a.projected.IsZero() {
+		d.ProjectedDate = a.projected.Format("2006-01-02")
+	}
+	if a.reminder.DueOdometer.Valid {
+		d.KmRemaining = a.reminder.DueOdometer.Int32 - a.currentOdo
+	}
+	d.Trigger = messages.TriggerText(locale, a.trigger, d)
+	return d
+}
+
+func (a alert) withinLead(leadDays int32) bool {
+	if a.level == notification.TriggerOverdue || a.daysLeft < 0 {
+		return true
+	}
+	return int32(a.daysLeft) <= leadDays
+}
+
+// matches reports whether a subscription wants to hear about this alert.
+func (a alert) matches(sub repository.ListActiveSubscriptionsRow) bool {
+	if sub.VehicleID.Valid && sub.VehicleID.Int32 != a.vehicle.ID {
+		return false
+	}
+	if len(sub.ReminderTypes) > 0 && !containsFold(sub.ReminderTypes, a.category()) {
+		return false
+	}
+	if len(sub.Triggers) > 0 && !containsFold(sub.Triggers, a.level) {
+		return false
+	}
+	return a.withinLead(sub.LeadDays)
+}
+
+func subscriptionTarget(sub repository.ListActiveSubscriptionsRow) notification.Target {
+	if sub.Channel == notification.ChannelEmail {
+		return notification.Target{Channel: notification.ChannelEmail, Address: sub.UserEmail.String}
+	}
+	return notification.Target{Channel: notification.ChannelNtfy, Address: sub.UserNtfyTopic.String}
+}
+
+// route delivers each alert to every matching subscriber that wants immediate pushes.
+// Digest subscribers are handled by sendDigests.
+func (s *Scheduler) route(alerts []alert, subs []repository.ListActiveSubscriptionsRow, templates []repository.NotificationTemplate) {
+	custom := make(map[string]messages.Template)
+	for _, t := range templates {
+		custom[templateKey(t.UserID, t.Kind)] = messages.Template{Title: t.Title, Body: t.Body}
+	}
+
+	// A user with overlapping subscriptions should still only hear about a reminder once per target
+	sent := make(map[string]bool)
+
+	for _, sub := range subs {
+		if sub.Digest != notification.DigestNone {
+			continue
+		}
+		target := subscriptionTarget(sub)
+
+		for _, a := range alerts {
+			key := fmt.Sprintf("%s|%s|%s", target.Channel, target.Address, a.key())
+			if sent[key] || !a.matches(sub) {
+				continue
+			}
+			sent[key] = true
+			if s.sentBefore(a, target) {
+				continue
+			}
+
+			var override *messages.Template
+			if t, ok := custom[templateKey(sub.UserID, a.kind())]; ok {
+				override = &t
+			}
+			title, msg := s.render(a, sub.UserLocale, override)
+			s.deliver(target, title, msg)
+		}
+	}
+}
+
+// sentBefore records a document alert for a target and reports whether it had already
+// been sent, so a document is announced once as expiring and once as expired rather than
+// on every run. Reminder alerts are not recorded.
+func (s *Scheduler) sentBefore(a alert, target notification.Target) bool {
+	if !a.isDocument() {
+		return false
+	}
+	recorded, err := s.queries.RecordDocumentAlert(s.context(), repository.RecordDocumentAlertParams{
+		DocumentID: a.document.ID,
+		ExpiryDate: a.document.ExpiryDate.Time,
+		Level:      a.level,
+		Target:     target.Channel + ":" + target.Address,
+	})
+	if err != nil {
+		// Skip rather than risk repeating the alert every run
+		log.Printf("Scheduler: Failed to record alert of document %d: %v", a.document.ID, err)
+		return true
+	}
+	return recorded == 0
+}
+
+// render builds the notification for an alert from the user's template, falling back
+// to the bundled translation if the custom template fails.
+func (s *Scheduler) render(a alert, locale string, override *messages.Template) (string, string) {
+	data := a.data(locale, s.unit)
+	if override != nil {
+		title, body, err := messages.Render(*override, data)
+		if err == nil {
+			return title, body
+		}
+		log.Printf("Scheduler: Custom template %s failed, using default: %v", a.kind(), err)
+	}
+
+	title, body, err := messages.Render(messages.Bundled(locale, a.kind()), data)
+	if err != nil {
+		log.Printf("Scheduler: Bundled template %s/%s failed: %v", locale, a.kind(), err)
+	}
+	return title, body
+}
+
+func templateKey(userID int32, kind string) string {
+	return fmt.Sprintf("%d|%s", userID, kind)
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,48 +5,328 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/axlenote/axlenote-backend/internal/fleet"
+	"github.com/axlenote/axlenote-backend/internal/forecast"
+	"github.com/axlenote/axlenote-backend/internal/messages"
+	"github.com/axlenote/axlenote-backend/internal/mqtt"
 	"github.com/axlenote/axlenote-backend/internal/notification"
 	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/storage"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
 )
 
 type Scheduler struct {
-	db       *sql.DB
-	queries  *repository.Queries
-	notifier *notification.Service
+	db            *sql.DB
+	queries       *repository.Queries
+	notifier      *notification.Service
+	storage       *storage.Store
+	webhooks      *webhook.Client
+	bridge        *mqtt.Bridge // nil when MQTT is not configured
+	digestWeekday time.Weekday
+	unit          string
+	runOnStart    bool
+	backup        backupConfig
+	historyDays   int
+	elector       *elector
+
+	jobs    []*job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]bool
 }
 
-func New(db *sql.DB, queries *repository.Queries, notifier *notification.Service) *Scheduler {
-	return &Scheduler{
-		db:       db,
-		queries:  queries,
-		notifier: notifier,
+func New(db *sql.DB, queries *repository.Queries, notifier *notification.Service, store *storage.Store) *Scheduler {
+	unit := os.Getenv("METRICS_UNIT")
+	if unit == "" {
+		unit = "km"
+	}
+	digestWeekday, err := strconv.Atoi(os.Getenv("NOTIFY_DIGEST_WEEKDAY"))
+	if err != nil || digestWeekday < 0 || digestWeekday > 6 {
+		digestWeekday = int(time.Monday)
+	}
+	historyDays, err := strconv.Atoi(os.Getenv("JOB_HISTORY_DAYS"))
+	if err != nil || historyDays <= 0 {
+		historyDays = 30
+	}
+	s := &Scheduler{
+		db:            db,
+		queries:       queries,
+		notifier:      notifier,
+		storage:       store,
+		webhooks:      webhook.NewClient(),
+		bridge:        mqtt.New(queries),
+		digestWeekday: time.Weekday(digestWeekday),
+		unit:          unit,
+		runOnStart:    os.Getenv("SCHEDULER_RUN_ON_START") == "true",
+		backup:        backupConfigFromEnv(),
+		historyDays:   historyDays,
+		running:       make(map[string]bool),
+		elector:       newElector(db),
 	}
+	s.registerJobs()
+	return s
 }
 
-func (s *Scheduler) Start() {
-	// Run immediately on start
-	go s.checkReminders()
+// Start runs every enabled job on its schedule until ctx is cancelled or Stop is called.
+// With several replicas only the elected leader runs scheduled jobs.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.ctx, s.cancel = context.WithCancel(ctx)
 
-	// Then run every hour
-	ticker := time.NewTicker(1 * time.Hour)
+	if s.runOnStart {
+		var once sync.Once
+		s.elector.onElected = func() {
+			once.Do(func() {
+				if _, err := s.Trigger(jobReminders); err != nil {
+					log.Printf("Scheduler: Failed to run %s on start: %v", jobReminders, err)
+				}
+			})
+		}
+	}
+	s.wg.Add(1)
 	go func() {
-		for range ticker.C {
-			s.checkReminders()
+		defer s.wg.Done()
+		s.elector.run(s.ctx)
+	}()
+
+	if s.bridge != nil {
+		s.bridge.Start(s.ctx, mqtt.Options{Accept: s.IsLeader, OnReading: s.odometerLogged})
+	}
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.wakeLoop(j)
+
+		if j.schedule == nil {
+			log.Printf("Scheduler: Job %s is disabled", j.name)
+			continue
+		}
+		log.Printf("Scheduler: Job %s scheduled (%s)", j.name, j.spec)
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+}
+
+// IsLeader reports whether this instance is running scheduled jobs.
+func (s *Scheduler) IsLeader() bool {
+	return s.elector.IsLeader()
+}
+
+// Stop cancels pending runs and waits for running jobs to finish.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	if s.bridge != nil {
+		s.bridge.Close()
+	}
+	log.Println("Scheduler: Stopped")
+}
+
+func (s *Scheduler) loop(j *job) {
+	defer s.wg.Done()
+	for {
+		timer := time.NewTimer(time.Until(j.schedule.Next(time.Now())))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if !s.elector.IsLeader() {
+				continue
+			}
+			run, err := s.begin(j, RunTriggerSchedule)
+			if err != nil {
+				log.Printf("Scheduler: Skipping %s: %v", j.name, err)
+				continue
+			}
+			s.execute(j, run)
+		}
+	}
+}
+
+// wake asks for a job to run soon on this replica, without recording it in the job history.
+// It never blocks; wakes while the job is running or already woken are merged.
+func (s *Scheduler) wake(name string) {
+	if j := s.job(name); j != nil {
+		select {
+		case j.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// wakeLoop runs a job each time it is woken, e.g. after a request queued webhooks. These
+// runs follow every write, so they are left out of the job history. A wake while the job
+// is already running is dropped, and that run or the next one picks up the change.
+func (s *Scheduler) wakeLoop(j *job) {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-j.wake:
+			if !s.claim(j) {
+				continue
+			}
+			if err := j.run(s.ctx); err != nil {
+				log.Printf("Scheduler: Job %s failed: %v", j.name, err)
+			}
+			s.release(j)
 		}
+	}
+}
+
+// Trigger starts a job immediately, outside its schedule, and returns its run record.
+// Manual runs are not limited to the leader, so they work on whichever replica receives the request.
+func (s *Scheduler) Trigger(name string) (repository.JobRun, error) {
+	j := s.job(name)
+	if j == nil {
+		return repository.JobRun{}, ErrUnknownJob
+	}
+
+	run, err := s.begin(j, RunTriggerManual)
+	if err != nil {
+		return run, err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(j, run)
 	}()
+	return run, nil
+}
+
+// claim marks a job as running, or reports false if it already is.
+func (s *Scheduler) claim(j *job) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[j.name] {
+		return false
+	}
+	s.running[j.name] = true
+	return true
+}
+
+func (s *Scheduler) release(j *job) {
+	s.mu.Lock()
+	delete(s.running, j.name)
+	s.mu.Unlock()
 }
 
-func (s *Scheduler) checkReminders() {
-	ctx := context.Background()
+// begin marks a job as running and records the start of a run.
+func (s *Scheduler) begin(j *job, trigger string) (repository.JobRun, error) {
+	if !s.claim(j) {
+		return repository.JobRun{}, ErrJobRunning
+	}
+
+	run, err := s.queries.CreateJobRun(s.context(), repository.CreateJobRunParams{JobName: j.name, Trigger: trigger})
+	if err != nil {
+		// History is best effort; the job itself should still run
+		log.Printf("Scheduler: Failed to record run of %s: %v", j.name, err)
+		run = repository.JobRun{JobName: j.name, Trigger: trigger, Status: RunStatusRunning, StartedAt: time.Now()}
+	}
+	return run, nil
+}
+
+func (s *Scheduler) execute(j *job, run repository.JobRun) {
+	defer s.release(j)
+
+	log.Printf("Scheduler: Running %s (%s)", j.name, run.Trigger)
+	err := j.run(s.context())
 
-	// Get all vehicles
-	vehicles, err := s.queries.ListVehicles(ctx)
+	status := RunStatusSuccess
+	var errMsg sql.NullString
 	if err != nil {
-		log.Printf("Scheduler: Failed to list vehicles: %v", err)
+		status = RunStatusFailed
+		errMsg = sql.NullString{String: err.Error(), Valid: true}
+		log.Printf("Scheduler: Job %s failed: %v", j.name, err)
+	}
+
+	if run.ID == 0 {
 		return
 	}
+	// Record the outcome even if we are shutting down
+	_, err = s.queries.FinishJobRun(context.Background(), repository.FinishJobRunParams{
+		ID:     run.ID,
+		Status: status,
+		Error:  errMsg,
+	})
+	if err != nil {
+		log.Printf("Scheduler: Failed to record result of %s: %v", j.name, err)
+	}
+}
+
+func (s *Scheduler) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+func (s *Scheduler) checkReminders(ctx context.Context) error {
+	subs, err := s.queries.ListActiveSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("list subscriptions: %w", err)
+	}
+
+	// Warn as early as the most eager subscriber wants to hear about it
+	leadDays := int32(defaultLeadDays)
+	for _, sub := range subs {
+		if sub.LeadDays > leadDays {
+			leadDays = sub.LeadDays
+		}
+	}
+
+	alerts, err := s.collectAlerts(ctx, leadDays)
+	if err != nil {
+		return err
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	// Without any subscriptions, fall back to the single NOTIFY_TOPIC
+	if len(subs) == 0 {
+		target := notification.Target{Channel: notification.ChannelNtfy}
+		for _, a := range alerts {
+			if a.withinLead(defaultLeadDays) && !s.sentBefore(a, target) {
+				title, msg := s.render(a, messages.DefaultLocale, nil)
+				s.deliver(target, title, msg)
+			}
+		}
+		return nil
+	}
+
+	templates, err := s.queries.ListNotificationTemplates(ctx)
+	if err != nil {
+		log.Printf("Scheduler: Failed to list notification templates: %v", err)
+	}
+
+	s.route(alerts, subs, templates)
+	return nil
+}
+
+func (s *Scheduler) collectAlerts(ctx context.Context, leadDays int32) ([]alert, error) {
+	var alerts []alert
+	now := time.Now()
+	today := forecast.Today()
+
+	// Stored, sold and scrapped vehicles get no reminders or document alerts
+	all, err := s.queries.ListVehicles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list vehicles: %w", err)
+	}
+	vehicles := slices.DeleteFunc(all, func(v repository.Vehicle) bool { return !fleet.IsActive(v) })
 
 	for _, v := range vehicles {
 		// Get reminders for vehicle
@@ -59,64 +339,107 @@ func (s *Scheduler) checkReminders() {
 			continue
 		}
 
-		// Calculate current odometer (max of service or fuel logs)
-		// We can get this from a custom query or just separate checks.
-		// For simplicity/performance, let's just make a helper map or query.
-		// Using GetVehicleStats could be heavy if called in loop but fine for small user base.
-
-		currentOdo := int32(0) // Default
-		// Wait, GetVehicleStats returns sums, not max odometer.
-		// We'll need a quick query execution here or assume 'currentOdo' logic.
-		// Let's execute a raw query to get max odometer for accuracy.
-		var maxOdo sql.NullInt32
-		err = s.db.QueryRowContext(ctx, `
-			SELECT GREATEST(
-				(SELECT COALESCE(MAX(odometer), 0) FROM service_records WHERE vehicle_id = $1),
-				(SELECT COALESCE(MAX(odometer), 0) FROM fuel_logs WHERE vehicle_id = $1)
-			)
-		`, v.ID).Scan(&maxOdo)
-
-		if err == nil && maxOdo.Valid {
-			currentOdo = maxOdo.Int32
+		// Current odometer from services, fuel logs and readings, as the driving rate uses
+		currentOdo, err := s.queries.GetVehicleOdometer(ctx, v.ID)
+		if err != nil {
+			log.Printf("Scheduler: Failed to get odometer for %s: %v", v.Name, err)
+		}
+
+		rate, hasRate, err := forecast.ForVehicle(ctx, s.queries, v.ID, now)
+		if err != nil {
+			log.Printf("Scheduler: Failed to estimate driving rate for %s: %v", v.Name, err)
 		}
 
 		for _, r := range reminders {
-			shouldNotify := false
-			trigger := ""
+			a := alert{vehicle: v, reminder: r, daysLeft: -1, currentOdo: currentOdo}
 
 			// Date Check
 			if r.DueDate.Valid && !r.DueDate.Time.IsZero() {
+				until := time.Until(r.DueDate.Time)
 				// Due today or in the past
-				if time.Now().After(r.DueDate.Time) {
-					shouldNotify = true
-					trigger = fmt.Sprintf("Date Due: %s", r.DueDate.Time.Format("2006-01-02"))
-				}
-				// Warning: Due within 7 days
-				if time.Until(r.DueDate.Time) < 7*24*time.Hour && time.Until(r.DueDate.Time) > 0 {
-					shouldNotify = true
-					trigger = fmt.Sprintf("Upcoming Due Date: %s", r.DueDate.Time.Format("2006-01-02"))
+				if until <= 0 {
+					a.level = notification.TriggerOverdue
+					a.trigger = messages.TriggerDateDue
+				} else if days := int(until.Hours()/24) + 1; int32(days) <= leadDays {
+					// Warning: Due within the lead time
+					a.level = notification.TriggerUpcoming
+					a.daysLeft = days
+					a.trigger = messages.TriggerDateUpcoming
 				}
 			}
 
 			// Odometer Check
 			if r.DueOdometer.Valid && r.DueOdometer.Int32 > 0 {
 				if currentOdo >= r.DueOdometer.Int32 {
-					shouldNotify = true
-					trigger = fmt.Sprintf("Odometer Reached: %d km", r.DueOdometer.Int32)
+					a.level = notification.TriggerOverdue
+					a.daysLeft = -1
+					a.trigger = messages.TriggerOdometerReached
+				} else if hasRate {
+					// Warn by projected date so lead days apply to odometer reminders too
+					days := rate.DaysUntil(r.DueOdometer.Int32, today)
+					if int32(days) <= leadDays && (a.level == "" || (a.level == notification.TriggerUpcoming && days < a.daysLeft)) {
+						a.level = notification.TriggerUpcoming
+						a.daysLeft = days
+						a.trigger = messages.TriggerOdometerProjected
+						a.projected = rate.DueDate(r.DueOdometer.Int32, today)
+					}
 				} else if r.DueOdometer.Int32-currentOdo < 500 {
-					shouldNotify = true
-					trigger = fmt.Sprintf("Odometer Approaching: %d km (Current: %d)", r.DueOdometer.Int32, currentOdo)
+					// Not enough history to project a date
+					a.level = notification.TriggerUpcoming
+					a.daysLeft = -1
+					a.trigger = messages.TriggerOdometerApproaching
 				}
 			}
 
-			if shouldNotify {
-				msg := fmt.Sprintf("Vehicle: %s\nReminder: %s\nTrigger: %s", v.Name, r.Title, trigger)
-				log.Printf("Sending notification: %s", msg)
-				err := s.notifier.Send(fmt.Sprintf("Reminder: %s", r.Title), msg)
-				if err != nil {
-					log.Printf("Failed to send notification: %v", err)
-				}
+			if a.level != "" {
+				alerts = append(alerts, a)
 			}
 		}
 	}
+
+	docAlerts, err := s.collectDocumentAlerts(ctx, vehicles, leadDays, today)
+	if err != nil {
+		return nil, err
+	}
+	return append(alerts, docAlerts...), nil
+}
+
+// collectDocumentAlerts finds current documents that have expired or expire within the lead
+// time. Whether each target has already been told is checked when routing.
+func (s *Scheduler) collectDocumentAlerts(ctx context.Context, vehicles []repository.Vehicle, leadDays int32, today time.Time) ([]alert, error) {
+	docs, err := s.queries.ListExpiringDocuments(ctx, today.AddDate(0, 0, int(leadDays)))
+	if err != nil {
+		return nil, fmt.Errorf("list expiring documents: %w", err)
+	}
+
+	byID := make(map[int32]repository.Vehicle, len(vehicles))
+	for _, v := range vehicles {
+		byID[v.ID] = v
+	}
+
+	var alerts []alert
+	for _, d := range docs {
+		v, ok := byID[d.VehicleID.Int32]
+		if !ok {
+			continue
+		}
+		a := alert{vehicle: v, document: d, daysLeft: -1}
+		if days := int(d.ExpiryDate.Time.Sub(today).Hours() / 24); days < 0 {
+			a.level = notification.TriggerOverdue
+			a.trigger = messages.TriggerDocumentExpired
+		} else {
+			a.level = notification.TriggerUpcoming
+			a.trigger = messages.TriggerDocumentExpiring
+			a.daysLeft = days
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
+
+func (s *Scheduler) deliver(target notification.Target, title, msg string) {
+	log.Printf("Sending notification: %s", msg)
+	if err := s.notifier.Deliver(target, title, msg); err != nil {
+		log.Printf("Failed to send notification: %v", err)
+	}
 }
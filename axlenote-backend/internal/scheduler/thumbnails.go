@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/thumbnail"
+)
+
+// thumbnailBatch is how many files are processed per query, so a large backlog is worked through in chunks.
+const thumbnailBatch = 50
+
+// GenerateThumbnails wakes the thumbnail job, e.g. right after an upload, instead of
+// waiting for its schedule. If it is already running the file is picked up on its next pass.
+func (s *Scheduler) GenerateThumbnails() {
+	s.wake(jobThumbnails)
+}
+
+// generateThumbnails renders previews for every uploaded file that does not have one yet.
+func (s *Scheduler) generateThumbnails(ctx context.Context) error {
+	generated := 0
+	for {
+		files, err := s.queries.ListPendingThumbnails(ctx, thumbnailBatch)
+		if err != nil {
+			return fmt.Errorf("list pending thumbnails: %w", err)
+		}
+
+		for _, f := range files {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			status, genErr := s.thumbnail(f)
+			var errMsg sql.NullString
+			if genErr != nil {
+				log.Printf("Scheduler: Thumbnail for file %d failed: %v", f.ID, genErr)
+				errMsg = sql.NullString{String: genErr.Error(), Valid: true}
+			}
+			err := s.queries.SetThumbnailStatus(ctx, repository.SetThumbnailStatusParams{
+				ID:              f.ID,
+				ThumbnailStatus: status,
+				ThumbnailError:  errMsg,
+			})
+			if err != nil {
+				// Stop rather than pick the same files up again
+				return fmt.Errorf("update thumbnail status: %w", err)
+			}
+			if status == thumbnail.StatusReady {
+				generated++
+			}
+		}
+
+		if len(files) < thumbnailBatch {
+			break
+		}
+	}
+
+	if generated > 0 {
+		log.Printf("Scheduler: Generated %d thumbnails", generated)
+	}
+	return nil
+}
+
+func (s *Scheduler) thumbnail(f repository.DocumentFile) (string, error) {
+	if !thumbnail.Supported(f.ContentType) {
+		return thumbnail.StatusUnsupported, nil
+	}
+
+	r, err := s.storage.Open(f.StorageKey)
+	if err != nil {
+		return thumbnail.StatusFailed, err
+	}
+	defer r.Close()
+
+	data, err := thumbnail.Generate(r, f.ContentType)
+	if err != nil {
+		return thumbnail.StatusFailed, err
+	}
+	if err := s.storage.SaveVariant(f.StorageKey, thumbnail.Variant, data); err != nil {
+		return thumbnail.StatusFailed, err
+	}
+	return thumbnail.StatusReady, nil
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+	"github.com/axlenote/axlenote-backend/internal/webhook"
+)
+
+const (
+	// webhookBatch is how many deliveries are claimed per query.
+	webhookBatch = 20
+	// webhookLease keeps a claimed delivery from being sent by another worker while in flight.
+	webhookLease = 5 * time.Minute
+)
+
+// DeliverWebhooks wakes the webhook job, e.g. right after an event is queued.
+// If it is already running the delivery is picked up on its next pass.
+func (s *Scheduler) DeliverWebhooks() {
+	s.wake(jobWebhooks)
+}
+
+// deliverWebhooks sends every delivery that is due. Failures are rescheduled with
+// exponential backoff until the attempt limit, then marked failed.
+func (s *Scheduler) deliverWebhooks(ctx context.Context) error {
+	hooks := make(map[int32]*repository.Webhook)
+	sent, failed := 0, 0
+	for {
+		deliveries, err := s.queries.ClaimWebhookDeliveries(ctx, repository.ClaimWebhookDeliveriesParams{
+			LeaseSeconds: int32(webhookLease / time.Second),
+			Batch:        webhookBatch,
+		})
+		if err != nil {
+			return fmt.Errorf("claim webhook deliveries: %w", err)
+		}
+
+		for _, d := range deliveries {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			hook, ok := hooks[d.WebhookID]
+			if !ok {
+				h, err := s.queries.GetWebhook(ctx, d.WebhookID)
+				if err != nil && err != sql.ErrNoRows {
+					return fmt.Errorf("get webhook %d: %w", d.WebhookID, err)
+				}
+				if err == nil {
+					hook = &h
+				}
+				hooks[d.WebhookID] = hook
+			}
+			if hook == nil {
+				// Removed while the delivery was queued; the cascade takes care of the row
+				continue
+			}
+
+			delivered, err := s.deliverWebhook(ctx, *hook, d)
+			if err != nil {
+				// Stop rather than resend deliveries whose outcome could not be saved
+				return err
+			}
+			if delivered {
+				sent++
+			} else {
+				failed++
+			}
+		}
+
+		if len(deliveries) < webhookBatch {
+			break
+		}
+	}
+
+	if sent > 0 || failed > 0 {
+		log.Printf("Scheduler: Delivered %d webhooks, %d failed", sent, failed)
+	}
+	return nil
+}
+
+// deliverWebhook makes one attempt at a delivery and records the outcome.
+func (s *Scheduler) deliverWebhook(ctx context.Context, hook repository.Webhook, d repository.WebhookDelivery) (bool, error) {
+	var status int
+	var sendErr error
+	if hook.Enabled || d.EventType == webhook.Ping {
+		status, sendErr = s.webhooks.Send(ctx, hook, d)
+	} else {
+		sendErr = errors.New("webhook is disabled")
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the lease expires and another run retries without counting this attempt
+		return false, ctx.Err()
+	}
+
+	attempt := repository.RecordWebhookAttemptParams{
+		ID:             d.ID,
+		Status:         webhook.StatusSuccess,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseStatus: sql.NullInt32{Int32: int32(status), Valid: status != 0},
+	}
+	if sendErr != nil {
+		attempt.Error = sql.NullString{String: sendErr.Error(), Valid: true}
+		attempts := int(d.Attempts) + 1
+		if attempts >= s.webhooks.MaxAttempts || !hook.Enabled {
+			attempt.Status = webhook.StatusFailed
+			log.Printf("Scheduler: Webhook delivery %d to %s failed after %d attempts: %v", d.ID, hook.Url, attempts, sendErr)
+		} else {
+			attempt.Status = webhook.StatusPending
+			attempt.NextAttemptAt = time.Now().Add(webhook.Backoff(attempts))
+		}
+	}
+
+	if err := s.queries.RecordWebhookAttempt(ctx, attempt); err != nil {
+		return false, fmt.Errorf("record webhook attempt: %w", err)
+	}
+	return sendErr == nil, nil
+}
@@ -0,0 +1,301 @@
+package storage
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Encrypted files use envelope encryption: every file gets its own random data key,
+// which encrypts the content and is itself stored wrapped by a master key. Rotating the
+// master key only rewrites the small header, not the file.
+//
+// Layout:
+//
+//	magic (8) | master key ID (8) | wrapped data key (60) | nonce prefix (8) | chunks...
+//
+// Content is sealed with AES-256-GCM in 64 KiB chunks so large files can be streamed.
+// Each chunk's nonce is the prefix plus a counter, and the last chunk is marked in the
+// additional data so truncation is detected.
+const (
+	magic       = "AXLNENC1"
+	chunkSize   = 64 << 10
+	keyIDSize   = 8
+	dataKeySize = 32
+	prefixSize  = 8
+	wrappedSize = 12 + dataKeySize + 16 // nonce + key + tag
+	headerSize  = len(magic) + keyIDSize + wrappedSize + prefixSize
+)
+
+var (
+	ErrKeyUnavailable = errors.New("file is encrypted with an unknown master key")
+	ErrNoMasterKey    = errors.New("no master key configured")
+)
+
+// Keyring holds the current master key used for new files and older keys that can
+// still decrypt existing ones.
+type Keyring struct {
+	current []byte
+	keys    map[string][]byte
+}
+
+// keyringFromEnv reads STORAGE_ENCRYPTION_KEY and STORAGE_ENCRYPTION_OLD_KEYS (comma separated).
+// Keys are base64-encoded 32-byte values. Encryption is off when no key is set.
+func keyringFromEnv() (*Keyring, error) {
+	current := strings.TrimSpace(os.Getenv("STORAGE_ENCRYPTION_KEY"))
+	var old []string
+	for _, k := range strings.Split(os.Getenv("STORAGE_ENCRYPTION_OLD_KEYS"), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			old = append(old, k)
+		}
+	}
+	if current == "" && len(old) == 0 {
+		return nil, nil
+	}
+	return NewKeyring(current, old...)
+}
+
+// NewKeyring builds a keyring from base64-encoded master keys. current may be empty,
+// in which case existing files can be read but new files are stored unencrypted.
+func NewKeyring(current string, old ...string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string][]byte)}
+	for i, encoded := range append([]string{current}, old...) {
+		if encoded == "" {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("master key %d must be 32 bytes, base64 encoded", i)
+		}
+		k.keys[string(keyID(key))] = key
+		if i == 0 {
+			k.current = key
+		}
+	}
+	return k, nil
+}
+
+func keyID(key []byte) []byte {
+	sum := sha256.Sum256(key)
+	return sum[:keyIDSize]
+}
+
+func (k *Keyring) enabled() bool {
+	return k != nil && k.current != nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// wrap seals a data key with the current master key.
+func (k *Keyring) wrap(dataKey []byte) ([]byte, error) {
+	if !k.enabled() {
+		return nil, ErrNoMasterKey
+	}
+	gcm, err := newGCM(k.current)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// Bind the wrapped key to the master key ID it claims
+	return gcm.Seal(nonce, nonce, dataKey, keyID(k.current)), nil
+}
+
+func (k *Keyring) unwrap(id, wrapped []byte) ([]byte, error) {
+	if k == nil {
+		return nil, ErrKeyUnavailable
+	}
+	master, ok := k.keys[string(id)]
+	if !ok {
+		return nil, ErrKeyUnavailable
+	}
+	gcm, err := newGCM(master)
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := gcm.NonceSize()
+	dataKey, err := gcm.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], id)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// encrypt copies r to w as an encrypted blob and returns the number of plaintext bytes.
+func (k *Keyring) encrypt(w io.Writer, r io.Reader) (int64, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return 0, err
+	}
+	wrapped, err := k.wrap(dataKey)
+	if err != nil {
+		return 0, err
+	}
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return 0, err
+	}
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, magic...)
+	header = append(header, keyID(k.current)...)
+	header = append(header, wrapped...)
+	header = append(header, prefix...)
+	if _, err := w.Write(header); err != nil {
+		return 0, err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return 0, err
+	}
+
+	br := bufio.NewReaderSize(r, chunkSize)
+	buf := make([]byte, chunkSize)
+	var total int64
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(br, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return total, err
+		}
+		total += int64(n)
+
+		// Only a short read or nothing left to read marks the last chunk
+		last := n < chunkSize
+		if !last {
+			if _, peekErr := br.Peek(1); peekErr == io.EOF {
+				last = true
+			}
+		}
+
+		sealed := gcm.Seal(nil, chunkNonce(prefix, counter), buf[:n], chunkAD(last))
+		if _, err := w.Write(sealed); err != nil {
+			return total, err
+		}
+		if last {
+			return total, nil
+		}
+	}
+}
+
+// decrypter streams the plaintext of an encrypted blob.
+type decrypter struct {
+	src     *bufio.Reader
+	closer  io.Closer
+	gcm     cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte // decrypted bytes not yet returned
+	done    bool
+}
+
+func (k *Keyring) decrypt(f io.ReadCloser, header []byte) (*decrypter, error) {
+	id := header[len(magic) : len(magic)+keyIDSize]
+	wrapped := header[len(magic)+keyIDSize : len(magic)+keyIDSize+wrappedSize]
+	dataKey, err := k.unwrap(id, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &decrypter{
+		src:    bufio.NewReaderSize(f, chunkSize+gcm.Overhead()),
+		closer: f,
+		gcm:    gcm,
+		prefix: header[headerSize-prefixSize:],
+	}, nil
+}
+
+func (d *decrypter) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *decrypter) next() error {
+	sealed := make([]byte, chunkSize+d.gcm.Overhead())
+	n, err := io.ReadFull(d.src, sealed)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return errors.New("encrypted file is truncated")
+		}
+		return err
+	}
+	last := n < len(sealed)
+	if !last {
+		if _, peekErr := d.src.Peek(1); peekErr == io.EOF {
+			last = true
+		}
+	}
+
+	plain, err := d.gcm.Open(sealed[:0], chunkNonce(d.prefix, d.counter), sealed[:n], chunkAD(last))
+	if err != nil {
+		return fmt.Errorf("decrypt chunk %d: %w", d.counter, err)
+	}
+	d.counter++
+	d.buf = plain
+	d.done = last
+	return nil
+}
+
+func (d *decrypter) Close() error {
+	return d.closer.Close()
+}
+
+func chunkNonce(prefix []byte, counter uint32) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[prefixSize:], counter)
+	return nonce
+}
+
+func chunkAD(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// readHeader returns the encryption header of a file, or nil if it is stored in plain text.
+// The reader is left positioned after the header, or rewound for plain files.
+func readHeader(f *os.File) ([]byte, error) {
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	if n == headerSize && string(header[:len(magic)]) == magic {
+		return header, nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newMasterKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func newStore(t *testing.T, dir, current string, old ...string) *Store {
+	t.Helper()
+	keys, err := NewKeyring(current, old...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Store{Dir: dir, keys: keys}
+}
+
+// plaintext is recognisable text spanning several chunks, ending in a partial one.
+func plaintext() []byte {
+	return bytes.Repeat([]byte("insurance policy 4711-AXLE "), 2*chunkSize/27+100)
+}
+
+func readAll(t *testing.T, s *Store, key string) ([]byte, error) {
+	t.Helper()
+	r, err := s.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func save(t *testing.T, s *Store, data []byte) string {
+	t.Helper()
+	key, size, err := s.Save(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if size != int64(len(data)) {
+		t.Fatalf("Save returned size %d, want %d", size, len(data))
+	}
+	return key
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	s := newStore(t, t.TempDir(), newMasterKey(t))
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3 * chunkSize} {
+		data := make([]byte, size)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatal(err)
+		}
+		key := save(t, s, data)
+		got, err := readAll(t, s, key)
+		if err != nil {
+			t.Fatalf("size %d: Open: %v", size, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("size %d: read back %d bytes that differ from what was saved", size, len(got))
+		}
+	}
+}
+
+func TestEncryptedFileHidesPlaintext(t *testing.T) {
+	s := newStore(t, t.TempDir(), newMasterKey(t))
+	data := plaintext()
+	key := save(t, s, data)
+
+	path, err := s.path(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw, []byte(magic)) {
+		t.Fatal("stored file has no encryption header")
+	}
+	if bytes.Contains(raw, []byte("insurance policy")) {
+		t.Fatal("stored file contains the plaintext")
+	}
+}
+
+func TestOpenWithoutKey(t *testing.T) {
+	dir := t.TempDir()
+	key := save(t, newStore(t, dir, newMasterKey(t)), plaintext())
+
+	noKeys := &Store{Dir: dir}
+	if _, err := readAll(t, noKeys, key); !errors.Is(err, ErrKeyUnavailable) {
+		t.Errorf("without a keyring: got %v, want ErrKeyUnavailable", err)
+	}
+
+	wrongKey := newStore(t, dir, newMasterKey(t))
+	if _, err := readAll(t, wrongKey, key); !errors.Is(err, ErrKeyUnavailable) {
+		t.Errorf("with the wrong key: got %v, want ErrKeyUnavailable", err)
+	}
+}
+
+func TestTamperedFileIsRejected(t *testing.T) {
+	data := plaintext()
+	sealedChunk := chunkSize + 16 // chunk plus GCM tag
+
+	tests := []struct {
+		name   string
+		damage func(path string) error
+	}{
+		{"flipped byte", func(path string) error {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			raw[headerSize+sealedChunk+10] ^= 0x01
+			return os.WriteFile(path, raw, 0o640)
+		}},
+		{"truncated mid-chunk", func(path string) error {
+			return os.Truncate(path, int64(headerSize+sealedChunk+100))
+		}},
+		{"last chunk dropped", func(path string) error {
+			return os.Truncate(path, int64(headerSize+2*sealedChunk))
+		}},
+		{"chunks swapped", func(path string) error {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			first := bytes.Clone(raw[headerSize : headerSize+sealedChunk])
+			copy(raw[headerSize:], raw[headerSize+sealedChunk:headerSize+2*sealedChunk])
+			copy(raw[headerSize+sealedChunk:], first)
+			return os.WriteFile(path, raw, 0o640)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStore(t, t.TempDir(), newMasterKey(t))
+			key := save(t, s, data)
+			path, err := s.path(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.damage(path); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := readAll(t, s, key); err == nil {
+				t.Fatal("damaged file was read without an error")
+			}
+		})
+	}
+}
+
+func TestRotateRetiresOldKey(t *testing.T) {
+	dir := t.TempDir()
+	oldKey, newKey := newMasterKey(t), newMasterKey(t)
+	data := plaintext()
+
+	encrypted := save(t, newStore(t, dir, oldKey), data)
+	plain := save(t, &Store{Dir: dir}, data)
+
+	stats, err := newStore(t, dir, newKey, oldKey).Rotate(true)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if stats.Rewrapped != 1 || stats.Encrypted != 1 || stats.Failed != 0 {
+		t.Fatalf("Rotate stats = %+v, want one re-wrapped and one encrypted file", stats)
+	}
+
+	// The old key is gone; both files open with the new one alone
+	s := newStore(t, dir, newKey)
+	for _, key := range []string{encrypted, plain} {
+		got, err := readAll(t, s, key)
+		if err != nil {
+			t.Fatalf("Open %s after rotation: %v", key, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("file %s changed content during rotation", key)
+		}
+	}
+	if _, err := readAll(t, newStore(t, dir, oldKey), encrypted); !errors.Is(err, ErrKeyUnavailable) {
+		t.Errorf("old key still opens a rotated file: %v", err)
+	}
+
+	// A second run finds nothing to do and leaves no temporary files behind
+	stats, err = s.Rotate(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Current != 2 || stats.Rewrapped != 0 || stats.Encrypted != 0 {
+		t.Errorf("second Rotate stats = %+v, want both files current", stats)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*", "*.tmp")); len(tmp) > 0 {
+		t.Errorf("rotation left temporary files: %v", tmp)
+	}
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RotateStats summarises a key rotation.
+type RotateStats struct {
+	Rewrapped int // data keys re-wrapped with the current master key
+	Encrypted int // plain files that were encrypted
+	Current   int // already using the current key
+	Plain     int // plain files left as they were
+	Failed    int
+}
+
+// Rotate re-wraps every file's data key with the current master key, so old master keys
+// can be retired. Only the header of each file changes, but every file is rewritten
+// through a temporary copy so an interrupted rotation never corrupts it. With encryptPlain, files
+// stored before encryption was enabled are encrypted as well.
+func (s *Store) Rotate(encryptPlain bool) (RotateStats, error) {
+	var stats RotateStats
+	if !s.keys.enabled() {
+		return stats, ErrNoMasterKey
+	}
+
+	err := filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+
+		outcome, err := s.rotateFile(path, encryptPlain)
+		if err != nil {
+			log.Printf("Storage: Failed to rotate %s: %v", path, err)
+			stats.Failed++
+			return nil
+		}
+		switch outcome {
+		case rotateRewrapped:
+			stats.Rewrapped++
+		case rotateEncrypted:
+			stats.Encrypted++
+		case rotateCurrent:
+			stats.Current++
+		case rotatePlain:
+			stats.Plain++
+		}
+		return nil
+	})
+	return stats, err
+}
+
+type rotateOutcome int
+
+const (
+	rotateRewrapped rotateOutcome = iota
+	rotateEncrypted
+	rotateCurrent
+	rotatePlain
+)
+
+func (s *Store) rotateFile(path string, encryptPlain bool) (rotateOutcome, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	header, err := readHeader(f)
+	if err != nil {
+		return 0, err
+	}
+
+	if header == nil {
+		if !encryptPlain {
+			return rotatePlain, nil
+		}
+		return rotateEncrypted, replaceFile(path, func(w io.Writer) error {
+			_, err := s.keys.encrypt(w, f)
+			return err
+		})
+	}
+
+	id := header[len(magic) : len(magic)+keyIDSize]
+	if bytes.Equal(id, keyID(s.keys.current)) {
+		return rotateCurrent, nil
+	}
+
+	dataKey, err := s.keys.unwrap(id, header[len(magic)+keyIDSize:len(magic)+keyIDSize+wrappedSize])
+	if err != nil {
+		return 0, err
+	}
+	wrapped, err := s.keys.wrap(dataKey)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only the key ID and wrapped key change; the chunks are copied as they are
+	rewrapped := make([]byte, 0, headerSize)
+	rewrapped = append(rewrapped, magic...)
+	rewrapped = append(rewrapped, keyID(s.keys.current)...)
+	rewrapped = append(rewrapped, wrapped...)
+	rewrapped = append(rewrapped, header[headerSize-prefixSize:]...)
+	return rotateRewrapped, replaceFile(path, func(w io.Writer) error {
+		if _, err := w.Write(rewrapped); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, f)
+		return err
+	})
+}
+
+// replaceFile writes a new version of a file to a temporary file, syncs it and renames
+// it over the original, so a crash part way leaves either the old file or the new one.
+func replaceFile(path string, write func(io.Writer) error) error {
+	tmp := path + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	err = write(out)
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// Make the rename itself durable
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
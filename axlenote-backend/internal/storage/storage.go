@@ -0,0 +1,188 @@
+// Package storage keeps uploaded files on local disk under opaque keys, optionally
+// encrypted at rest.
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+type Store struct {
+	Dir  string
+	keys *Keyring // nil when encryption is not configured
+}
+
+func New() (*Store, error) {
+	dir := os.Getenv("STORAGE_DIR")
+	if dir == "" {
+		dir = "./data/files"
+	}
+	keys, err := keyringFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Dir: dir, keys: keys}, nil
+}
+
+// Encrypted reports whether new files are encrypted at rest.
+func (s *Store) Encrypted() bool {
+	return s.keys.enabled()
+}
+
+// Save writes r to a new file and returns its key and plaintext size.
+func (s *Store) Save(r io.Reader) (string, int64, error) {
+	key, err := newKey()
+	if err != nil {
+		return "", 0, err
+	}
+	path, err := s.path(key)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", 0, fmt.Errorf("create storage dir: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return "", 0, err
+	}
+	size, err := s.write(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+	return key, size, nil
+}
+
+// Open returns the contents stored under key, decrypting them if needed.
+func (s *Store) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.open(path)
+}
+
+// SaveVariant stores content derived from a file, such as a thumbnail, next to the original.
+func (s *Store) SaveVariant(key, variant string, data []byte) error {
+	path, err := s.variantPath(key, variant)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so readers never see a partial variant
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	_, err = s.write(f, bytes.NewReader(data))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// OpenVariant returns a variant previously stored with SaveVariant.
+func (s *Store) OpenVariant(key, variant string) (io.ReadCloser, error) {
+	path, err := s.variantPath(key, variant)
+	if err != nil {
+		return nil, err
+	}
+	return s.open(path)
+}
+
+func (s *Store) write(w io.Writer, r io.Reader) (int64, error) {
+	if s.keys.enabled() {
+		return s.keys.encrypt(w, r)
+	}
+	return io.Copy(w, r)
+}
+
+// open reads encrypted and plain files alike, so files stored before encryption
+// was turned on stay readable.
+func (s *Store) open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	header, err := readHeader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if header == nil {
+		return f, nil
+	}
+	d, err := s.keys.decrypt(f, header)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// Delete removes the file stored under key along with its variants. Missing files are not an error.
+func (s *Store) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	variants, _ := filepath.Glob(path + ".*")
+	for _, p := range append(variants, path) {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// path shards keys into subdirectories so no single directory grows too large.
+func (s *Store) path(key string) (string, error) {
+	if len(key) != 32 {
+		return "", ErrInvalidKey
+	}
+	if _, err := hex.DecodeString(key); err != nil {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.Dir, key[:2], key), nil
+}
+
+func (s *Store) variantPath(key, variant string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if variant == "" || strings.ContainsAny(variant, `/\.`) {
+		return "", fmt.Errorf("invalid variant %q", variant)
+	}
+	return path + "." + variant, nil
+}
+
+func newKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
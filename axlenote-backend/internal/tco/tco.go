@@ -0,0 +1,367 @@
+// Package tco works out the total cost of ownership of a vehicle over a date range: fuel,
+// maintenance, expenses, fixed costs and depreciation, per distance driven and per month.
+package tco
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/depreciation"
+	"github.com/axlenote/axlenote-backend/internal/expense"
+	"github.com/axlenote/axlenote-backend/internal/loan"
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// Fixed cost categories and frequencies.
+const (
+	CategoryInsurance    = "insurance"
+	CategoryTax          = "tax"
+	CategoryFinancing    = "financing"
+	CategoryDepreciation = "depreciation"
+	CategoryOther        = "other"
+
+	FrequencyOnce    = "once"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+var (
+	Categories  = []string{CategoryInsurance, CategoryTax, CategoryFinancing, CategoryDepreciation, CategoryOther}
+	Frequencies = []string{FrequencyOnce, FrequencyMonthly, FrequencyYearly}
+)
+
+// daysPerMonth is the average month length used for per-month figures.
+const daysPerMonth = 365.25 / 12
+
+// CategoryExpenses is where expenses that are not insurance, tax or loan repayments
+// (tolls, parking, washes, fines, ...) are counted.
+const CategoryExpenses = "expenses"
+
+// ExpenseCost returns the cost category an expense adds to: insurance, tax and loan
+// expenses count with the fixed costs of the same kind, the rest as expenses.
+func ExpenseCost(category string) string {
+	switch category {
+	case expense.CategoryInsurance:
+		return CategoryInsurance
+	case expense.CategoryTax:
+		return CategoryTax
+	case expense.CategoryLoan:
+		return CategoryFinancing
+	}
+	return CategoryExpenses
+}
+
+// Costs breaks down spend by kind. Fixed is insurance, tax, financing and other
+// together, whether recorded as fixed costs or expenses; for a vehicle with tracked
+// loans, financing is the interest paid on them instead. Depreciation is the value the
+// vehicle lost, estimated from its purchase price or valuations, or else depreciation
+// fixed costs. Total adds fuel, maintenance, other expenses and depreciation to it.
+type Costs struct {
+	Fuel         float64 `json:"fuel"`
+	Maintenance  float64 `json:"maintenance"`
+	Expenses     float64 `json:"expenses"` // tolls, parking, washes, fines, ...
+	Insurance    float64 `json:"insurance"`
+	Tax          float64 `json:"tax"`
+	Financing    float64 `json:"financing"`
+	Other        float64 `json:"other"`
+	Fixed        float64 `json:"fixed"`
+	Depreciation float64 `json:"depreciation"`
+	Total        float64 `json:"total"`
+}
+
+func (c Costs) scale(f float64) Costs {
+	return Costs{
+		Fuel:         round2(c.Fuel * f),
+		Maintenance:  round2(c.Maintenance * f),
+		Expenses:     round2(c.Expenses * f),
+		Insurance:    round2(c.Insurance * f),
+		Tax:          round2(c.Tax * f),
+		Financing:    round2(c.Financing * f),
+		Other:        round2(c.Other * f),
+		Fixed:        round2(c.Fixed * f),
+		Depreciation: round2(c.Depreciation * f),
+		Total:        round2(c.Total * f),
+	}
+}
+
+// Report is the cost of ownership of one vehicle over a date range.
+type Report struct {
+	VehicleID    int32     `json:"vehicle_id"`
+	VehicleName  string    `json:"vehicle_name"`
+	From         time.Time `json:"-"`
+	To           time.Time `json:"-"`
+	FromDate     string    `json:"from"` // YYYY-MM-DD
+	ToDate       string    `json:"to"`
+	Days         int       `json:"days"`
+	Months       float64   `json:"months"`
+	Distance     int32     `json:"distance"`
+	DistanceUnit string    `json:"distance_unit"`
+	Currency     string    `json:"currency"`
+	Costs        Costs     `json:"costs"`
+	PerDistance  *Costs    `json:"per_distance"` // null when nothing was driven
+	PerMonth     Costs     `json:"per_month"`
+}
+
+// Accrued returns the part of a fixed cost that falls between from and to, inclusive.
+// Recurring costs accrue evenly per day from their start date until their end date, if
+// any; one-off costs count in full on their start date.
+func Accrued(fc repository.FixedCost, from, to time.Time) float64 {
+	amount, _ := strconv.ParseFloat(fc.Amount, 64)
+	if fc.Frequency == FrequencyOnce {
+		if fc.StartDate.Before(from) || fc.StartDate.After(to) {
+			return 0
+		}
+		return amount
+	}
+
+	start, end := fc.StartDate, to
+	if start.Before(from) {
+		start = from
+	}
+	if fc.EndDate.Valid && fc.EndDate.Time.Before(end) {
+		end = fc.EndDate.Time
+	}
+	days := end.Sub(start).Hours()/24 + 1
+	if days <= 0 {
+		return 0
+	}
+
+	perDay := amount / 365
+	if fc.Frequency == FrequencyMonthly {
+		perDay = amount * 12 / 365
+	}
+	return perDay * days
+}
+
+// ForVehicle builds the report of a vehicle between from and to, inclusive.
+func ForVehicle(ctx context.Context, queries *repository.Queries, vehicle repository.Vehicle, from, to time.Time) (Report, error) {
+	report := Report{
+		VehicleID:    vehicle.ID,
+		VehicleName:  vehicle.Name,
+		From:         from,
+		To:           to,
+		FromDate:     from.Format("2006-01-02"),
+		ToDate:       to.Format("2006-01-02"),
+		Days:         int(to.Sub(from).Hours()/24) + 1,
+		DistanceUnit: distanceUnit(vehicle),
+		Currency:     Currency(),
+	}
+	report.Months = round2(float64(report.Days) / daysPerMonth)
+
+	spend, err := queries.GetVehicleSpend(ctx, repository.GetVehicleSpendParams{
+		VehicleID: vehicle.ID,
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return report, fmt.Errorf("spend: %w", err)
+	}
+	costs := Costs{Fuel: spend.FuelCost, Maintenance: spend.ServiceCost}
+
+	// Only the interest on a loan is a cost; the principal pays for the vehicle. Once a
+	// vehicle's loans are tracked, financing is their amortized interest alone: loan
+	// expenses and financing fixed costs are EMIs that include principal.
+	loans, err := queries.ListLoansByVehicle(ctx, vehicle.ID)
+	if err != nil {
+		return report, fmt.Errorf("loans: %w", err)
+	}
+	tracked := len(loans) > 0
+	if tracked {
+		payments, err := queries.ListLoanPaymentsByVehicle(ctx, vehicle.ID)
+		if err != nil {
+			return report, fmt.Errorf("loan payments: %w", err)
+		}
+		byLoan := make(map[int32][]repository.LoanPayment)
+		for _, p := range payments {
+			byLoan[p.LoanID] = append(byLoan[p.LoanID], p)
+		}
+		for _, l := range loans {
+			costs.Financing += loan.ForLoan(l, byLoan[l.ID], to).InterestBetween(from, to)
+		}
+	}
+
+	expenses, err := queries.ListExpenseSpend(ctx, repository.ListExpenseSpendParams{
+		VehicleID: sql.NullInt32{Int32: vehicle.ID, Valid: true},
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return report, fmt.Errorf("expenses: %w", err)
+	}
+	for _, e := range expenses {
+		switch ExpenseCost(e.Category) {
+		case CategoryInsurance:
+			costs.Insurance += e.Amount
+		case CategoryTax:
+			costs.Tax += e.Amount
+		case CategoryFinancing:
+			if !tracked {
+				costs.Financing += e.Amount
+			}
+		default:
+			costs.Expenses += e.Amount
+		}
+	}
+
+	// Depreciation is estimated when the purchase or valuations are known, and taken from
+	// depreciation fixed costs otherwise
+	valuations, err := queries.ListValuationsByVehicle(ctx, vehicle.ID)
+	if err != nil {
+		return report, fmt.Errorf("valuations: %w", err)
+	}
+	estimate, estimated := depreciation.ForVehicle(vehicle, valuations)
+	if estimated {
+		costs.Depreciation = estimate.Between(from, to)
+	}
+
+	fixed, err := queries.ListFixedCostsByVehicle(ctx, vehicle.ID)
+	if err != nil {
+		return report, fmt.Errorf("fixed costs: %w", err)
+	}
+	for _, fc := range fixed {
+		amount := Accrued(fc, from, to)
+		switch fc.Category {
+		case CategoryInsurance:
+			costs.Insurance += amount
+		case CategoryTax:
+			costs.Tax += amount
+		case CategoryFinancing:
+			if !tracked {
+				costs.Financing += amount
+			}
+		case CategoryDepreciation:
+			if !estimated {
+				costs.Depreciation += amount
+			}
+		default:
+			costs.Other += amount
+		}
+	}
+	costs.Fixed = costs.Insurance + costs.Tax + costs.Financing + costs.Other
+	costs.Total = costs.Fuel + costs.Maintenance + costs.Expenses + costs.Fixed + costs.Depreciation
+
+	odometer, err := queries.GetOdometerRange(ctx, repository.GetOdometerRangeParams{
+		VehicleID: vehicle.ID,
+		DateFrom:  from,
+		DateTo:    to,
+	})
+	if err != nil {
+		return report, fmt.Errorf("odometer: %w", err)
+	}
+	report.Distance = max(odometer.EndOdometer-odometer.StartOdometer, 0)
+
+	report.Costs = costs.scale(1)
+	report.PerMonth = costs.scale(1 / (float64(report.Days) / daysPerMonth))
+	if report.Distance > 0 {
+		perDistance := costs.scale(1 / float64(report.Distance))
+		report.PerDistance = &perDistance
+	}
+	return report, nil
+}
+
+// Line is one row of the printed report.
+type Line struct {
+	Label       string
+	Total       float64
+	PerMonth    float64
+	PerDistance *float64
+	Summary     bool // subtotal or total row
+}
+
+// Lines lists the report's costs in print order, skipping empty expense and fixed cost
+// categories.
+func (r Report) Lines() []Line {
+	pick := func(c *Costs, f func(Costs) float64) *float64 {
+		if c == nil {
+			return nil
+		}
+		v := f(*c)
+		return &v
+	}
+	rows := []struct {
+		label    string
+		value    func(Costs) float64
+		optional bool
+		summary  bool
+	}{
+		{"Fuel", func(c Costs) float64 { return c.Fuel }, false, false},
+		{"Maintenance", func(c Costs) float64 { return c.Maintenance }, false, false},
+		{"Tolls, parking and other expenses", func(c Costs) float64 { return c.Expenses }, true, false},
+		{"Insurance", func(c Costs) float64 { return c.Insurance }, true, false},
+		{"Tax", func(c Costs) float64 { return c.Tax }, true, false},
+		{"Financing", func(c Costs) float64 { return c.Financing }, true, false},
+		{"Other fixed costs", func(c Costs) float64 { return c.Other }, true, false},
+		{"Fixed costs", func(c Costs) float64 { return c.Fixed }, false, true},
+		{"Depreciation", func(c Costs) float64 { return c.Depreciation }, false, false},
+		{"Total cost of ownership", func(c Costs) float64 { return c.Total }, false, true},
+	}
+
+	var lines []Line
+	for _, row := range rows {
+		if row.optional && row.value(r.Costs) == 0 {
+			continue
+		}
+		lines = append(lines, Line{
+			Label:       row.label,
+			Total:       row.value(r.Costs),
+			PerMonth:    row.value(r.PerMonth),
+			PerDistance: pick(r.PerDistance, row.value),
+			Summary:     row.summary,
+		})
+	}
+	return lines
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// distanceUnit labels the unit of a vehicle's odometer, METRICS_UNIT unless the vehicle sets one.
+func distanceUnit(v repository.Vehicle) string {
+	unit := v.DistanceUnit.String
+	if unit == "" {
+		unit = os.Getenv("METRICS_UNIT")
+	}
+	if unit == "miles" {
+		return "mi"
+	}
+	return "km"
+}
+
+// Currency is the currency symbol from APP_CURRENCY.
+func Currency() string {
+	if c := os.Getenv("APP_CURRENCY"); c != "" {
+		return c
+	}
+	return "₹"
+}
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var htmlTemplate = template.Must(template.New("report.html.tmpl").Funcs(template.FuncMap{
+	"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"date":  func(t time.Time) string { return t.Format("2006-01-02") },
+	"deref": func(v *float64) float64 { return *v },
+}).ParseFS(templateFS, "templates/report.html.tmpl"))
+
+// RenderHTML renders reports as a printable page, one section per vehicle.
+func RenderHTML(reports []Report, generatedAt time.Time) (string, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		Reports     []Report
+		GeneratedAt time.Time
+	}{reports, generatedAt})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
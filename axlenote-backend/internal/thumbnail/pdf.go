@@ -0,0 +1,457 @@
+package thumbnail
+
+import (
+	"bytes"
+	"cmp"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// renderTimeout bounds how long pdftoppm may spend on the first page.
+	renderTimeout = 30 * time.Second
+	// maxPDFDepth bounds nesting and reference chains, so a malformed file cannot recurse
+	// or loop without end.
+	maxPDFDepth = 32
+)
+
+// pdfPreview shows the first page of a PDF. When pdftoppm (from poppler-utils) is
+// installed the page is rendered. Otherwise the preview is the largest JPEG image placed
+// on page 1, which for scans is the page itself, and a PDF without one gets a generic PDF
+// icon.
+func pdfPreview(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return nil, errors.New("not a PDF file")
+	}
+	if img, err := renderFirstPage(data); err == nil {
+		return img, nil
+	}
+	if img := firstPageImage(data); img != nil {
+		return img, nil
+	}
+	return placeholder("PDF"), nil
+}
+
+// renderFirstPage rasterises page 1 with pdftoppm, scaled to MaxSize on its longest edge.
+func renderFirstPage(data []byte) (image.Image, error) {
+	bin, err := exec.LookPath("pdftoppm")
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "axlenote-pdf-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in, out := filepath.Join(dir, "in.pdf"), filepath.Join(dir, "page")
+	if err := os.WriteFile(in, data, 0o600); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), renderTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, bin, "-f", "1", "-l", "1", "-singlefile", "-png",
+		"-scale-to", strconv.Itoa(MaxSize), in, out)
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pdftoppm: %w: %s", err, bytes.TrimSpace(msg))
+	}
+
+	f, err := os.Open(out + ".png")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decode(f)
+}
+
+// firstPageImage decodes the largest JPEG image among page 1's resources, or returns nil.
+func firstPageImage(data []byte) image.Image {
+	f := parsePDF(data)
+	found := f.images(f.firstPageResources(), 0)
+	slices.SortStableFunc(found, func(a, b pdfImage) int { return cmp.Compare(b.area, a.area) })
+	for _, candidate := range found {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(candidate.data))
+		if err != nil || checkSize(cfg) != nil {
+			continue
+		}
+		if img, err := jpeg.Decode(bytes.NewReader(candidate.data)); err == nil {
+			return img
+		}
+	}
+	return nil
+}
+
+// The reader below understands just enough of the PDF format to find page 1 and its
+// images. Objects are located by scanning for "N G obj" rather than through the
+// cross-reference table, which also copes with files whose offsets are wrong.
+
+type (
+	pdfDict    map[string]any
+	pdfArray   []any
+	pdfName    string
+	pdfRef     int
+	pdfKeyword string
+)
+
+type pdfObject struct {
+	value  any
+	stream []byte
+}
+
+type pdfFile struct {
+	data []byte
+	objs map[int]pdfObject
+}
+
+type pdfImage struct {
+	data []byte
+	area int
+}
+
+var objHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+
+// parsePDF indexes the objects in a file. Later definitions of an object replace earlier
+// ones, as they do in incrementally updated files.
+func parsePDF(data []byte) *pdfFile {
+	f := &pdfFile{data: data, objs: map[int]pdfObject{}}
+	next := 0
+	for _, m := range objHeader.FindAllSubmatchIndex(data, -1) {
+		// Matches inside the previous object, such as in binary stream data, are not objects
+		if m[0] < next {
+			continue
+		}
+		num, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		p := &pdfParser{data: data, pos: m[1]}
+		obj := pdfObject{value: p.value(0)}
+		p.skip()
+		if start := p.pos; p.token() == "stream" {
+			dict, _ := obj.value.(pdfDict)
+			obj.stream = p.stream(dict)
+		} else {
+			p.pos = start
+		}
+		f.objs[num] = obj
+		next = p.pos
+	}
+	f.unpackObjectStreams()
+	return f
+}
+
+// unpackObjectStreams adds the objects stored in compressed object streams, which PDF 1.5
+// writers use for most objects that are not streams themselves, the page tree included.
+// Objects already found in the file are kept.
+func (f *pdfFile) unpackObjectStreams() {
+	budget := maxInput
+	for _, num := range slices.Sorted(maps.Keys(f.objs)) {
+		obj := f.objs[num]
+		dict, _ := obj.value.(pdfDict)
+		if dict["/Type"] != pdfName("/ObjStm") || !hasFilter(f.resolve(dict["/Filter"]), "/FlateDecode") {
+			continue
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(obj.stream))
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(zr, int64(budget)))
+		if err != nil {
+			continue
+		}
+		budget -= len(body)
+
+		// The stream starts with pairs of object number and offset, then the objects
+		first, _ := dict["/First"].(int)
+		if first <= 0 || first > len(body) {
+			continue
+		}
+		header := &pdfParser{data: body[:first]}
+		objects := &pdfParser{data: body[first:]}
+		for {
+			num, ok := header.value(0).(int)
+			offset, ok2 := header.value(0).(int)
+			if !ok || !ok2 {
+				break
+			}
+			if _, ok := f.objs[num]; ok || offset < 0 || offset >= len(objects.data) {
+				continue
+			}
+			objects.pos = offset
+			f.objs[num] = pdfObject{value: objects.value(0)}
+		}
+	}
+}
+
+// resolve follows references until it reaches a direct value.
+func (f *pdfFile) resolve(v any) any {
+	for range maxPDFDepth {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = f.objs[int(ref)].value
+	}
+	return nil
+}
+
+func (f *pdfFile) dict(v any) pdfDict {
+	d, _ := f.resolve(v).(pdfDict)
+	return d
+}
+
+// root returns the document catalog named by the last trailer, which in an incrementally
+// updated file is the current one. Files with a cross-reference stream keep the trailer
+// entries in that stream's dictionary, which is not compressed.
+func (f *pdfFile) root() pdfDict {
+	i := bytes.LastIndex(f.data, []byte("/Root"))
+	if i < 0 {
+		return nil
+	}
+	p := &pdfParser{data: f.data, pos: i + len("/Root")}
+	return f.dict(p.value(0))
+}
+
+// firstPageResources walks the page tree down the first kid of each node to page 1 and
+// returns the resources it declares or inherits.
+func (f *pdfFile) firstPageResources() pdfDict {
+	node := f.dict(f.root()["/Pages"])
+	var resources any
+	for range maxPDFDepth {
+		if node == nil {
+			return nil
+		}
+		if r, ok := node["/Resources"]; ok {
+			resources = r
+		}
+		kids, _ := f.resolve(node["/Kids"]).(pdfArray)
+		if node["/Type"] == pdfName("/Page") || len(kids) == 0 {
+			return f.dict(resources)
+		}
+		node = f.dict(kids[0])
+	}
+	return nil
+}
+
+// images lists the JPEG image XObjects in resources, looking into form XObjects too,
+// since some scanners wrap the page image in one.
+func (f *pdfFile) images(resources pdfDict, depth int) []pdfImage {
+	xobjects := f.dict(resources["/XObject"])
+	var found []pdfImage
+	for _, name := range slices.Sorted(maps.Keys(xobjects)) {
+		ref, ok := xobjects[name].(pdfRef)
+		if !ok {
+			continue
+		}
+		obj := f.objs[int(ref)]
+		dict, _ := obj.value.(pdfDict)
+		switch dict["/Subtype"] {
+		case pdfName("/Image"):
+			// Only images with no other filter before the JPEG encoding can be decoded as is
+			if hasFilter(f.resolve(dict["/Filter"]), "/DCTDecode") {
+				w, _ := f.resolve(dict["/Width"]).(int)
+				h, _ := f.resolve(dict["/Height"]).(int)
+				found = append(found, pdfImage{data: obj.stream, area: w * h})
+			}
+		case pdfName("/Form"):
+			if depth < 2 {
+				found = append(found, f.images(f.dict(dict["/Resources"]), depth+1)...)
+			}
+		}
+	}
+	return found
+}
+
+// hasFilter reports whether a stream's /Filter is exactly the named one.
+func hasFilter(filter any, name pdfName) bool {
+	if a, ok := filter.(pdfArray); ok && len(a) == 1 {
+		filter = a[0]
+	}
+	return filter == name
+}
+
+type pdfParser struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// skip moves past whitespace and comments.
+func (p *pdfParser) skip() {
+	for p.pos < len(p.data) {
+		switch c := p.data[p.pos]; {
+		case isPDFSpace(c):
+			p.pos++
+		case c == '%':
+			for p.pos < len(p.data) && p.data[p.pos] != '\r' && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// token reads a run of regular characters, such as a number or keyword.
+func (p *pdfParser) token() string {
+	start := p.pos
+	for p.pos < len(p.data) && !isPDFSpace(p.data[p.pos]) && !isPDFDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+func (p *pdfParser) peek(offset int) byte {
+	if p.pos+offset < len(p.data) {
+		return p.data[p.pos+offset]
+	}
+	return 0
+}
+
+// value parses the object at the current position. Strings are skipped rather than
+// decoded, as nothing here needs their contents. Anything else it does not understand is
+// returned as nil after moving past at least one byte, so a damaged file cannot stall it.
+func (p *pdfParser) value(depth int) any {
+	p.skip()
+	if p.pos >= len(p.data) || depth > maxPDFDepth {
+		return nil
+	}
+	switch c := p.data[p.pos]; {
+	case c == '<' && p.peek(1) == '<':
+		p.pos += 2
+		dict := pdfDict{}
+		for {
+			p.skip()
+			if p.pos >= len(p.data) {
+				return dict
+			}
+			if p.data[p.pos] == '>' {
+				p.pos = min(p.pos+2, len(p.data))
+				return dict
+			}
+			key, ok := p.value(depth + 1).(pdfName)
+			if !ok {
+				return dict
+			}
+			dict[string(key)] = p.value(depth + 1)
+		}
+	case c == '[':
+		p.pos++
+		var array pdfArray
+		for {
+			p.skip()
+			if p.pos >= len(p.data) {
+				return array
+			}
+			if p.data[p.pos] == ']' {
+				p.pos++
+				return array
+			}
+			array = append(array, p.value(depth+1))
+		}
+	case c == '/':
+		p.pos++
+		return pdfName("/" + p.token())
+	case c == '(':
+		open := 0
+		for ; p.pos < len(p.data); p.pos++ {
+			switch p.data[p.pos] {
+			case '\\':
+				p.pos++
+			case '(':
+				open++
+			case ')':
+				if open--; open == 0 {
+					p.pos++
+					return ""
+				}
+			}
+		}
+		return ""
+	case c == '<':
+		if end := bytes.IndexByte(p.data[p.pos:], '>'); end >= 0 {
+			p.pos += end + 1
+		} else {
+			p.pos = len(p.data)
+		}
+		return ""
+	case c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9':
+		tok := p.token()
+		n, err := strconv.Atoi(tok)
+		if err != nil {
+			f, _ := strconv.ParseFloat(tok, 64)
+			return f
+		}
+		// "N G R" refers to object N
+		save := p.pos
+		p.skip()
+		if gen, err := strconv.Atoi(p.token()); err == nil && gen >= 0 {
+			p.skip()
+			if p.token() == "R" {
+				return pdfRef(n)
+			}
+		}
+		p.pos = save
+		return n
+	default:
+		if tok := p.token(); tok != "" {
+			return pdfKeyword(tok)
+		}
+		p.pos++
+		return nil
+	}
+}
+
+// stream returns the data after a "stream" keyword that has just been read. /Length is
+// used when it is a direct number that ends at "endstream"; otherwise the data runs up to
+// the next "endstream".
+func (p *pdfParser) stream(dict pdfDict) []byte {
+	// The keyword is followed by CRLF or LF before the data begins
+	start := p.pos
+	if bytes.HasPrefix(p.data[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(p.data) && (p.data[start] == '\n' || p.data[start] == '\r') {
+		start++
+	}
+
+	if n, ok := dict["/Length"].(int); ok && n >= 0 && n <= len(p.data)-start {
+		rest := bytes.TrimLeft(p.data[start+n:], " \t\r\n")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			p.pos = start + n
+			return p.data[start : start+n]
+		}
+	}
+	end := bytes.Index(p.data[start:], []byte("endstream"))
+	if end < 0 {
+		p.pos = len(p.data)
+		return p.data[start:]
+	}
+	p.pos = start + end
+	data := bytes.TrimSuffix(p.data[start:p.pos], []byte("\n"))
+	return bytes.TrimSuffix(data, []byte("\r"))
+}
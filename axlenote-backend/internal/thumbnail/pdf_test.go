@@ -0,0 +1,143 @@
+package thumbnail
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"strings"
+	"testing"
+)
+
+// jpegOf encodes a blank image of the given size.
+func jpegOf(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pdfBuilder writes numbered objects one after another, followed by a trailer naming
+// object 1 as the catalog. No cross-reference table is written, since it is not read.
+type pdfBuilder struct {
+	buf bytes.Buffer
+}
+
+func newPDF() *pdfBuilder {
+	b := &pdfBuilder{}
+	b.buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	return b
+}
+
+func (b *pdfBuilder) object(num int, body string) {
+	fmt.Fprintf(&b.buf, "%d 0 obj\n%s\nendobj\n", num, body)
+}
+
+func (b *pdfBuilder) stream(num int, dict string, data []byte) {
+	fmt.Fprintf(&b.buf, "%d 0 obj\n<< %s /Length %d >>\nstream\r\n", num, dict, len(data))
+	b.buf.Write(data)
+	b.buf.WriteString("\r\nendstream\nendobj\n")
+}
+
+func (b *pdfBuilder) image(num, w, h int, data []byte) {
+	b.stream(num, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /DCTDecode", w, h), data)
+}
+
+// objectStream stores objects in a compressed object stream.
+func (b *pdfBuilder) objectStream(t *testing.T, num int, objects map[int]string) {
+	t.Helper()
+	var header, body strings.Builder
+	for n := range 100 {
+		if obj, ok := objects[n]; ok {
+			fmt.Fprintf(&header, "%d %d ", n, body.Len())
+			body.WriteString(obj + "\n")
+		}
+	}
+	var data bytes.Buffer
+	zw := zlib.NewWriter(&data)
+	if _, err := zw.Write([]byte(header.String() + body.String())); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	b.stream(num, fmt.Sprintf("/Type /ObjStm /N %d /First %d /Filter /FlateDecode", len(objects), header.Len()), data.Bytes())
+}
+
+func (b *pdfBuilder) bytes() []byte {
+	b.buf.WriteString("trailer\n<< /Size 20 /Root 1 0 R >>\nstartxref\n0\n%%EOF\n")
+	return b.buf.Bytes()
+}
+
+func TestFirstPageImage(t *testing.T) {
+	b := newPDF()
+	// Page 2's image is stored first, so scanning the file in order would find it
+	b.image(9, 40, 10, jpegOf(t, 40, 10))
+	b.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	b.object(2, "<< /Type /Pages /Kids [3 0 R 6 0 R] /Count 2 >>")
+	b.object(3, "<< /Type /Pages /Parent 2 0 R /Kids [4 0 R] /Count 1 >>")
+	b.object(4, "<< /Type /Page /Parent 3 0 R /MediaBox [0 0 595.28 841.89] /Resources 5 0 R /Contents 12 0 R >>")
+	b.object(5, "<< /XObject << /Logo 10 0 R /Scan 11 0 R >> /Font << /F1 << /Type /Font /BaseFont (Helvetica (bold\\))) >> >> >>")
+	b.object(6, "<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im1 9 0 R >> >> >>")
+	b.image(10, 8, 8, jpegOf(t, 8, 8))
+	b.image(11, 30, 50, jpegOf(t, 30, 50))
+	b.stream(12, "", []byte("q 595 0 0 842 0 0 cm /Scan Do Q 10 0 obj"))
+
+	img := firstPageImage(b.bytes())
+	if img == nil {
+		t.Fatal("no image found on page 1")
+	}
+	if got := img.Bounds().Size(); got != image.Pt(30, 50) {
+		t.Errorf("image is %v, want the 30x50 scan from page 1", got)
+	}
+}
+
+func TestFirstPageImageInObjectStream(t *testing.T) {
+	b := newPDF()
+	b.image(7, 40, 10, jpegOf(t, 40, 10))
+	b.image(8, 24, 36, jpegOf(t, 24, 36))
+	// Page 1 inherits its resources from the page tree
+	b.objectStream(t, 20, map[int]string{
+		1: "<< /Type /Catalog /Pages 2 0 R >>",
+		2: "<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /XObject << /Page 5 0 R >> >> >>",
+		3: "<< /Type /Page /Parent 2 0 R >>",
+		4: "<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im 7 0 R >> >> >>",
+		5: "<< /Type /XObject /Subtype /Form /BBox [0 0 1 1] /Resources << /XObject << /Im 8 0 R >> >> /Length 0 >>",
+	})
+
+	img := firstPageImage(b.bytes())
+	if img == nil {
+		t.Fatal("no image found on page 1")
+	}
+	if got := img.Bounds().Size(); got != image.Pt(24, 36) {
+		t.Errorf("image is %v, want the 24x36 image from page 1", got)
+	}
+}
+
+func TestFirstPageImageWithoutJPEG(t *testing.T) {
+	b := newPDF()
+	b.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	b.object(2, "<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>")
+	b.object(3, "<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im 5 0 R >> >> >>")
+	b.object(4, "<< /Type /Page /Parent 2 0 R /Resources << /XObject << /Im 6 0 R >> >> >>")
+	b.stream(5, "/Type /XObject /Subtype /Image /Width 1 /Height 1 /Filter /FlateDecode", []byte{0x78, 0x9c})
+	b.image(6, 40, 10, jpegOf(t, 40, 10))
+
+	if img := firstPageImage(b.bytes()); img != nil {
+		t.Errorf("found a %v image, want none since page 1 has no JPEG", img.Bounds().Size())
+	}
+}
+
+func TestFirstPageImageDamaged(t *testing.T) {
+	for _, data := range []string{
+		"%PDF-1.4\n1 0 obj << /Pages 1 0 R >> endobj trailer << /Root 1 0 R >>",
+		"%PDF-1.4\n1 0 obj << /Pages [[[[[[ (unterminated",
+		"%PDF-1.4\n1 0 obj << /Type /ObjStm /Filter /FlateDecode /First 999 >> stream\nxx",
+		"%PDF-1.4\ntrailer << /Root 1 0 R",
+	} {
+		if img := firstPageImage([]byte(data)); img != nil {
+			t.Errorf("found an image in %q", data)
+		}
+	}
+}
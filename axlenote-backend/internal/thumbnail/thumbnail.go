@@ -0,0 +1,163 @@
+// Package thumbnail renders small JPEG previews of uploaded images and of the first page
+// of PDFs.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxSize is the longest edge of a thumbnail, in pixels.
+	MaxSize = 320
+	quality = 80
+	// maxInput caps how much of a file is read, so a huge upload cannot exhaust memory.
+	maxInput = 64 << 20
+	// maxPixels caps the dimensions of an image that is decoded, since a small compressed
+	// file can expand to gigabytes of pixels.
+	maxPixels = 50_000_000
+)
+
+// Variant is the storage variant thumbnails are saved under, next to the original file.
+const Variant = "thumb"
+
+// Thumbnail states recorded on document_files.
+const (
+	StatusPending     = "pending"
+	StatusReady       = "ready"
+	StatusFailed      = "failed"
+	StatusUnsupported = "unsupported"
+)
+
+var (
+	// ErrUnsupported is returned for content types that have no preview.
+	ErrUnsupported = errors.New("unsupported content type")
+	// ErrTooLarge is returned for images with more than maxPixels pixels.
+	ErrTooLarge = errors.New("image too large")
+)
+
+// Supported reports whether a thumbnail can be generated for the content type.
+func Supported(contentType string) bool {
+	switch mediaType(contentType) {
+	case "image/jpeg", "image/png", "image/gif", "image/webp", "application/pdf":
+		return true
+	}
+	return false
+}
+
+// Generate reads a file and returns a JPEG thumbnail no larger than MaxSize on either edge.
+func Generate(r io.Reader, contentType string) ([]byte, error) {
+	var src image.Image
+	var err error
+
+	switch mediaType(contentType) {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		src, err = decode(io.LimitReader(r, maxInput))
+	case "application/pdf":
+		src, err = pdfPreview(io.LimitReader(r, maxInput))
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", contentType, err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scale(src), &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decode reads an image's dimensions from its header and decodes it only when they are
+// within maxPixels.
+func decode(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSize(cfg); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+func checkSize(cfg image.Config) error {
+	if int64(cfg.Width)*int64(cfg.Height) > maxPixels {
+		return fmt.Errorf("%w: %dx%d pixels", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+	return nil
+}
+
+// scale shrinks an image to fit within MaxSize, flattening transparency onto white.
+func scale(src image.Image) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > MaxSize || h > MaxSize {
+		if w >= h {
+			h = max(1, h*MaxSize/w)
+			w = MaxSize
+		} else {
+			w = max(1, w*MaxSize/h)
+			h = MaxSize
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+	return dst
+}
+
+// placeholder draws a blank page with a folded corner and a short label.
+func placeholder(label string) image.Image {
+	const w, h, fold = MaxSize * 3 / 4, MaxSize, 48
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{0xf3, 0xf4, 0xf6, 0xff}}, image.Point{}, draw.Src)
+
+	page := image.Rect(16, 16, w-16, h-16)
+	border := &image.Uniform{color.RGBA{0x9c, 0xa3, 0xaf, 0xff}}
+	draw.Draw(img, page, border, image.Point{}, draw.Src)
+	draw.Draw(img, page.Inset(2), image.White, image.Point{}, draw.Src)
+	// Folded corner
+	for y := 0; y < fold; y++ {
+		for x := page.Max.X - fold + y; x < page.Max.X; x++ {
+			img.Set(x, page.Min.Y+y, color.RGBA{0xf3, 0xf4, 0xf6, 0xff})
+		}
+		img.Set(page.Max.X-fold+y, page.Min.Y+y, border.C)
+	}
+
+	d := font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{color.RGBA{0xdc, 0x26, 0x26, 0xff}},
+		Face: basicfont.Face7x13,
+	}
+	width := d.MeasureString(label).Ceil()
+	d.Dot = fixed.P((w-width)/2, h/2)
+	d.DrawString(label)
+	return img
+}
+
+func mediaType(contentType string) string {
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
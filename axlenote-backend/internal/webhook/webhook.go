@@ -0,0 +1,244 @@
+// Package webhook queues and delivers signed event notifications to external systems.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/axlenote/axlenote-backend/internal/repository"
+)
+
+// Event types. Subscribers filter on these; an empty filter receives everything.
+const (
+	FuelLogCreated       = "fuel_log.created"
+	FuelLogUpdated       = "fuel_log.updated"
+	FuelLogDeleted       = "fuel_log.deleted"
+	ServiceRecordCreated = "service_record.created"
+	ServiceRecordUpdated = "service_record.updated"
+	ServiceRecordDeleted = "service_record.deleted"
+	ExpenseCreated       = "expense.created"
+	ExpenseUpdated       = "expense.updated"
+	ExpenseDeleted       = "expense.deleted"
+	ReminderCreated      = "reminder.created"
+	ReminderUpdated      = "reminder.updated"
+	ReminderCompleted    = "reminder.completed"
+	ReminderDeleted      = "reminder.deleted"
+
+	OdometerReadingCreated = "odometer_reading.created"
+
+	// Ping is sent by the test endpoint and is delivered regardless of filters.
+	Ping = "ping"
+)
+
+// Events lists the event types that can be subscribed to.
+var Events = []string{
+	FuelLogCreated, FuelLogUpdated, FuelLogDeleted,
+	ServiceRecordCreated, ServiceRecordUpdated, ServiceRecordDeleted,
+	ExpenseCreated, ExpenseUpdated, ExpenseDeleted,
+	ReminderCreated, ReminderUpdated, ReminderCompleted, ReminderDeleted,
+	OdometerReadingCreated,
+}
+
+// Delivery statuses.
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// Request headers sent with every delivery.
+const (
+	HeaderEvent     = "X-AxleNote-Event"
+	HeaderDelivery  = "X-AxleNote-Delivery"
+	HeaderTimestamp = "X-AxleNote-Timestamp"
+	HeaderSignature = "X-AxleNote-Signature"
+)
+
+// Envelope is the JSON body of a delivery.
+type Envelope struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// ValidEvent reports whether name is an event type that can be subscribed to.
+func ValidEvent(name string) bool {
+	for _, e := range Events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload builds the JSON body for an event.
+func Payload(event string, data any) (json.RawMessage, error) {
+	return json.Marshal(Envelope{Event: event, OccurredAt: time.Now().UTC(), Data: data})
+}
+
+// Enqueue queues an event for every enabled webhook listening to it and returns how
+// many deliveries were queued. The payload is captured now, so later changes to the
+// record don't alter what is sent.
+func Enqueue(ctx context.Context, queries *repository.Queries, event string, data any) (int64, error) {
+	payload, err := Payload(event, data)
+	if err != nil {
+		return 0, fmt.Errorf("encode payload: %w", err)
+	}
+	return queries.EnqueueWebhookDeliveries(ctx, repository.EnqueueWebhookDeliveriesParams{
+		EventType: event,
+		Payload:   payload,
+	})
+}
+
+// Sign returns the signature header value for a body: an HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret. Receivers should recompute it and
+// reject old timestamps to prevent replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrPrivateAddress is returned for webhook URLs that resolve to a loopback, private or
+// link-local address while WEBHOOK_ALLOW_PRIVATE is false.
+var ErrPrivateAddress = errors.New("address is loopback, private or link-local")
+
+// allowPrivate reads WEBHOOK_ALLOW_PRIVATE. Private addresses are allowed unless it is
+// false, since AxleNote usually runs on the same network as the services it notifies.
+func allowPrivate() bool {
+	return os.Getenv("WEBHOOK_ALLOW_PRIVATE") != "false"
+}
+
+func private(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsUnspecified()
+}
+
+// CheckURL returns ErrPrivateAddress when private addresses are not allowed and the
+// URL's host is or resolves to one. Hosts that do not resolve yet are accepted; the
+// address is checked again on every delivery.
+func CheckURL(ctx context.Context, rawURL string) error {
+	if allowPrivate() {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if private(addr) {
+			return fmt.Errorf("%s: %w", u.Hostname(), ErrPrivateAddress)
+		}
+	}
+	return nil
+}
+
+// refusePrivate is a dialer control that stops connections to private addresses, so a
+// host re-pointed after it was registered, or a redirect, cannot reach them either.
+func refusePrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && private(addr) {
+		return fmt.Errorf("%s: %w", host, ErrPrivateAddress)
+	}
+	return nil
+}
+
+// Client sends deliveries over HTTP.
+type Client struct {
+	http        *http.Client
+	MaxAttempts int
+}
+
+// NewClient reads WEBHOOK_TIMEOUT_SECONDS (10), WEBHOOK_MAX_ATTEMPTS (8) and
+// WEBHOOK_ALLOW_PRIVATE (true).
+func NewClient() *Client {
+	timeout, err := strconv.Atoi(os.Getenv("WEBHOOK_TIMEOUT_SECONDS"))
+	if err != nil || timeout <= 0 {
+		timeout = 10
+	}
+	maxAttempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	if !allowPrivate() {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: refusePrivate}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = dialer.DialContext
+		client.Transport = transport
+	}
+	return &Client{
+		http:        client,
+		MaxAttempts: maxAttempts,
+	}
+}
+
+// Send posts a delivery to its webhook. Any 2xx response counts as success; the
+// response status is returned even when the delivery failed.
+func (c *Client) Send(ctx context.Context, hook repository.Webhook, d repository.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Url, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AxleNote-Webhook/1.0")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(int(d.ID)))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, d.Payload))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the wait before the next attempt after the given number of failed
+// attempts: 30s doubling each time up to 6h, with some jitter so retries to a
+// recovering endpoint are spread out.
+func Backoff(attempts int) time.Duration {
+	const (
+		base    = 30 * time.Second
+		maxWait = 6 * time.Hour
+	)
+	attempts = max(attempts, 1)
+	wait := maxWait
+	if attempts < 20 {
+		wait = min(base<<(attempts-1), maxWait)
+	}
+	return wait + rand.N(wait/10+1)
+}
@@ -17,7 +17,8 @@ export default function AddServiceModal({ isOpen, onClose, onSuccess, vehicleId,
         cost: 0.0,
         notes: '',
         service_type: 'maintenance',
-        document_url: ''
+        document_url: '',
+        completed_date: ''
     }
 
     const [formData, setFormData] = useState(defaultForm)
@@ -32,7 +33,8 @@ export default function AddServiceModal({ isOpen, onClose, onSuccess, vehicleId,
                 cost: initialData.cost,
                 notes: initialData.notes,
                 service_type: initialData.service_type,
-                document_url: initialData.document_url || ''
+                document_url: initialData.document_url || '',
+                completed_date: initialData.completed_date || ''
             })
         } else if (isOpen && !initialData) {
             setFormData(defaultForm)
@@ -103,6 +105,16 @@ export default function AddServiceModal({ isOpen, onClose, onSuccess, vehicleId,
                         />
                     </div>
                 </div>
+                <div>
+                    <label className="block text-sm font-medium text-neutral-500 dark:text-zinc-400 mb-1">Back on the road (optional)</label>
+                    <input
+                        type="date"
+                        min={formData.date}
+                        className="w-full rounded-xl bg-neutral-50 dark:bg-zinc-950 border border-neutral-200 dark:border-white/5 px-4 py-2 text-neutral-900 dark:text-white focus:outline-none focus:ring-2 focus:ring-violet-500/50"
+                        value={formData.completed_date}
+                        onChange={e => setFormData({ ...formData, completed_date: e.target.value })}
+                    />
+                </div>
                 <div className="grid grid-cols-2 gap-4">
                     <div>
                         <label className="block text-sm font-medium text-neutral-500 dark:text-zinc-400 mb-1">Cost</label>
@@ -20,6 +20,7 @@ export interface ServiceRecord {
     notes: string
     service_type: string
     document_url?: string
+    completed_date?: string
 }
 
 export interface FuelLog {